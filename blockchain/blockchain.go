@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -29,9 +31,13 @@ const (
 	defaultCacheSize int = 100
 )
 
+// DefaultBlocksCacheSize is the default number of canonical full blocks kept in the blocks cache
+const DefaultBlocksCacheSize int = 64
+
 var (
 	ErrNoBlock              = errors.New("no block data passed in")
 	ErrParentNotFound       = errors.New("parent block not found")
+	ErrMissingParentState   = errors.New("parent block state is missing")
 	ErrInvalidParentHash    = errors.New("parent block hash is invalid")
 	ErrParentHashMismatch   = errors.New("invalid parent block hash")
 	ErrInvalidBlockSequence = errors.New("invalid block sequence")
@@ -41,6 +47,9 @@ var (
 	ErrInvalidStateRoot     = errors.New("invalid block state root")
 	ErrInvalidGasUsed       = errors.New("invalid block gas used")
 	ErrInvalidReceiptsRoot  = errors.New("invalid block receipts root")
+	ErrBlockNotFound        = errors.New("block not found")
+	ErrBodyMissing          = errors.New("block body is missing")
+	ErrReorgTooDeep         = errors.New("reorg exceeds the maximum reorg depth")
 )
 
 // Blockchain is a blockchain reference
@@ -69,13 +78,40 @@ type Blockchain struct {
 	// any new fields from being added
 	receiptsCache *lru.Cache // LRU cache for the block receipts
 
+	// executionResultsCache is LRU cache of block execution results (block hash -> *executionResult),
+	// so that a block presented again (e.g. on re-sync) is not executed again on the same parent state
+	executionResultsCache *lru.Cache
+
+	// blocksCache is LRU cache of canonical full blocks (block number -> *types.Block),
+	// caching is disabled if it is nil. Cached blocks get invalidated when canonical chain changes.
+	blocksCache     *lru.Cache
+	blocksCacheLock sync.Mutex
+
 	currentHeader     atomic.Pointer[types.Header] // The current header
 	currentDifficulty atomic.Pointer[big.Int]      // The current difficulty of the chain (total difficulty)
 
 	stream *eventStream // Event subscriptions
 
+	reorgStream reorgStream // Reorg event subscriptions
+
 	gpAverage *gasPriceAverage // A reference to the average gas price
 
+	// strictBodyLookup indicates whether a missing block body is reported as ErrBodyMissing
+	// when a full block is requested, instead of returning a header-only block
+	strictBodyLookup atomic.Bool
+
+	// maxReorgDepth is the maximum number of canonical blocks which can be orphaned by a reorg,
+	// deeper reorgs are rejected (zero means that the reorg depth is not limited)
+	maxReorgDepth atomic.Uint64
+
+	// maxForks is the maximum number of tracked fork heads, the ones with the lowest total difficulty
+	// are evicted once it is exceeded (zero means that the number of forks is not limited)
+	maxForks atomic.Uint64
+
+	// maxForkDepth is the maximum number of blocks a tracked fork head can be behind the chain head,
+	// deeper fork heads are pruned (zero means that the fork depth is not limited)
+	maxForkDepth atomic.Uint64
+
 	writeLock sync.Mutex
 }
 
@@ -109,6 +145,12 @@ type BlockResult struct {
 	TotalGas uint64
 }
 
+// executionResult is the cached result of the block execution on top of the given parent state
+type executionResult struct {
+	parentRoot types.Hash
+	result     *BlockResult
+}
+
 // updateGasPriceAvg updates the rolling average value of the gas price
 func (b *Blockchain) updateGasPriceAvg(newValues []*big.Int) {
 	b.gpAverage.Lock()
@@ -239,9 +281,119 @@ func (b *Blockchain) initCaches(size int) error {
 		return fmt.Errorf("unable to create receipts cache, %w", err)
 	}
 
+	b.executionResultsCache, err = lru.New(size)
+	if err != nil {
+		return fmt.Errorf("unable to create execution results cache, %w", err)
+	}
+
+	return b.SetBlocksCacheSize(DefaultBlocksCacheSize)
+}
+
+// SetBlocksCacheSize sets the number of canonical full blocks which are cached
+// by block number (zero disables the cache). Previously cached blocks are dropped.
+func (b *Blockchain) SetBlocksCacheSize(size int) error {
+	var (
+		blocksCache *lru.Cache
+		err         error
+	)
+
+	if size > 0 {
+		blocksCache, err = lru.New(size)
+		if err != nil {
+			return fmt.Errorf("unable to create blocks cache, %w", err)
+		}
+	}
+
+	b.blocksCacheLock.Lock()
+	b.blocksCache = blocksCache
+	b.blocksCacheLock.Unlock()
+
 	return nil
 }
 
+// getCachedBlock returns a copy of the cached canonical full block with the given number,
+// so that the cached block can not be modified by the caller
+func (b *Blockchain) getCachedBlock(number uint64) (*types.Block, bool) {
+	b.blocksCacheLock.Lock()
+	defer b.blocksCacheLock.Unlock()
+
+	if b.blocksCache == nil {
+		return nil, false
+	}
+
+	cached, ok := b.blocksCache.Get(number)
+	if !ok {
+		return nil, false
+	}
+
+	block, ok := cached.(*types.Block)
+	if !ok {
+		return nil, false
+	}
+
+	return block.Copy(), true
+}
+
+// cacheBlock adds a copy of the given full block to the blocks cache, if it is still the canonical one
+// for its number (the given block can still be modified by the caller)
+func (b *Blockchain) cacheBlock(block *types.Block) {
+	b.blocksCacheLock.Lock()
+	defer b.blocksCacheLock.Unlock()
+
+	if b.blocksCache == nil {
+		return
+	}
+
+	// canonical chain could have changed since the block was read
+	if hash, ok := b.db.ReadCanonicalHash(block.Number()); !ok || hash != block.Hash() {
+		return
+	}
+
+	b.blocksCache.Add(block.Number(), block.Copy())
+}
+
+// invalidateCachedBlocks removes blocks with the given numbers from the blocks cache
+func (b *Blockchain) invalidateCachedBlocks(headers ...*types.Header) {
+	b.blocksCacheLock.Lock()
+	defer b.blocksCacheLock.Unlock()
+
+	if b.blocksCache == nil {
+		return
+	}
+
+	for _, header := range headers {
+		b.blocksCache.Remove(header.Number)
+	}
+}
+
+// getCachedExecutionResult returns the cached execution result of the given block,
+// if the block was executed on top of the state with the given parent root
+func (b *Blockchain) getCachedExecutionResult(header *types.Header, parentRoot types.Hash) (*BlockResult, bool) {
+	cached, ok := b.executionResultsCache.Get(header.Hash)
+	if !ok {
+		return nil, false
+	}
+
+	execResult, ok := cached.(*executionResult)
+	if !ok || execResult.parentRoot != parentRoot {
+		return nil, false
+	}
+
+	// cached result is reused only if its state root is the one of the block
+	if execResult.result.Root != header.StateRoot {
+		return nil, false
+	}
+
+	return execResult.result, true
+}
+
+// invalidateExecutionResults removes execution results of the given blocks from the execution results cache
+func (b *Blockchain) invalidateExecutionResults(headers ...*types.Header) {
+	for _, header := range headers {
+		b.executionResultsCache.Remove(header.Hash)
+	}
+}
+
 // ComputeGenesis computes the genesis hash, and updates the blockchain reference
 func (b *Blockchain) ComputeGenesis() error {
 	// try to write the genesis block
@@ -478,6 +630,8 @@ func (b *Blockchain) advanceHead(newHeader *types.Header) (*big.Int, error) {
 		return nil, err
 	}
 
+	b.invalidateCachedBlocks(newHeader)
+
 	// Check if there was a parent difficulty
 	parentTD := big.NewInt(0)
 
@@ -512,6 +666,42 @@ func (b *Blockchain) GetBodyByHash(hash types.Hash) (*types.Body, bool) {
 	return b.readBody(hash)
 }
 
+// MissingBodyError is returned by GetBodiesByHashes, identifying the first requested body missing in storage
+type MissingBodyError struct {
+	Hash types.Hash
+}
+
+// Error returns the error message
+func (e *MissingBodyError) Error() string {
+	return fmt.Sprintf("%s: hash %s", ErrBodyMissing, e.Hash)
+}
+
+// Unwrap returns ErrBodyMissing, so that the error can be checked with errors.Is
+func (e *MissingBodyError) Unwrap() error {
+	return ErrBodyMissing
+}
+
+// GetBodiesByHashes returns the bodies of the given hashes, in the same order as the hashes.
+// If a body is not stored, *MissingBodyError for the first missing hash is returned.
+func (b *Blockchain) GetBodiesByHashes(hashes []types.Hash) ([]*types.Body, error) {
+	bodies := make([]*types.Body, len(hashes))
+
+	for i, hash := range hashes {
+		body, err := b.loadBody(hash)
+		if err != nil {
+			if errors.Is(err, storage.ErrNotFound) {
+				return nil, &MissingBodyError{Hash: hash}
+			}
+
+			return nil, fmt.Errorf("failed to read body %s: %w", hash, err)
+		}
+
+		bodies[i] = body
+	}
+
+	return bodies, nil
+}
+
 // GetHeaderByHash returns the header by his hash
 func (b *Blockchain) GetHeaderByHash(hash types.Hash) (*types.Header, bool) {
 	return b.readHeader(hash)
@@ -519,40 +709,66 @@ func (b *Blockchain) GetHeaderByHash(hash types.Hash) (*types.Header, bool) {
 
 // readHeader Returns the header using the hash
 func (b *Blockchain) readHeader(hash types.Hash) (*types.Header, bool) {
+	header, err := b.loadHeader(hash)
+	if err != nil {
+		return nil, false
+	}
+
+	return header, true
+}
+
+// loadHeader returns the header using the hash, either from the headers cache or from the DB.
+// ErrBlockNotFound is returned if the header is not stored, while other storage errors are propagated
+func (b *Blockchain) loadHeader(hash types.Hash) (*types.Header, error) {
 	// Try to find a hit in the headers cache
 	h, ok := b.headersCache.Get(hash)
 	if ok {
 		// Hit, return the3 header
 		header, ok := h.(*types.Header)
 		if !ok {
-			return nil, false
+			return nil, fmt.Errorf("invalid type of cached header %s", hash)
 		}
 
-		return header, true
+		return header, nil
 	}
 
 	// Cache miss, load it from the DB
 	hh, err := b.db.ReadHeader(hash)
 	if err != nil {
-		return nil, false
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("%w: header %s", ErrBlockNotFound, hash)
+		}
+
+		return nil, fmt.Errorf("failed to read header %s: %w", hash, err)
 	}
 
 	// Compute the header hash and update the cache
 	hh.ComputeHash()
 	b.headersCache.Add(hash, hh)
 
-	return hh, true
+	return hh, nil
 }
 
 // readBody reads the block's body, using the block hash
 func (b *Blockchain) readBody(hash types.Hash) (*types.Body, bool) {
-	bb, err := b.db.ReadBody(hash)
+	bb, err := b.loadBody(hash)
 	if err != nil {
 		b.logger.Error("failed to read body", "err", err)
 
 		return nil, false
 	}
 
+	return bb, true
+}
+
+// loadBody returns the body using the hash from the DB.
+// storage.ErrNotFound is returned if the body is not stored, while other storage errors are propagated
+func (b *Blockchain) loadBody(hash types.Hash) (*types.Body, error) {
+	bb, err := b.db.ReadBody(hash)
+	if err != nil {
+		return nil, err
+	}
+
 	// To return from field in the transactions of the past blocks
 	if updated := b.recoverFromFieldsInTransactions(bb.Transactions); updated {
 		if err := b.db.WriteBody(hash, bb); err != nil {
@@ -560,7 +776,7 @@ func (b *Blockchain) readBody(hash types.Hash) (*types.Body, bool) {
 		}
 	}
 
-	return bb, true
+	return bb, nil
 }
 
 // readTotalDifficulty reads the total difficulty associated with the hash
@@ -604,6 +820,22 @@ func (b *Blockchain) GetHeaderByNumber(n uint64) (*types.Header, bool) {
 	return h, true
 }
 
+// HeaderByNumber returns the header using the block number.
+// Unlike GetHeaderByNumber, it distinguishes a missing block (ErrBlockNotFound) from a storage failure
+func (b *Blockchain) HeaderByNumber(n uint64) (*types.Header, error) {
+	hash, ok := b.db.ReadCanonicalHash(n)
+	if !ok {
+		return nil, fmt.Errorf("%w: canonical hash of block %d", ErrBlockNotFound, n)
+	}
+
+	header, err := b.loadHeader(hash)
+	if err != nil {
+		return nil, fmt.Errorf("block %d: %w", n, err)
+	}
+
+	return header, nil
+}
+
 // WriteHeaders writes an array of headers
 func (b *Blockchain) WriteHeaders(headers []*types.Header) error {
 	return b.WriteHeadersWithBodies(headers)
@@ -799,7 +1031,8 @@ func (br *BlockResult) verifyBlockResult(referenceBlock *types.Block) error {
 
 	// Make sure the gas used is valid
 	if br.TotalGas != referenceBlock.Header.GasUsed {
-		return ErrInvalidGasUsed
+		return fmt.Errorf("%w: have %d, want %d, %s", ErrInvalidGasUsed, br.TotalGas,
+			referenceBlock.Header.GasUsed, describeGasUsedDivergence(br.Receipts, referenceBlock.Header.GasUsed))
 	}
 
 	// Make sure the receipts root matches up
@@ -811,6 +1044,30 @@ func (br *BlockResult) verifyBlockResult(referenceBlock *types.Block) error {
 	return nil
 }
 
+// describeGasUsedDivergence describes the gas used by each transaction, and names the first transaction
+// whose cumulative gas used exceeds the expected block gas used
+func describeGasUsedDivergence(receipts []*types.Receipt, expectedGasUsed uint64) string {
+	var (
+		divergence string
+		breakdown  = make([]string, len(receipts))
+	)
+
+	for i, receipt := range receipts {
+		breakdown[i] = fmt.Sprintf("%d: %d (cumulative %d)", i, receipt.GasUsed, receipt.CumulativeGasUsed)
+
+		if divergence == "" && receipt.CumulativeGasUsed > expectedGasUsed {
+			divergence = fmt.Sprintf("cumulative gas used exceeds the expected one at transaction %d (hash %s)",
+				i, receipt.TxHash)
+		}
+	}
+
+	if divergence == "" {
+		divergence = "cumulative gas used of all the transactions is below the expected one"
+	}
+
+	return fmt.Sprintf("%s, gas used by transactions: [%s]", divergence, strings.Join(breakdown, ", "))
+}
+
 // executeBlockTransactions executes the transactions in the block locally,
 // and reports back the block execution result
 func (b *Blockchain) executeBlockTransactions(block *types.Block) (*BlockResult, error) {
@@ -821,6 +1078,14 @@ func (b *Blockchain) executeBlockTransactions(block *types.Block) (*BlockResult,
 		return nil, ErrParentNotFound
 	}
 
+	if blockResult, ok := b.getCachedExecutionResult(header, parent.StateRoot); ok {
+		b.logger.Debug("block already executed, skipping execution", "number", header.Number, "hash", header.Hash)
+
+		b.receiptsCache.Add(header.Hash, blockResult.Receipts)
+
+		return blockResult, nil
+	}
+
 	blockCreator, err := b.consensus.GetBlockCreator(header)
 	if err != nil {
 		return nil, err
@@ -828,11 +1093,18 @@ func (b *Blockchain) executeBlockTransactions(block *types.Block) (*BlockResult,
 
 	txn, err := b.executor.ProcessBlock(parent.StateRoot, block, blockCreator)
 	if err != nil {
+		// parent header is known, but its state is not, so the state needs to be replayed
+		// rather than headers being synced
+		if errors.Is(err, state.ErrStateNotFound) {
+			return nil, fmt.Errorf("%w: parent %s (%d), state root %s",
+				ErrMissingParentState, parent.Hash, parent.Number, parent.StateRoot)
+		}
+
 		return nil, err
 	}
 
 	if err := b.consensus.PreCommitState(block, txn); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to pre-commit state of block %d: %w", header.Number, err)
 	}
 
 	_, root, err := txn.Commit()
@@ -843,11 +1115,15 @@ func (b *Blockchain) executeBlockTransactions(block *types.Block) (*BlockResult,
 	// Append the receipts to the receipts cache
 	b.receiptsCache.Add(header.Hash, txn.Receipts())
 
-	return &BlockResult{
+	blockResult := &BlockResult{
 		Root:     root,
 		Receipts: txn.Receipts(),
 		TotalGas: txn.TotalGas(),
-	}, nil
+	}
+
+	b.executionResultsCache.Add(header.Hash, &executionResult{parentRoot: parent.StateRoot, result: blockResult})
+
+	return blockResult, nil
 }
 
 // WriteFullBlock writes a single block to the local blockchain.
@@ -1183,6 +1459,10 @@ func (b *Blockchain) GetHashByNumber(blockNumber uint64) types.Hash {
 // dispatchEvent pushes a new event to the stream
 func (b *Blockchain) dispatchEvent(evnt *Event) {
 	b.stream.push(evnt)
+
+	if evnt.Type == EventReorg {
+		b.reorgStream.push(newReorgEvent(evnt))
+	}
 }
 
 // writeHeaderImpl writes a block and the data, assumes the genesis is already set
@@ -1195,10 +1475,6 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 		return b.writeCanonicalHeader(evnt, header)
 	}
 
-	if err := b.db.WriteHeader(header); err != nil {
-		return err
-	}
-
 	currentTD, ok := b.readTotalDifficulty(currentHeader.Hash)
 	if !ok {
 		return errors.New("failed to get header difficulty")
@@ -1214,22 +1490,40 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 		)
 	}
 
+	incomingTD := big.NewInt(0).Add(parentTD, big.NewInt(0).SetUint64(header.Difficulty))
+	isReorg := chooseFork(currentTD, incomingTD) == ForkChoiceReorg
+
+	// too deep reorg is rejected before anything gets written
+	if isReorg {
+		if err := b.checkReorgDepth(currentHeader, header); err != nil {
+			if errors.Is(err, ErrReorgTooDeep) {
+				b.logger.Warn(
+					"rejected fork, reorg exceeds the maximum reorg depth",
+					"head", currentHeader.Number,
+					"headHash", currentHeader.Hash,
+					"fork", header.Number,
+					"forkHash", header.Hash,
+					"maxReorgDepth", b.maxReorgDepth.Load(),
+				)
+			}
+
+			return err
+		}
+	}
+
+	if err := b.db.WriteHeader(header); err != nil {
+		return err
+	}
+
 	// Write the difficulty
-	if err := b.db.WriteTotalDifficulty(
-		header.Hash,
-		big.NewInt(0).Add(
-			parentTD,
-			big.NewInt(0).SetUint64(header.Difficulty),
-		),
-	); err != nil {
+	if err := b.db.WriteTotalDifficulty(header.Hash, incomingTD); err != nil {
 		return err
 	}
 
 	// Update the headers cache
 	b.headersCache.Add(header.Hash, header)
 
-	incomingTD := big.NewInt(0).Add(parentTD, big.NewInt(0).SetUint64(header.Difficulty))
-	if incomingTD.Cmp(currentTD) > 0 {
+	if isReorg {
 		// new block has higher difficulty, reorg the chain
 		if err := b.handleReorg(evnt, currentHeader, header); err != nil {
 			return err
@@ -1239,7 +1533,7 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 		evnt.AddOldHeader(header)
 		evnt.Type = EventFork
 
-		if err := b.writeFork(header); err != nil {
+		if err := b.writeFork(header, currentHeader); err != nil {
 			return err
 		}
 	}
@@ -1247,8 +1541,136 @@ func (b *Blockchain) writeHeaderImpl(evnt *Event, header *types.Header) error {
 	return nil
 }
 
-// writeFork writes the new header forks to the DB
-func (b *Blockchain) writeFork(header *types.Header) error {
+// ForkChoiceDecision is the outcome of the fork choice rule for a header
+type ForkChoiceDecision int
+
+const (
+	ForkChoiceExtend ForkChoiceDecision = iota // Header extends the current chain head
+	ForkChoiceFork                             // Header is written as a fork, since it has no higher difficulty
+	ForkChoiceReorg                            // Header has higher difficulty and reorganizes the chain
+	ForkChoiceReject                           // Header can not be written (unknown parent or too deep reorg)
+)
+
+// String returns the name of the fork choice decision
+func (d ForkChoiceDecision) String() string {
+	switch d {
+	case ForkChoiceExtend:
+		return "extend"
+	case ForkChoiceFork:
+		return "fork"
+	case ForkChoiceReorg:
+		return "reorg"
+	case ForkChoiceReject:
+		return "reject"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(d))
+	}
+}
+
+// ForkChoice holds the fork choice decision for a header, along with the compared total difficulties
+type ForkChoice struct {
+	Decision ForkChoiceDecision
+
+	// CurrentTD is the total difficulty of the current chain head
+	CurrentTD *big.Int
+
+	// IncomingTD is the total difficulty of the header (nil if the parent of the header is unknown)
+	IncomingTD *big.Int
+}
+
+// ForkChoice returns the decision writeHeaderImpl would make for the given header,
+// without writing anything to the chain
+func (b *Blockchain) ForkChoice(header *types.Header) (*ForkChoice, error) {
+	currentHeader := b.Header()
+
+	currentTD, ok := b.readTotalDifficulty(currentHeader.Hash)
+	if !ok {
+		return nil, errors.New("failed to get header difficulty")
+	}
+
+	choice := &ForkChoice{
+		Decision:  ForkChoiceReject,
+		CurrentTD: new(big.Int).Set(currentTD),
+	}
+
+	parentTD, ok := b.readTotalDifficulty(header.ParentHash)
+	if !ok {
+		return choice, nil
+	}
+
+	choice.IncomingTD = big.NewInt(0).Add(parentTD, big.NewInt(0).SetUint64(header.Difficulty))
+
+	if header.ParentHash == currentHeader.Hash {
+		choice.Decision = ForkChoiceExtend
+	} else {
+		choice.Decision = chooseFork(currentTD, choice.IncomingTD)
+	}
+
+	if choice.Decision == ForkChoiceReorg {
+		if err := b.checkReorgDepth(currentHeader, header); err != nil {
+			if !errors.Is(err, ErrReorgTooDeep) {
+				return nil, err
+			}
+
+			choice.Decision = ForkChoiceReject
+		}
+	}
+
+	return choice, nil
+}
+
+// chooseFork decides whether a header, which does not extend the current chain head,
+// reorganizes the chain or is written as a fork, based on the total difficulties
+func chooseFork(currentTD, incomingTD *big.Int) ForkChoiceDecision {
+	if incomingTD.Cmp(currentTD) > 0 {
+		return ForkChoiceReorg
+	}
+
+	return ForkChoiceFork
+}
+
+// checkReorgDepth returns an error if reorg to the new chain head would orphan more canonical blocks
+// than the maximum reorg depth allows. Headers are only read while walking back to the common ancestor
+// (the new chain head itself does not need to be written), and the walk stops once the limit is exceeded.
+func (b *Blockchain) checkReorgDepth(oldChainHead, newChainHead *types.Header) error {
+	maxDepth := b.maxReorgDepth.Load()
+	if maxDepth == 0 {
+		return nil
+	}
+
+	oldHeader, newHeader := oldChainHead, newChainHead
+
+	for oldHeader.Hash != newHeader.Hash {
+		if newHeader.Number > oldHeader.Number {
+			parent, ok := b.readHeader(newHeader.ParentHash)
+			if !ok {
+				return fmt.Errorf("header '%s' not found", newHeader.ParentHash.String())
+			}
+
+			newHeader = parent
+
+			continue
+		}
+
+		parent, ok := b.readHeader(oldHeader.ParentHash)
+		if !ok {
+			return fmt.Errorf("header '%s' not found", oldHeader.ParentHash.String())
+		}
+
+		oldHeader = parent
+
+		// common ancestor is not above the reached old chain header
+		if oldChainHead.Number-oldHeader.Number > maxDepth {
+			return fmt.Errorf("%w: fork %s (%d) orphans more than %d blocks",
+				ErrReorgTooDeep, newChainHead.Hash, newChainHead.Number, maxDepth)
+		}
+	}
+
+	return nil
+}
+
+// writeFork writes the new header forks to the DB, given the chain head they fork from
+func (b *Blockchain) writeFork(header, head *types.Header) error {
 	forks, err := b.db.ReadForks()
 	if err != nil {
 		if errors.Is(err, storage.ErrNotFound) {
@@ -1267,6 +1689,8 @@ func (b *Blockchain) writeFork(header *types.Header) error {
 	}
 
 	newForks = append(newForks, header.Hash)
+	newForks = b.pruneForks(newForks, head)
+
 	if err := b.db.WriteForks(newForks); err != nil {
 		return err
 	}
@@ -1274,6 +1698,70 @@ func (b *Blockchain) writeFork(header *types.Header) error {
 	return nil
 }
 
+// pruneForks removes the fork heads which are too deep behind the given chain head,
+// and the ones with the lowest total difficulty if there are more fork heads than allowed
+func (b *Blockchain) pruneForks(forks []types.Hash, head *types.Header) []types.Hash {
+	maxForks, maxDepth := b.maxForks.Load(), b.maxForkDepth.Load()
+	if maxDepth == 0 && (maxForks == 0 || uint64(len(forks)) <= maxForks) {
+		return forks
+	}
+
+	type forkHead struct {
+		hash types.Hash
+		td   *big.Int
+	}
+
+	forkHeads := make([]forkHead, 0, len(forks))
+
+	for _, fork := range forks {
+		header, ok := b.readHeader(fork)
+		if !ok {
+			// fork head is not known anymore, so there is nothing to track
+			continue
+		}
+
+		if maxDepth > 0 && head.Number > header.Number && head.Number-header.Number > maxDepth {
+			continue
+		}
+
+		td, ok := b.readTotalDifficulty(fork)
+		if !ok {
+			td = big.NewInt(0)
+		}
+
+		forkHeads = append(forkHeads, forkHead{hash: fork, td: td})
+	}
+
+	if maxForks > 0 && uint64(len(forkHeads)) > maxForks {
+		// keep the strongest forks, evicting the ones with the lowest total difficulty
+		sort.SliceStable(forkHeads, func(i, j int) bool {
+			return forkHeads[i].td.Cmp(forkHeads[j].td) > 0
+		})
+
+		forkHeads = forkHeads[:maxForks]
+	}
+
+	// retained forks are kept in the order they were tracked in
+	retained := make(map[types.Hash]struct{}, len(forkHeads))
+	for _, f := range forkHeads {
+		retained[f.hash] = struct{}{}
+	}
+
+	prunedForks := make([]types.Hash, 0, len(forkHeads))
+
+	for _, fork := range forks {
+		if _, ok := retained[fork]; ok {
+			prunedForks = append(prunedForks, fork)
+		}
+	}
+
+	if pruned := len(forks) - len(prunedForks); pruned > 0 {
+		b.logger.Debug("pruned tracked forks", "pruned", pruned, "retained", len(prunedForks))
+	}
+
+	return prunedForks
+}
+
 // handleReorg handles a reorganization event
 func (b *Blockchain) handleReorg(
 	evnt *Event,
@@ -1320,6 +1808,11 @@ func (b *Blockchain) handleReorg(
 		}
 
 		oldChain = append(oldChain, oldHeader)
+
+		// new chain headers below the old head height become canonical as well (common ancestor excluded)
+		if oldHeader.Hash != newHeader.Hash {
+			newChain = append(newChain, newHeader)
+		}
 	}
 
 	for _, b := range oldChain[:len(oldChain)-1] {
@@ -1333,7 +1826,7 @@ func (b *Blockchain) handleReorg(
 		evnt.AddNewHeader(b)
 	}
 
-	if err := b.writeFork(oldChainHead); err != nil {
+	if err := b.writeFork(oldChainHead, newChainHead); err != nil {
 		return fmt.Errorf("failed to write the old header as fork: %w", err)
 	}
 
@@ -1344,6 +1837,14 @@ func (b *Blockchain) handleReorg(
 		}
 	}
 
+	// blocks of the old chain are not canonical anymore
+	b.invalidateCachedBlocks(oldChain...)
+	b.invalidateCachedBlocks(oldChainHead)
+
+	// orphaned blocks are executed again if they are presented again
+	b.invalidateExecutionResults(oldChain[:len(oldChain)-1]...)
+	b.invalidateExecutionResults(oldChainHead)
+
 	diff, err := b.advanceHead(newChainHead)
 	if err != nil {
 		return err
@@ -1351,6 +1852,7 @@ func (b *Blockchain) handleReorg(
 
 	// Set the event type and difficulty
 	evnt.Type = EventReorg
+	evnt.CommonAncestor = oldChain[len(oldChain)-1].Copy()
 	evnt.SetDifficulty(diff)
 
 	return nil
@@ -1376,6 +1878,10 @@ func (b *Blockchain) GetBlockByHash(hash types.Hash, full bool) (*types.Block, b
 		return block, true
 	}
 
+	if cached, ok := b.getCachedBlock(header.Number); ok && cached.Hash() == hash {
+		return cached, true
+	}
+
 	// Load the entire block body
 	body, ok := b.readBody(hash)
 	if !ok {
@@ -1389,8 +1895,68 @@ func (b *Blockchain) GetBlockByHash(hash types.Hash, full bool) (*types.Block, b
 	return block, true
 }
 
+// SetStrictBodyLookup sets whether LookupBlockByHash reports a missing block body as ErrBodyMissing
+// (strict mode), or returns a header-only block in that case (lenient mode, which is the default one)
+func (b *Blockchain) SetStrictBodyLookup(strict bool) {
+	b.strictBodyLookup.Store(strict)
+}
+
+// SetForkTrackingLimits sets the maximum number of tracked fork heads, and the maximum number of blocks
+// a tracked fork head can be behind the chain head. Fork heads with the lowest total difficulty are evicted
+// when there are too many of them, and too deep ones are pruned. Zero disables the respective limit (the default)
+func (b *Blockchain) SetForkTrackingLimits(maxForks, maxDepth uint64) {
+	b.maxForks.Store(maxForks)
+	b.maxForkDepth.Store(maxDepth)
+}
+
+// SetMaxReorgDepth sets the maximum number of canonical blocks which can be orphaned by a reorg.
+// Deeper reorgs are rejected and the current head is kept. Zero depth disables the limit (the default one)
+func (b *Blockchain) SetMaxReorgDepth(depth uint64) {
+	b.maxReorgDepth.Store(depth)
+}
+
+// LookupBlockByHash returns the block using the provided hash.
+// If full block is requested and its body is missing in storage, ErrBodyMissing is returned
+// in strict body lookup mode (so that the caller can fetch it), while header-only block is returned otherwise
+func (b *Blockchain) LookupBlockByHash(hash types.Hash, full bool) (*types.Block, error) {
+	header, ok := b.readHeader(hash)
+	if !ok {
+		return nil, fmt.Errorf("%w: hash %s", ErrBlockNotFound, hash)
+	}
+
+	block := &types.Block{
+		Header: header,
+	}
+
+	if !full || header.Number == 0 {
+		return block, nil
+	}
+
+	body, ok := b.readBody(hash)
+	if !ok {
+		if b.strictBodyLookup.Load() {
+			return nil, fmt.Errorf("%w: block %d (hash %s)", ErrBodyMissing, header.Number, hash)
+		}
+
+		return block, nil
+	}
+
+	block.Transactions = body.Transactions
+	block.Uncles = body.Uncles
+
+	return block, nil
+}
+
 // GetBlockByNumber returns the block using the block number
 func (b *Blockchain) GetBlockByNumber(blockNumber uint64, full bool) (*types.Block, bool) {
+	if block, ok := b.getCachedBlock(blockNumber); ok {
+		if !full {
+			return &types.Block{Header: block.Header}, true
+		}
+
+		return block, true
+	}
+
 	blockHash, ok := b.db.ReadCanonicalHash(blockNumber)
 	if !ok {
 		return nil, false
@@ -1401,7 +1967,12 @@ func (b *Blockchain) GetBlockByNumber(blockNumber uint64, full bool) (*types.Blo
 		full = false
 	}
 
-	return b.GetBlockByHash(blockHash, full)
+	block, ok := b.GetBlockByHash(blockHash, full)
+	if ok && full {
+		b.cacheBlock(block)
+	}
+
+	return block, ok
 }
 
 // Close closes the DB connection
@@ -8,10 +8,12 @@ import (
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/hashicorp/go-hclog"
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/0xPolygon/polygon-edge/blockchain/storage"
 	"github.com/0xPolygon/polygon-edge/blockchain/storage/memory"
@@ -543,6 +545,211 @@ func TestForkUnknownParents(t *testing.T) {
 	assert.Error(t, b.WriteHeadersWithBodies([]*types.Header{h1[12]}))
 }
 
+func TestBlockchain_MaxReorgDepth(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(10)
+	b := NewTestBlockchain(t, headers)
+	b.SetMaxReorgDepth(3)
+
+	// a longer fork starting after block 4 would orphan 5 canonical blocks
+	forkHeaders := AppendNewTestheadersWithSeed(headers[:5], 10, 1)
+
+	var rejected *types.Header
+
+	for _, header := range forkHeaders[5:] {
+		if err := b.WriteHeaders([]*types.Header{header}); err != nil {
+			require.ErrorIs(t, err, ErrReorgTooDeep)
+
+			rejected = header
+
+			break
+		}
+	}
+
+	require.NotNil(t, rejected)
+
+	// nothing is written for the rejected header
+	_, ok := b.GetHeaderByHash(rejected.Hash)
+	require.False(t, ok)
+
+	_, ok = b.GetTD(rejected.Hash)
+	require.False(t, ok)
+
+	// the current head is kept
+	require.Equal(t, headers[9].Hash, b.Header().Hash)
+
+	header, ok := b.GetHeaderByNumber(5)
+	require.True(t, ok)
+	require.Equal(t, headers[5].Hash, header.Hash)
+
+	// a reorg within the limit is accepted
+	b = NewTestBlockchain(t, headers)
+	b.SetMaxReorgDepth(5)
+
+	require.NoError(t, b.WriteHeaders(forkHeaders[5:]))
+	require.Equal(t, forkHeaders[len(forkHeaders)-1].Hash, b.Header().Hash)
+}
+
+func TestBlockchain_ForkTrackingLimits(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(10)
+
+	// fork heads at heights 4, 6 and 8, all with lower total difficulty than the chain head
+	forkBases := []int{3, 5, 7}
+	forkHeaders := make([][]*types.Header, len(forkBases))
+	forkHeads := make([]*types.Header, len(forkBases))
+
+	for i, base := range forkBases {
+		forkHeaders[i] = AppendNewTestheadersWithSeed(headers[:base], 2, uint64(i+1))[base:]
+		forkHeads[i] = forkHeaders[i][len(forkHeaders[i])-1]
+	}
+
+	writeForks := func(b *Blockchain) {
+		t.Helper()
+
+		for _, fork := range forkHeaders {
+			require.NoError(t, b.WriteHeaders(fork))
+		}
+	}
+
+	t.Run("unlimited", func(t *testing.T) {
+		t.Parallel()
+
+		b := NewTestBlockchain(t, headers)
+		writeForks(b)
+
+		forks, err := b.GetForks()
+		require.NoError(t, err)
+		require.Equal(t, []types.Hash{forkHeads[0].Hash, forkHeads[1].Hash, forkHeads[2].Hash}, forks)
+	})
+
+	t.Run("exceeding the cap evicts the weakest fork", func(t *testing.T) {
+		t.Parallel()
+
+		b := NewTestBlockchain(t, headers)
+		b.SetForkTrackingLimits(2, 0)
+		writeForks(b)
+
+		forks, err := b.GetForks()
+		require.NoError(t, err)
+		require.Equal(t, []types.Hash{forkHeads[1].Hash, forkHeads[2].Hash}, forks)
+	})
+
+	t.Run("too deep forks are pruned", func(t *testing.T) {
+		t.Parallel()
+
+		b := NewTestBlockchain(t, headers)
+		b.SetForkTrackingLimits(0, 4)
+		writeForks(b)
+
+		// chain head is at height 9, so the fork head at height 4 is too deep
+		forks, err := b.GetForks()
+		require.NoError(t, err)
+		require.Equal(t, []types.Hash{forkHeads[1].Hash, forkHeads[2].Hash}, forks)
+	})
+}
+
+func TestBlockchain_ForkChoice(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(10)
+	b := NewTestBlockchain(t, headers)
+
+	// fork headers have the same difficulties as canonical headers of the same height
+	forkHeaders := AppendNewTestheadersWithSeed(headers[:5], 6, 1)
+	require.NoError(t, b.WriteHeaders(forkHeaders[5:10]))
+
+	currentTD, ok := b.GetTD(headers[9].Hash)
+	require.True(t, ok)
+
+	cases := []struct {
+		name       string
+		header     *types.Header
+		decision   ForkChoiceDecision
+		incomingTD *big.Int
+	}{
+		{
+			name:       "extend",
+			header:     AppendNewTestHeaders(headers, 1)[10],
+			decision:   ForkChoiceExtend,
+			incomingTD: big.NewInt(0).Add(currentTD, big.NewInt(10)),
+		},
+		{
+			name:       "fork with lower difficulty",
+			header:     forkHeaders[6],
+			decision:   ForkChoiceFork,
+			incomingTD: big.NewInt(0).Sub(currentTD, big.NewInt(7+8+9)),
+		},
+		{
+			name:       "fork with equal difficulty",
+			header:     forkHeaders[9],
+			decision:   ForkChoiceFork,
+			incomingTD: currentTD,
+		},
+		{
+			name:       "reorg",
+			header:     forkHeaders[10],
+			decision:   ForkChoiceReorg,
+			incomingTD: big.NewInt(0).Add(currentTD, big.NewInt(10)),
+		},
+		{
+			name:     "reject",
+			header:   NewTestHeadersWithSeed(nil, 3, 2)[2],
+			decision: ForkChoiceReject,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			choice, err := b.ForkChoice(c.header)
+			require.NoError(t, err)
+			require.Equal(t, c.decision, choice.Decision, choice.Decision.String())
+			require.Equal(t, currentTD, choice.CurrentTD)
+			require.Equal(t, c.incomingTD, choice.IncomingTD)
+		})
+	}
+
+	// fork choice does not change the chain
+	t.Cleanup(func() {
+		require.Equal(t, headers[9].Hash, b.Header().Hash)
+
+		_, ok := b.GetHeaderByHash(forkHeaders[10].Hash)
+		require.False(t, ok)
+	})
+}
+
+func TestBlockchain_ForkChoice_MaxReorgDepth(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(10)
+	b := NewTestBlockchain(t, headers)
+
+	// the fork starts after block 4, so the reorg would orphan 5 canonical blocks
+	forkHeaders := AppendNewTestheadersWithSeed(headers[:5], 6, 1)
+	require.NoError(t, b.WriteHeaders(forkHeaders[5:10]))
+
+	choice, err := b.ForkChoice(forkHeaders[10])
+	require.NoError(t, err)
+	require.Equal(t, ForkChoiceReorg, choice.Decision)
+
+	b.SetMaxReorgDepth(3)
+
+	choice, err = b.ForkChoice(forkHeaders[10])
+	require.NoError(t, err)
+	require.Equal(t, ForkChoiceReject, choice.Decision)
+
+	b.SetMaxReorgDepth(5)
+
+	choice, err = b.ForkChoice(forkHeaders[10])
+	require.NoError(t, err)
+	require.Equal(t, ForkChoiceReorg, choice.Decision)
+}
+
 func TestBlockchainWriteBody(t *testing.T) {
 	t.Parallel()
 
@@ -898,6 +1105,63 @@ func TestBlockchainReadBody(t *testing.T) {
 	assert.Equal(t, addr, readBody.Transactions[0].From)
 }
 
+func TestBlockchain_GetBodiesByHashes(t *testing.T) {
+	t.Parallel()
+
+	storage, err := memory.NewMemoryStorage(nil)
+	require.NoError(t, err)
+
+	b := &Blockchain{
+		logger: hclog.NewNullLogger(),
+		db:     storage,
+		txSigner: &mockSigner{
+			txFromByTxHash: map[types.Hash]types.Address{},
+		},
+	}
+
+	hashes := make([]types.Hash, 3)
+
+	for i := range hashes {
+		tx := &types.Transaction{Nonce: uint64(i), Value: big.NewInt(10), From: types.StringToAddress("1")}
+		tx.ComputeHash()
+
+		block := &types.Block{
+			Header:       &types.Header{Number: uint64(i + 1)},
+			Transactions: []*types.Transaction{tx},
+		}
+		block.Header.ComputeHash()
+
+		require.NoError(t, b.writeBody(block))
+
+		hashes[i] = block.Hash()
+	}
+
+	// bodies are returned in the requested order
+	bodies, err := b.GetBodiesByHashes([]types.Hash{hashes[2], hashes[0], hashes[1]})
+	require.NoError(t, err)
+	require.Len(t, bodies, 3)
+
+	for i, nonce := range []uint64{2, 0, 1} {
+		require.Equal(t, nonce, bodies[i].Transactions[0].Nonce)
+	}
+
+	// the first missing hash is reported
+	missingHashes := []types.Hash{types.StringToHash("0x1"), types.StringToHash("0x2")}
+
+	bodies, err = b.GetBodiesByHashes([]types.Hash{hashes[0], missingHashes[0], hashes[1], missingHashes[1]})
+	require.ErrorIs(t, err, ErrBodyMissing)
+	require.Nil(t, bodies)
+
+	var missingBodyErr *MissingBodyError
+
+	require.ErrorAs(t, err, &missingBodyErr)
+	require.Equal(t, missingHashes[0], missingBodyErr.Hash)
+
+	bodies, err = b.GetBodiesByHashes(nil)
+	require.NoError(t, err)
+	require.Empty(t, bodies)
+}
+
 func TestCalculateGasLimit(t *testing.T) {
 	tests := []struct {
 		name             string
@@ -1250,6 +1514,49 @@ func TestBlockchain_VerifyBlockBody(t *testing.T) {
 
 		_, err = blockchain.verifyBlockBody(block)
 		assert.ErrorIs(t, err, ErrParentNotFound)
+		assert.NotErrorIs(t, err, ErrMissingParentState)
+	})
+
+	t.Run("Invalid execution result - missing parent state", func(t *testing.T) {
+		t.Parallel()
+
+		// Set up the storage callback
+		storageCallback := func(storage *storage.MockStorage) {
+			// parent header is found
+			storage.HookReadHeader(func(hash types.Hash) (*types.Header, error) {
+				return emptyHeader, nil
+			})
+		}
+
+		executorCallback := func(executor *mockExecutor) {
+			// but its state is not
+			executor.HookProcessBlock(func(
+				hash types.Hash,
+				block *types.Block,
+				address types.Address,
+			) (*state.Transition, error) {
+				return nil, fmt.Errorf("%w at hash %s", state.ErrStateNotFound, hash)
+			})
+		}
+
+		blockchain, err := NewMockBlockchain(map[TestCallbackType]interface{}{
+			StorageCallback:  storageCallback,
+			ExecutorCallback: executorCallback,
+		})
+		if err != nil {
+			t.Fatalf("unable to instantiate new blockchain, %v", err)
+		}
+
+		block := &types.Block{
+			Header: &types.Header{
+				Sha3Uncles: types.EmptyUncleHash,
+				TxRoot:     types.EmptyRootHash,
+			},
+		}
+
+		_, err = blockchain.verifyBlockBody(block)
+		assert.ErrorIs(t, err, ErrMissingParentState)
+		assert.NotErrorIs(t, err, ErrParentNotFound)
 	})
 
 	t.Run("Invalid execution result - unable to fetch block creator", func(t *testing.T) {
@@ -1334,6 +1641,130 @@ func TestBlockchain_VerifyBlockBody(t *testing.T) {
 		_, err = blockchain.verifyBlockBody(block)
 		assert.ErrorIs(t, err, errUnableToExecute)
 	})
+
+	t.Run("Invalid execution result - unable to pre-commit state", func(t *testing.T) {
+		t.Parallel()
+
+		errPreCommitState := errors.New("unable to pre-commit state")
+
+		storageCallback := func(storage *storage.MockStorage) {
+			// This is used for parent fetching
+			storage.HookReadHeader(func(hash types.Hash) (*types.Header, error) {
+				return emptyHeader, nil
+			})
+		}
+
+		executorCallback := func(executor *mockExecutor) {
+			executor.HookProcessBlock(func(
+				hash types.Hash,
+				block *types.Block,
+				address types.Address,
+			) (*state.Transition, error) {
+				return &state.Transition{}, nil
+			})
+		}
+
+		verifierCallback := func(verifier *MockVerifier) {
+			verifier.HookPreCommitState(func(block *types.Block, txn *state.Transition) error {
+				return errPreCommitState
+			})
+		}
+
+		blockchain, err := NewMockBlockchain(map[TestCallbackType]interface{}{
+			StorageCallback:  storageCallback,
+			ExecutorCallback: executorCallback,
+			VerifierCallback: verifierCallback,
+		})
+		if err != nil {
+			t.Fatalf("unable to instantiate new blockchain, %v", err)
+		}
+
+		block := &types.Block{
+			Header: &types.Header{
+				Number:     5,
+				Sha3Uncles: types.EmptyUncleHash,
+				TxRoot:     types.EmptyRootHash,
+			},
+		}
+
+		_, err = blockchain.verifyBlockBody(block)
+		assert.ErrorIs(t, err, errPreCommitState)
+		assert.ErrorContains(t, err, "block 5")
+	})
+}
+
+func TestBlockchain_VerifyBlockBody_CachedExecutionResult(t *testing.T) {
+	t.Parallel()
+
+	parent := &types.Header{
+		Number:    0,
+		StateRoot: types.EmptyRootHash,
+	}
+	parent.ComputeHash()
+
+	config := &chain.Params{Forks: &chain.Forks{chain.Homestead: chain.NewFork(0)}}
+	stateExecutor := state.NewExecutor(config, itrie.NewState(itrie.NewMemoryStorage()), hclog.NewNullLogger())
+	stateExecutor.GetHash = func(*types.Header) state.GetHashByNumber {
+		return func(uint64) types.Hash { return types.ZeroHash }
+	}
+	executionsCount := 0
+
+	blockchain, err := NewMockBlockchain(map[TestCallbackType]interface{}{
+		StorageCallback: func(storage *storage.MockStorage) {
+			storage.HookReadHeader(func(hash types.Hash) (*types.Header, error) {
+				return parent, nil
+			})
+		},
+		ExecutorCallback: func(executor *mockExecutor) {
+			executor.HookProcessBlock(func(
+				parentRoot types.Hash,
+				block *types.Block,
+				blockCreator types.Address,
+			) (*state.Transition, error) {
+				executionsCount++
+
+				return stateExecutor.ProcessBlock(parentRoot, block, blockCreator)
+			})
+		},
+	})
+	require.NoError(t, err)
+
+	header := &types.Header{
+		Number:       1,
+		ParentHash:   parent.Hash,
+		Sha3Uncles:   types.EmptyUncleHash,
+		TxRoot:       types.EmptyRootHash,
+		ReceiptsRoot: types.EmptyRootHash,
+		StateRoot:    types.EmptyRootHash,
+	}
+	header.ComputeHash()
+
+	block := &types.Block{Header: header}
+
+	_, err = blockchain.verifyBlockBody(block)
+	require.NoError(t, err)
+	require.Equal(t, 1, executionsCount)
+
+	// the same block is not executed again
+	_, err = blockchain.verifyBlockBody(block)
+	require.NoError(t, err)
+	require.Equal(t, 1, executionsCount)
+
+	// cached result is not used if the parent state differs
+	parent.StateRoot = types.StringToHash("1")
+
+	_, err = blockchain.executeBlockTransactions(block)
+	require.Error(t, err)
+	require.Equal(t, 2, executionsCount)
+
+	// cached result is not used once it is invalidated (e.g. on reorg)
+	parent.StateRoot = types.EmptyRootHash
+
+	blockchain.invalidateExecutionResults(header)
+
+	_, err = blockchain.verifyBlockBody(block)
+	require.NoError(t, err)
+	require.Equal(t, 3, executionsCount)
 }
 
 func TestBlockchain_CalculateBaseFee(t *testing.T) {
@@ -1390,3 +1821,263 @@ func TestBlockchain_CalculateBaseFee(t *testing.T) {
 		})
 	}
 }
+
+func TestBlockchain_LookupBlockByHash(t *testing.T) {
+	// headers are written without bodies
+	headers := NewTestHeaders(3)
+	b := NewTestBlockchain(t, headers)
+
+	tx := &types.Transaction{Value: big.NewInt(10), V: big.NewInt(1), From: types.StringToAddress("1")}
+	tx.ComputeHash()
+
+	blockWithBody := &types.Block{Header: headers[2], Transactions: []*types.Transaction{tx}}
+	require.NoError(t, b.writeBody(blockWithBody))
+
+	t.Run("unknown block", func(t *testing.T) {
+		_, err := b.LookupBlockByHash(types.StringToHash("0x1"), true)
+		require.ErrorIs(t, err, ErrBlockNotFound)
+	})
+
+	t.Run("lenient mode", func(t *testing.T) {
+		block, err := b.LookupBlockByHash(headers[1].Hash, true)
+		require.NoError(t, err)
+		require.Equal(t, headers[1], block.Header)
+		require.Empty(t, block.Transactions)
+
+		block, err = b.LookupBlockByHash(headers[2].Hash, true)
+		require.NoError(t, err)
+		require.Len(t, block.Transactions, 1)
+	})
+
+	t.Run("strict mode", func(t *testing.T) {
+		b.SetStrictBodyLookup(true)
+		defer b.SetStrictBodyLookup(false)
+
+		_, err := b.LookupBlockByHash(headers[1].Hash, true)
+		require.ErrorIs(t, err, ErrBodyMissing)
+
+		// body is not needed if only a header is requested
+		block, err := b.LookupBlockByHash(headers[1].Hash, false)
+		require.NoError(t, err)
+		require.Equal(t, headers[1], block.Header)
+
+		block, err = b.LookupBlockByHash(headers[2].Hash, true)
+		require.NoError(t, err)
+		require.Len(t, block.Transactions, 1)
+	})
+}
+
+func TestBlockchain_HeaderByNumber(t *testing.T) {
+	t.Parallel()
+
+	t.Run("existing block", func(t *testing.T) {
+		t.Parallel()
+
+		headers := NewTestHeaders(3)
+		b := NewTestBlockchain(t, headers)
+
+		header, err := b.HeaderByNumber(2)
+		require.NoError(t, err)
+		require.Equal(t, headers[2].Hash, header.Hash)
+	})
+
+	t.Run("unknown block", func(t *testing.T) {
+		t.Parallel()
+
+		b := NewTestBlockchain(t, NewTestHeaders(3))
+
+		_, err := b.HeaderByNumber(10)
+		require.ErrorIs(t, err, ErrBlockNotFound)
+	})
+
+	t.Run("missing header", func(t *testing.T) {
+		t.Parallel()
+
+		blockchain, err := NewMockBlockchain(map[TestCallbackType]interface{}{
+			StorageCallback: func(mockStorage *storage.MockStorage) {
+				mockStorage.HookReadCanonicalHash(func(uint64) (types.Hash, bool) {
+					return types.StringToHash("0x1"), true
+				})
+				mockStorage.HookReadHeader(func(types.Hash) (*types.Header, error) {
+					return nil, storage.ErrNotFound
+				})
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = blockchain.HeaderByNumber(1)
+		require.ErrorIs(t, err, ErrBlockNotFound)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
+		t.Parallel()
+
+		errStorage := errors.New("storage failure")
+
+		blockchain, err := NewMockBlockchain(map[TestCallbackType]interface{}{
+			StorageCallback: func(mockStorage *storage.MockStorage) {
+				mockStorage.HookReadCanonicalHash(func(uint64) (types.Hash, bool) {
+					return types.StringToHash("0x1"), true
+				})
+				mockStorage.HookReadHeader(func(types.Hash) (*types.Header, error) {
+					return nil, errStorage
+				})
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = blockchain.HeaderByNumber(1)
+		require.ErrorIs(t, err, errStorage)
+		require.NotErrorIs(t, err, ErrBlockNotFound)
+
+		// the boolean variant is not able to tell the difference
+		_, found := blockchain.GetHeaderByNumber(1)
+		require.False(t, found)
+	})
+}
+
+func TestBlockchain_BlocksCache(t *testing.T) {
+	t.Parallel()
+
+	writeBodies := func(t *testing.T, b *Blockchain, headers []*types.Header) {
+		t.Helper()
+
+		for _, header := range headers {
+			require.NoError(t, b.writeBody(&types.Block{Header: header}))
+		}
+	}
+
+	t.Run("cached blocks", func(t *testing.T) {
+		t.Parallel()
+
+		headers := NewTestHeaders(10)
+		b := NewTestBlockchain(t, headers)
+		writeBodies(t, b, headers[1:])
+
+		block, ok := b.GetBlockByNumber(5, true)
+		require.True(t, ok)
+		require.Equal(t, headers[5].Hash, block.Hash())
+
+		// subsequent lookups are served from the cache, both by number and by hash,
+		// and modifying the returned block does not affect the cached one
+		block.Header.GasUsed++
+
+		cachedBlock, ok := b.GetBlockByNumber(5, true)
+		require.True(t, ok)
+		require.Equal(t, headers[5].Hash, cachedBlock.Hash())
+		require.Equal(t, headers[5].GasUsed, cachedBlock.Header.GasUsed)
+		require.NotSame(t, block, cachedBlock)
+
+		cachedBlock.Header.GasUsed++
+
+		cachedBlock, ok = b.GetBlockByHash(headers[5].Hash, true)
+		require.True(t, ok)
+		require.Equal(t, headers[5].GasUsed, cachedBlock.Header.GasUsed)
+
+		headerOnlyBlock, ok := b.GetBlockByNumber(5, false)
+		require.True(t, ok)
+		require.Equal(t, headers[5].Hash, headerOnlyBlock.Hash())
+		require.NotSame(t, block, headerOnlyBlock)
+	})
+
+	t.Run("reorg invalidates cached blocks", func(t *testing.T) {
+		t.Parallel()
+
+		headers := NewTestHeaders(10)
+		b := NewTestBlockchain(t, headers)
+		writeBodies(t, b, headers[1:])
+
+		for i := uint64(1); i < 10; i++ {
+			block, ok := b.GetBlockByNumber(i, true)
+			require.True(t, ok)
+			require.Equal(t, headers[i].Hash, block.Hash())
+		}
+
+		// a longer fork starting after block 4 becomes the canonical chain
+		forkHeaders := AppendNewTestheadersWithSeed(headers[:5], 10, 1)
+		writeBodies(t, b, forkHeaders[5:])
+		require.NoError(t, b.WriteHeaders(forkHeaders[5:]))
+		require.Equal(t, forkHeaders[len(forkHeaders)-1].Hash, b.Header().Hash)
+
+		for i := uint64(1); i < uint64(len(forkHeaders)); i++ {
+			block, ok := b.GetBlockByNumber(i, true)
+			require.True(t, ok)
+			require.Equal(t, forkHeaders[i].Hash, block.Hash(), "block %d", i)
+		}
+	})
+
+	t.Run("disabled cache", func(t *testing.T) {
+		t.Parallel()
+
+		headers := NewTestHeaders(3)
+		b := NewTestBlockchain(t, headers)
+		writeBodies(t, b, headers[1:])
+		require.NoError(t, b.SetBlocksCacheSize(0))
+
+		block, ok := b.GetBlockByNumber(2, true)
+		require.True(t, ok)
+
+		otherBlock, ok := b.GetBlockByNumber(2, true)
+		require.True(t, ok)
+		require.Equal(t, block.Hash(), otherBlock.Hash())
+		require.NotSame(t, block, otherBlock)
+	})
+}
+
+func TestBlockchain_Reorg_CanonicalHashes(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(10)
+	b := NewTestBlockchain(t, headers)
+
+	// a longer fork starting after block 4 becomes the canonical chain
+	forkHeaders := AppendNewTestheadersWithSeed(headers[:5], 10, 1)
+	require.NoError(t, b.WriteHeaders(forkHeaders[5:]))
+	require.Equal(t, forkHeaders[len(forkHeaders)-1].Hash, b.Header().Hash)
+
+	// fork headers below the old head height are canonical as well
+	for i := uint64(1); i < uint64(len(forkHeaders)); i++ {
+		hash, ok := b.db.ReadCanonicalHash(i)
+		require.True(t, ok)
+		require.Equal(t, forkHeaders[i].Hash, hash, "block %d", i)
+	}
+}
+
+func TestBlockResult_VerifyBlockResult_GasUsedDivergence(t *testing.T) {
+	t.Parallel()
+
+	gasUsed := []uint64{21000, 30000, 21000}
+
+	blockResult := &BlockResult{Root: types.StringToHash("0x1")}
+	transactions := make([]*types.Transaction, len(gasUsed))
+
+	for i, gas := range gasUsed {
+		blockResult.TotalGas += gas
+		blockResult.Receipts = append(blockResult.Receipts, &types.Receipt{
+			GasUsed:           gas,
+			CumulativeGasUsed: blockResult.TotalGas,
+			TxHash:            types.BytesToHash([]byte{byte(i + 1)}),
+		})
+		transactions[i] = &types.Transaction{Nonce: uint64(i)}
+	}
+
+	newBlock := func(gasUsed uint64) *types.Block {
+		return &types.Block{
+			Header:       &types.Header{StateRoot: blockResult.Root, GasUsed: gasUsed},
+			Transactions: transactions,
+		}
+	}
+
+	// header gas used is deliberately wrong, so that the second transaction exceeds it
+	err := blockResult.verifyBlockResult(newBlock(40000))
+	require.ErrorIs(t, err, ErrInvalidGasUsed)
+	require.ErrorContains(t, err, "have 72000, want 40000")
+	require.ErrorContains(t, err,
+		fmt.Sprintf("exceeds the expected one at transaction 1 (hash %s)", blockResult.Receipts[1].TxHash))
+	require.ErrorContains(t, err, "[0: 21000 (cumulative 21000), 1: 30000 (cumulative 51000), 2: 21000 (cumulative 72000)]")
+
+	// header gas used is above the gas used by all the transactions
+	err = blockResult.verifyBlockResult(newBlock(100000))
+	require.ErrorIs(t, err, ErrInvalidGasUsed)
+	require.ErrorContains(t, err, "below the expected one")
+}
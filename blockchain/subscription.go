@@ -2,6 +2,7 @@ package blockchain
 
 import (
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/0xPolygon/polygon-edge/types"
@@ -86,6 +87,9 @@ type Event struct {
 	// Source is the source that generated the blocks for the event
 	// right now it can be either the Sealer or the Syncer
 	Source string
+
+	// CommonAncestor is the last header shared by the old and the new chain (set only for reorg events)
+	CommonAncestor *types.Header
 }
 
 // Header returns the latest block header for the event
@@ -166,3 +170,121 @@ func (e *eventStream) push(event *Event) {
 		update <- event
 	}
 }
+
+// ReorgEvent is the chain reorganization event that gets passed to the reorg listeners
+type ReorgEvent struct {
+	// CommonAncestor is the last header shared by the old and the new canonical chain
+	CommonAncestor *types.Header
+
+	// Orphaned are hashes of blocks removed from the canonical chain, in ascending order of their numbers
+	Orphaned []types.Hash
+
+	// NewCanonical are hashes of blocks which became canonical, in ascending order of their numbers
+	NewCanonical []types.Hash
+}
+
+// newReorgEvent creates a reorg event out of the blockchain reorg event
+func newReorgEvent(evnt *Event) *ReorgEvent {
+	hashes := func(headers []*types.Header) []types.Hash {
+		sorted := make([]*types.Header, len(headers))
+		copy(sorted, headers)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Number < sorted[j].Number })
+
+		result := make([]types.Hash, len(sorted))
+		for i, h := range sorted {
+			result[i] = h.Hash
+		}
+
+		return result
+	}
+
+	return &ReorgEvent{
+		CommonAncestor: evnt.CommonAncestor,
+		Orphaned:       hashes(evnt.OldChain),
+		NewCanonical:   hashes(evnt.NewChain),
+	}
+}
+
+// ReorgSubscription is the blockchain reorg subscription interface
+type ReorgSubscription interface {
+	GetReorgCh() <-chan *ReorgEvent
+	Close()
+}
+
+// SubscribeReorgs returns a blockchain reorg subscription
+func (b *Blockchain) SubscribeReorgs() ReorgSubscription {
+	return b.reorgStream.subscribe()
+}
+
+// reorgSubscription is the blockchain reorg subscription object
+type reorgSubscription struct {
+	stream    *reorgStream
+	updateCh  chan *ReorgEvent
+	closeCh   chan void
+	closeOnce sync.Once
+}
+
+// GetReorgCh returns the reorg event channel
+func (s *reorgSubscription) GetReorgCh() <-chan *ReorgEvent {
+	return s.updateCh
+}
+
+// Close closes the subscription, and stops delivering reorg events to it
+func (s *reorgSubscription) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closeCh)
+		s.stream.unsubscribe(s)
+	})
+}
+
+// reorgStream delivers reorg events to its subscribers
+type reorgStream struct {
+	lock          sync.Mutex
+	subscriptions map[*reorgSubscription]void
+}
+
+// subscribe creates a new reorg subscription
+func (r *reorgStream) subscribe() *reorgSubscription {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	sub := &reorgSubscription{
+		stream:   r,
+		updateCh: make(chan *ReorgEvent, 5),
+		closeCh:  make(chan void),
+	}
+
+	if r.subscriptions == nil {
+		r.subscriptions = make(map[*reorgSubscription]void)
+	}
+
+	r.subscriptions[sub] = void{}
+
+	return sub
+}
+
+// unsubscribe removes the given subscription from the stream
+func (r *reorgStream) unsubscribe(sub *reorgSubscription) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.subscriptions, sub)
+}
+
+// push notifies subscribers about the given reorg event
+func (r *reorgStream) push(event *ReorgEvent) {
+	r.lock.Lock()
+	subscriptions := make([]*reorgSubscription, 0, len(r.subscriptions))
+
+	for sub := range r.subscriptions {
+		subscriptions = append(subscriptions, sub)
+	}
+	r.lock.Unlock()
+
+	for _, sub := range subscriptions {
+		select {
+		case sub.updateCh <- event:
+		case <-sub.closeCh:
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSubscription(t *testing.T) {
@@ -109,3 +110,72 @@ func TestSubscription_BufferedChannel_MultipleSubscriptions(t *testing.T) {
 		s.Close()
 	}
 }
+
+func TestBlockchain_SubscribeReorgs(t *testing.T) {
+	t.Parallel()
+
+	headers := NewTestHeaders(10)
+	b := NewTestBlockchain(t, headers)
+
+	sub := b.SubscribeReorgs()
+	defer sub.Close()
+
+	// a longer fork starting after block 4 becomes the canonical chain
+	forkHeaders := AppendNewTestheadersWithSeed(headers[:5], 10, 1)
+	require.NoError(t, b.WriteHeaders(forkHeaders[5:]))
+
+	var reorg *ReorgEvent
+
+	select {
+	case reorg = <-sub.GetReorgCh():
+	case <-time.After(5 * time.Second):
+		t.Fatal("reorg event not received")
+	}
+
+	require.Equal(t, headers[4].Hash, reorg.CommonAncestor.Hash)
+
+	orphaned := make([]types.Hash, 0, 5)
+	for _, h := range headers[5:] {
+		orphaned = append(orphaned, h.Hash)
+	}
+
+	require.Equal(t, orphaned, reorg.Orphaned)
+
+	// fork becomes canonical once its total difficulty exceeds the one of the old chain (at block 10)
+	newCanonical := make([]types.Hash, 0, 6)
+	for _, h := range forkHeaders[5:11] {
+		newCanonical = append(newCanonical, h.Hash)
+	}
+
+	require.Equal(t, newCanonical, reorg.NewCanonical)
+
+	// the rest of the fork extends the canonical chain, without further reorgs
+	select {
+	case reorg = <-sub.GetReorgCh():
+		t.Fatalf("unexpected reorg event: %v", reorg)
+	default:
+	}
+}
+
+func TestReorgSubscription_Close(t *testing.T) {
+	t.Parallel()
+
+	var (
+		stream = &reorgStream{}
+		sub    = stream.subscribe()
+		other  = stream.subscribe()
+	)
+
+	defer other.Close()
+
+	sub.Close()
+	sub.Close()
+
+	// closed subscription does not block the stream, even if nobody reads from it
+	for i := 0; i < 10; i++ {
+		stream.push(&ReorgEvent{})
+		<-other.GetReorgCh()
+	}
+
+	require.Len(t, stream.subscriptions, 1)
+}
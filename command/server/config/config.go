@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/network"
 	"github.com/hashicorp/hcl"
 	"gopkg.in/yaml.v3"
@@ -34,6 +35,15 @@ type Config struct {
 
 	Relayer               bool   `json:"relayer" yaml:"relayer"`
 	NumBlockConfirmations uint64 `json:"num_block_confirmations" yaml:"num_block_confirmations"`
+	SystemStateRetries    uint64 `json:"system_state_retries" yaml:"system_state_retries"`
+	MaxParentLag          uint64 `json:"max_parent_lag" yaml:"max_parent_lag"`
+
+	SyncBeforeBlockProduction bool `json:"sync_before_block_production" yaml:"sync_before_block_production"`
+
+	MaxReorgDepth   uint64 `json:"max_reorg_depth" yaml:"max_reorg_depth"`
+	BlocksCacheSize int    `json:"blocks_cache_size" yaml:"blocks_cache_size"`
+	MaxForks        uint64 `json:"max_forks" yaml:"max_forks"`
+	MaxForkDepth    uint64 `json:"max_fork_depth" yaml:"max_fork_depth"`
 }
 
 // Telemetry holds the config details for metric services.
@@ -116,6 +126,7 @@ func DefaultConfig() *Config {
 		JSONRPCBlockRangeLimit:   DefaultJSONRPCBlockRangeLimit,
 		Relayer:                  false,
 		NumBlockConfirmations:    DefaultNumBlockConfirmations,
+		BlocksCacheSize:          blockchain.DefaultBlocksCacheSize,
 	}
 }
 
@@ -40,6 +40,15 @@ const (
 
 	relayerFlag               = "relayer"
 	numBlockConfirmationsFlag = "num-block-confirmations"
+	systemStateRetriesFlag    = "system-state-retries"
+	maxParentLagFlag          = "max-parent-lag"
+
+	syncBeforeBlockProductionFlag = "sync-before-block-production"
+
+	maxReorgDepthFlag   = "max-reorg-depth"
+	blocksCacheSizeFlag = "blocks-cache-size"
+	maxForksFlag        = "max-forks"
+	maxForkDepthFlag    = "max-fork-depth"
 )
 
 // Flags that are deprecated, but need to be preserved for
@@ -182,5 +191,14 @@ func (p *serverParams) generateConfig() *server.Config {
 
 		Relayer:               p.relayer,
 		NumBlockConfirmations: p.rawConfig.NumBlockConfirmations,
+		SystemStateRetries:    p.rawConfig.SystemStateRetries,
+		MaxParentLag:          p.rawConfig.MaxParentLag,
+
+		SyncBeforeBlockProduction: p.rawConfig.SyncBeforeBlockProduction,
+
+		MaxReorgDepth:   p.rawConfig.MaxReorgDepth,
+		BlocksCacheSize: p.rawConfig.BlocksCacheSize,
+		MaxForks:        p.rawConfig.MaxForks,
+		MaxForkDepth:    p.rawConfig.MaxForkDepth,
 	}
 }
@@ -228,6 +228,57 @@ func setFlags(cmd *cobra.Command) {
 		"minimal number of child blocks required for the parent block to be considered final",
 	)
 
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.SystemStateRetries,
+		systemStateRetriesFlag,
+		defaultConfig.SystemStateRetries,
+		"number of times a transient failure of a system state read is retried (PolyBFT only)",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.MaxParentLag,
+		maxParentLagFlag,
+		defaultConfig.MaxParentLag,
+		"maximum number of blocks the last built block can be behind the chain head for the node "+
+			"to still build a block on top of it (PolyBFT only, 0 means the default of 1)",
+	)
+
+	cmd.Flags().BoolVar(
+		&params.rawConfig.SyncBeforeBlockProduction,
+		syncBeforeBlockProductionFlag,
+		defaultConfig.SyncBeforeBlockProduction,
+		"wait to catch up with the network head after the start, before participating in block production "+
+			"(PolyBFT only)",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.MaxReorgDepth,
+		maxReorgDepthFlag,
+		defaultConfig.MaxReorgDepth,
+		"maximum number of canonical blocks which can be orphaned by a chain reorganization (0 means unlimited)",
+	)
+
+	cmd.Flags().IntVar(
+		&params.rawConfig.BlocksCacheSize,
+		blocksCacheSizeFlag,
+		defaultConfig.BlocksCacheSize,
+		"number of canonical full blocks kept in memory (0 disables the cache)",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.MaxForks,
+		maxForksFlag,
+		defaultConfig.MaxForks,
+		"maximum number of tracked fork heads, the ones with the lowest total difficulty are evicted (0 means unlimited)",
+	)
+
+	cmd.Flags().Uint64Var(
+		&params.rawConfig.MaxForkDepth,
+		maxForkDepthFlag,
+		defaultConfig.MaxForkDepth,
+		"maximum number of blocks a tracked fork head can be behind the chain head (0 means unlimited)",
+	)
+
 	setLegacyFlags(cmd)
 
 	setDevFlags(cmd)
@@ -78,6 +78,17 @@ type Params struct {
 	BlockTime      uint64
 
 	NumBlockConfirmations uint64
+
+	// SystemStateRetries is the number of times a transient failure of a system state read is retried
+	SystemStateRetries uint64
+
+	// MaxParentLag is the maximum number of blocks the last built block can be behind the chain head,
+	// for the node to still build a block on top of it
+	MaxParentLag uint64
+
+	// SyncBeforeBlockProduction indicates whether node waits to catch up with the network head
+	// (after it starts) before it participates in block production
+	SyncBeforeBlockProduction bool
 }
 
 // Factory is the factory function to create a discovery consensus
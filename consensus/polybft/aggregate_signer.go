@@ -0,0 +1,133 @@
+package polybft
+
+import (
+	"fmt"
+	"sync"
+
+	bls "github.com/0xPolygon/polygon-edge/consensus/polybft/signer"
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/wallet"
+)
+
+// BLSSignatureScheme is the name of the default, BLS based, commitment signature scheme
+const BLSSignatureScheme = "bls"
+
+// AggregateSigner is an abstraction of the signature scheme used for signing commitments
+// and aggregating validators signatures on them
+type AggregateSigner interface {
+	// Sign signs given message in the given domain with the provided key
+	Sign(key *wallet.Key, message, domain []byte) ([]byte, error)
+	// Verify verifies that the given raw signature of the message in the given domain is created by the given signer
+	Verify(signer *validator.ValidatorMetadata, signature, message, domain []byte) error
+	// Aggregate aggregates given raw signatures into a single raw signature
+	Aggregate(signatures [][]byte) ([]byte, error)
+}
+
+// newAggregateSigner creates an aggregate signer for the given signature scheme.
+// BLS signature scheme is used if no scheme is provided.
+// Signatures are aggregated in parallel batches by the given number of workers (serially if it is not above 1).
+func newAggregateSigner(scheme string, aggregationWorkers uint64) (AggregateSigner, error) {
+	switch scheme {
+	case "", BLSSignatureScheme:
+		return &blsAggregateSigner{aggregationWorkers: aggregationWorkers}, nil
+	default:
+		return nil, fmt.Errorf("unsupported commitment signature scheme: %s", scheme)
+	}
+}
+
+var _ AggregateSigner = (*blsAggregateSigner)(nil)
+
+// blsAggregateSigner is the BLS implementation of AggregateSigner interface
+type blsAggregateSigner struct {
+	// aggregationWorkers is the number of workers aggregating signatures in parallel batches
+	aggregationWorkers uint64
+}
+
+// Sign is an implementation of AggregateSigner interface
+func (b *blsAggregateSigner) Sign(key *wallet.Key, message, domain []byte) ([]byte, error) {
+	return key.SignWithDomain(message, domain)
+}
+
+// Verify is an implementation of AggregateSigner interface
+func (b *blsAggregateSigner) Verify(signer *validator.ValidatorMetadata, signature, message, domain []byte) error {
+	unmarshaledSignature, err := bls.UnmarshalSignature(signature)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal signature from signer %s, %w", signer.Address, err)
+	}
+
+	if !unmarshaledSignature.Verify(signer.BlsKey, message, domain) {
+		return fmt.Errorf("incorrect signature from %s", signer.Address)
+	}
+
+	return nil
+}
+
+// Aggregate is an implementation of AggregateSigner interface
+func (b *blsAggregateSigner) Aggregate(signatures [][]byte) ([]byte, error) {
+	workers := int(b.aggregationWorkers)
+	if workers > len(signatures) {
+		workers = len(signatures)
+	}
+
+	if workers <= 1 {
+		aggregated, err := aggregateBLSSignatures(signatures)
+		if err != nil {
+			return nil, err
+		}
+
+		return aggregated.Marshal()
+	}
+
+	// BLS aggregation is associative, so batches are aggregated in parallel and partial aggregates are combined
+	batchSize := (len(signatures) + workers - 1) / workers
+	batchesCount := (len(signatures) + batchSize - 1) / batchSize
+
+	var (
+		partials = make(bls.Signatures, batchesCount)
+		errs     = make([]error, batchesCount)
+		wg       sync.WaitGroup
+	)
+
+	for i := 0; i < batchesCount; i++ {
+		from := i * batchSize
+		to := from + batchSize
+
+		if to > len(signatures) {
+			to = len(signatures)
+		}
+
+		wg.Add(1)
+
+		go func(i int, batch [][]byte) {
+			defer wg.Done()
+
+			partials[i], errs[i] = aggregateBLSSignatures(batch)
+		}(i, signatures[from:to])
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return partials.Aggregate().Marshal()
+}
+
+// aggregateBLSSignatures unmarshals given raw BLS signatures and aggregates them into a single signature
+func aggregateBLSSignatures(signatures [][]byte) (*bls.Signature, error) {
+	unmarshaledSignatures := make(bls.Signatures, len(signatures))
+
+	for i, signature := range signatures {
+		unmarshaledSignature, err := bls.UnmarshalSignature(signature)
+		if err != nil {
+			return nil, err
+		}
+
+		unmarshaledSignatures[i] = unmarshaledSignature
+	}
+
+	return unmarshaledSignatures.Aggregate(), nil
+}
@@ -0,0 +1,179 @@
+package polybft
+
+import (
+	"fmt"
+	"testing"
+
+	bls "github.com/0xPolygon/polygon-edge/consensus/polybft/signer"
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregateSigner_NewAggregateSigner(t *testing.T) {
+	t.Parallel()
+
+	signer, err := newAggregateSigner("", 0)
+	require.NoError(t, err)
+	require.IsType(t, &blsAggregateSigner{}, signer)
+
+	signer, err = newAggregateSigner(BLSSignatureScheme, 0)
+	require.NoError(t, err)
+	require.IsType(t, &blsAggregateSigner{}, signer)
+
+	_, err = newAggregateSigner("unknown", 0)
+	require.ErrorContains(t, err, "unsupported commitment signature scheme")
+}
+
+func TestAggregateSigner_BLS(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidatorsWithAliases(t, []string{"A", "B", "C"})
+	message := types.StringToHash("0xABCD").Bytes()
+	signer := &blsAggregateSigner{}
+
+	signatures := make([][]byte, 0, len(vals.Validators))
+
+	for _, alias := range []string{"A", "B", "C"} {
+		val := vals.GetValidator(alias)
+
+		signature, err := signer.Sign(val.Key(), message, bls.DomainStateReceiver)
+		require.NoError(t, err)
+		require.NoError(t, signer.Verify(val.ValidatorMetadata(), signature, message, bls.DomainStateReceiver))
+
+		// signature is not valid for another signer nor another domain
+		require.Error(t, signer.Verify(vals.GetValidator("A").ValidatorMetadata(), signature, message,
+			bls.DomainCheckpointManager))
+
+		if alias != "A" {
+			require.Error(t, signer.Verify(vals.GetValidator("A").ValidatorMetadata(), signature, message,
+				bls.DomainStateReceiver))
+		}
+
+		signatures = append(signatures, signature)
+	}
+
+	aggregated, err := signer.Aggregate(signatures)
+	require.NoError(t, err)
+
+	aggregatedSignature, err := bls.UnmarshalSignature(aggregated)
+	require.NoError(t, err)
+	require.True(t, aggregatedSignature.VerifyAggregated(
+		vals.GetPublicIdentities().GetBlsKeys(), message, bls.DomainStateReceiver))
+
+	_, err = signer.Aggregate([][]byte{{0x1, 0x2}})
+	require.Error(t, err)
+}
+
+func TestAggregateSigner_BLS_Batched(t *testing.T) {
+	t.Parallel()
+
+	const signaturesCount = 10
+
+	message := types.StringToHash("0xABCD").Bytes()
+	signatures := createTestRawSignatures(t, signaturesCount, message)
+
+	serialSignature, err := (&blsAggregateSigner{}).Aggregate(signatures)
+	require.NoError(t, err)
+
+	// batched aggregation result does not depend on the number of workers (nor on the batch sizes)
+	for workers := uint64(2); workers <= signaturesCount+2; workers++ {
+		signer, err := newAggregateSigner(BLSSignatureScheme, workers)
+		require.NoError(t, err)
+
+		batchedSignature, err := signer.Aggregate(signatures)
+		require.NoError(t, err)
+		require.Equal(t, serialSignature, batchedSignature, "workers %d", workers)
+	}
+
+	// malformed signature in any of the batches fails the aggregation
+	malformedSignatures := append([][]byte{}, signatures...)
+	malformedSignatures[signaturesCount-1] = []byte{0x1, 0x2}
+
+	_, err = (&blsAggregateSigner{aggregationWorkers: 4}).Aggregate(malformedSignatures)
+	require.Error(t, err)
+}
+
+func BenchmarkAggregateSigner_BLS(b *testing.B) {
+	message := types.StringToHash("0xABCD").Bytes()
+	signatures := createTestRawSignatures(b, 128, message)
+
+	for _, workers := range []uint64{0, 2, 4, 8} {
+		signer := &blsAggregateSigner{aggregationWorkers: workers}
+
+		b.Run(fmt.Sprintf("workers %d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := signer.Aggregate(signatures); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// createTestRawSignatures creates raw BLS signatures of the given message by the given number of validators
+func createTestRawSignatures(tb testing.TB, count int, message []byte) [][]byte {
+	tb.Helper()
+
+	signer := &blsAggregateSigner{}
+	signatures := make([][]byte, 0, count)
+
+	for _, val := range validator.NewTestValidators(tb, count).Validators {
+		signature, err := signer.Sign(val.Key(), message, bls.DomainStateReceiver)
+		require.NoError(tb, err)
+
+		signatures = append(signatures, signature)
+	}
+
+	return signatures
+}
+
+func TestAggregateSigner_StateSyncManagerUsesConfiguredSigner(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+	aggregatedSignature := []byte("aggregated signature")
+
+	signerMock := new(aggregateSignerMock)
+	signerMock.On("Sign", mock.Anything, mock.Anything, bls.DomainStateReceiver).
+		Return([]byte("signature"), nil).Once()
+	signerMock.On("Verify", mock.Anything, mock.Anything, mock.Anything, bls.DomainStateReceiver).
+		Return(nil).Times(3)
+	signerMock.On("Aggregate", mock.MatchedBy(func(signatures [][]byte) bool { return len(signatures) == 4 })).
+		Return(aggregatedSignature, nil).Once()
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.config.aggregateSigner = signerMock
+	s.validatorSet = vals.ToValidatorSet()
+
+	for _, event := range generateStateSyncEvents(t, 3, 0) {
+		insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
+	}
+
+	// builds and signs the commitment with the local key
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 1)
+
+	hash, err := s.pendingCommitments[0].Hash()
+	require.NoError(t, err)
+
+	// votes from other validators are verified through the configured signer
+	for _, alias := range []string{"1", "2", "3"} {
+		require.NoError(t, s.saveVote(&TransportMessage{
+			Hash:      hash.Bytes(),
+			Signature: []byte("signature " + alias),
+			From:      vals.GetValidator(alias).Address().String(),
+		}))
+	}
+
+	commitment, err := s.Commitment()
+	require.NoError(t, err)
+	require.NotNil(t, commitment)
+	require.Equal(t, aggregatedSignature, commitment.AggSignature.AggregatedSignature)
+
+	signerMock.AssertExpectations(t)
+}
@@ -49,6 +49,10 @@ type blockchainBackend interface {
 	// GetHeaderByNumber returns a reference to block header for the given block number.
 	GetHeaderByNumber(number uint64) (*types.Header, bool)
 
+	// HeaderByNumber returns a reference to block header for the given block number,
+	// or an error if the header is missing (blockchain.ErrBlockNotFound) or can not be read
+	HeaderByNumber(number uint64) (*types.Header, error)
+
 	// GetHeaderByHash returns a reference to block header for the given block hash
 	GetHeaderByHash(hash types.Hash) (*types.Header, bool)
 
@@ -57,6 +61,9 @@ type blockchainBackend interface {
 
 	SubscribeEvents() blockchain.Subscription
 
+	// SubscribeReorgs returns a subscription to chain reorganization events
+	SubscribeReorgs() blockchain.ReorgSubscription
+
 	// GetChainID returns chain id of the current blockchain
 	GetChainID() uint64
 
@@ -142,6 +149,11 @@ func (p *blockchainWrapper) GetHeaderByNumber(number uint64) (*types.Header, boo
 	return p.blockchain.GetHeaderByNumber(number)
 }
 
+// HeaderByNumber is an implementation of blockchainBackend interface
+func (p *blockchainWrapper) HeaderByNumber(number uint64) (*types.Header, error) {
+	return p.blockchain.HeaderByNumber(number)
+}
+
 // GetHeaderByHash is an implementation of blockchainBackend interface
 func (p *blockchainWrapper) GetHeaderByHash(hash types.Hash) (*types.Header, bool) {
 	return p.blockchain.GetHeaderByHash(hash)
@@ -177,6 +189,10 @@ func (p *blockchainWrapper) SubscribeEvents() blockchain.Subscription {
 	return p.blockchain.SubscribeEvents()
 }
 
+func (p *blockchainWrapper) SubscribeReorgs() blockchain.ReorgSubscription {
+	return p.blockchain.SubscribeReorgs()
+}
+
 func (p *blockchainWrapper) GetChainID() uint64 {
 	return uint64(p.blockchain.Config().ChainID)
 }
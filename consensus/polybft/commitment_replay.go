@@ -0,0 +1,92 @@
+package polybft
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/merkle-tree"
+)
+
+// errInconsistentCommitment represents "inconsistent stored commitment" error message
+var errInconsistentCommitment = errors.New("inconsistent stored commitment")
+
+// ReplayCommitments verifies stored commitments which contain state syncs in the given range.
+// Merkle tree of each commitment is rebuilt from the stored state sync events and its root
+// is compared to the stored one, and stored proofs of its state syncs are verified against the root.
+// Trees are rebuilt and proofs are verified using the given merkle hasher (the default one if nil).
+// The first found inconsistency is returned as an error.
+func ReplayCommitments(state *State, fromIndex, toIndex uint64, hasher MerkleHasher) error {
+	if fromIndex > toIndex {
+		return fmt.Errorf("invalid state sync range: from index %d is greater than to index %d", fromIndex, toIndex)
+	}
+
+	commitments, err := state.StateSyncStore.getCommitmentMessages(fromIndex, toIndex)
+	if err != nil {
+		return fmt.Errorf("failed to get commitments for state syncs %d-%d: %w", fromIndex, toIndex, err)
+	}
+
+	for i, commitment := range commitments {
+		if i > 0 {
+			previousEndID := commitments[i-1].Message.EndID.Uint64()
+			if commitment.Message.StartID.Uint64() != previousEndID+1 {
+				return fmt.Errorf("%w: commitment %d-%d does not follow the previous commitment ending at %d",
+					errInconsistentCommitment, commitment.Message.StartID.Uint64(),
+					commitment.Message.EndID.Uint64(), previousEndID)
+			}
+		}
+
+		if err := replayCommitment(state, commitment, hasher); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replayCommitment rebuilds merkle tree of the given stored commitment and verifies the stored proofs against it
+func replayCommitment(state *State, commitment *CommitmentMessageSigned, hasher MerkleHasher) error {
+	fromIndex, toIndex := commitment.Message.StartID.Uint64(), commitment.Message.EndID.Uint64()
+
+	events, err := state.StateSyncStore.getStateSyncEventsForCommitment(fromIndex, toIndex)
+	if err != nil {
+		return fmt.Errorf("%w: failed to get state sync events of commitment %d-%d: %v",
+			errInconsistentCommitment, fromIndex, toIndex, err)
+	}
+
+	tree, err := createMerkleTree(events, hasher)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild merkle tree of commitment %d-%d: %w", fromIndex, toIndex, err)
+	}
+
+	root := tree.Hash()
+	if root != commitment.Message.Root {
+		return fmt.Errorf("%w: commitment %d-%d has root %s, while root rebuilt from state sync events is %s",
+			errInconsistentCommitment, fromIndex, toIndex, commitment.Message.Root, root)
+	}
+
+	for _, event := range events {
+		stateSyncID := event.ID.Uint64()
+
+		proof, err := state.StateSyncStore.getStateSyncProof(stateSyncID)
+		if err != nil {
+			return fmt.Errorf("failed to get proof of state sync %d: %w", stateSyncID, err)
+		}
+
+		if proof == nil {
+			return fmt.Errorf("%w: proof of state sync %d from commitment %d-%d is missing",
+				errInconsistentCommitment, stateSyncID, fromIndex, toIndex)
+		}
+
+		leaf, err := event.EncodeAbi()
+		if err != nil {
+			return fmt.Errorf("failed to encode state sync event %d: %w", stateSyncID, err)
+		}
+
+		if err := merkle.VerifyProofUsing(stateSyncID-fromIndex, leaf, proof.Proof, root, hasher.newHash()); err != nil {
+			return fmt.Errorf("%w: proof of state sync %d from commitment %d-%d (root %s) is invalid: %v",
+				errInconsistentCommitment, stateSyncID, fromIndex, toIndex, root, err)
+		}
+	}
+
+	return nil
+}
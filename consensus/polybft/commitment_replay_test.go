@@ -0,0 +1,100 @@
+package polybft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayCommitments(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+
+	// setup stores two consecutive commitments (0-9 and 10-19), along with proofs of their state syncs
+	setup := func(t *testing.T) *State {
+		t.Helper()
+
+		s := newTestStateSyncManager(t, vals.GetValidator("0"))
+		s.validatorSet = vals.ToValidatorSet()
+
+		for _, event := range generateStateSyncEvents(t, 20, 0) {
+			insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
+		}
+
+		for i := uint64(1); i <= 2; i++ {
+			require.NoError(t, s.buildCommitment())
+
+			commitment := &CommitmentMessageSigned{Message: s.pendingCommitments[0].StateSyncCommitment}
+			signTestCommitment(t, vals, commitment)
+
+			txData, err := commitment.EncodeAbi()
+			require.NoError(t, err)
+
+			require.NoError(t, s.PostBlock(&PostBlockRequest{
+				FullBlock: &types.FullBlock{
+					Block: &types.Block{
+						Header:       &types.Header{Number: i},
+						Transactions: []*types.Transaction{createStateTransactionWithData(types.Address{}, txData)},
+					},
+				},
+			}))
+		}
+
+		return s.state
+	}
+
+	t.Run("consistent commitments", func(t *testing.T) {
+		t.Parallel()
+
+		state := setup(t)
+
+		require.NoError(t, ReplayCommitments(state, 0, 19, nil))
+		require.NoError(t, ReplayCommitments(state, 5, 12, nil))
+		// there are no stored commitments in the range
+		require.NoError(t, ReplayCommitments(state, 20, 30, nil))
+	})
+
+	t.Run("corrupted proof", func(t *testing.T) {
+		t.Parallel()
+
+		state := setup(t)
+
+		proof, err := state.StateSyncStore.getStateSyncProof(13)
+		require.NoError(t, err)
+
+		proof.Proof[0] = types.StringToHash("0x1")
+		require.NoError(t, state.StateSyncStore.insertStateSyncProofs([]*StateSyncProof{proof}))
+
+		// commitment which does not contain corrupted proof is still consistent
+		require.NoError(t, ReplayCommitments(state, 0, 9, nil))
+
+		err = ReplayCommitments(state, 0, 19, nil)
+		require.ErrorIs(t, err, errInconsistentCommitment)
+		require.ErrorContains(t, err, "proof of state sync 13 from commitment 10-19")
+	})
+
+	t.Run("corrupted root", func(t *testing.T) {
+		t.Parallel()
+
+		state := setup(t)
+
+		commitment, err := state.StateSyncStore.getCommitmentMessage(9)
+		require.NoError(t, err)
+
+		commitment.Message.Root = types.StringToHash("0x1")
+		require.NoError(t, state.StateSyncStore.insertCommitmentMessage(commitment))
+
+		err = ReplayCommitments(state, 0, 19, nil)
+		require.ErrorIs(t, err, errInconsistentCommitment)
+		require.ErrorContains(t, err, "commitment 0-9 has root")
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		t.Parallel()
+
+		require.ErrorContains(t, ReplayCommitments(setup(t), 10, 5, nil), "invalid state sync range")
+	})
+}
@@ -8,24 +8,31 @@ import (
 	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 
+	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
 	bls "github.com/0xPolygon/polygon-edge/consensus/polybft/signer"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/wallet"
 	"github.com/0xPolygon/polygon-edge/contracts"
+	"github.com/0xPolygon/polygon-edge/helper/common"
 	"github.com/0xPolygon/polygon-edge/txrelayer"
 	"github.com/0xPolygon/polygon-edge/types"
 
 	"github.com/0xPolygon/go-ibft/messages"
 	"github.com/0xPolygon/go-ibft/messages/proto"
 	hcf "github.com/hashicorp/go-hclog"
+	lru "github.com/hashicorp/golang-lru"
 )
 
 const (
-	maxCommitmentSize       = 10
+	maxCommitmentSize       = 10 // default maximum number of state sync events in a single commitment
+	minCommitmentSize       = 1  // minimum number of state sync events in a single commitment
 	stateFileName           = "consensusState.db"
 	commitEpochLookbackSize = 2 // number of blocks to calculate commit epoch info from the previous epoch
+	defaultMaxParentLag     = 1 // default maximum number of blocks the parent of a built block is behind the head
+	defaultMaxVoteEpochLead = 1 // default maximum number of epochs a received vote is ahead of the current epoch
 )
 
 var (
@@ -33,8 +40,53 @@ var (
 	errNotAValidator = errors.New("node is not a validator")
 	// errQuorumNotReached represents "quorum not reached for commitment message" error message
 	errQuorumNotReached = errors.New("quorum not reached for commitment message")
+	// errInvalidEpochNumber represents "invalid epoch number" error message
+	errInvalidEpochNumber = errors.New("invalid epoch number")
+	// errObserverMode represents "node is running in observer mode" error message
+	errObserverMode = errors.New("node is running in observer mode")
+	// errValidatorKeyMismatch represents "node key does not belong to the current validator set" error message
+	errValidatorKeyMismatch = errors.New("node key does not belong to the current validator set")
+	// errStaleParent represents "parent block is behind the chain head" error message
+	errStaleParent = errors.New("parent block is behind the chain head")
+	// errNotSynced represents "node is not synced with the network" error message
+	errNotSynced = errors.New("node is not synced with the network")
+	// errValidatorSnapshotChecksum represents "validator snapshot checksum mismatch" error message
+	errValidatorSnapshotChecksum = errors.New("validator snapshot checksum mismatch")
+	// errRuntimeClosed represents "consensus runtime is closed" error message
+	errRuntimeClosed = errors.New("consensus runtime is closed")
+	// errInvalidValidatorSetSize represents "invalid validator set size" error message
+	errInvalidValidatorSetSize = errors.New("invalid validator set size")
+	// errEpochNotInitialized represents "epoch is not initialized" error message
+	errEpochNotInitialized = errors.New("epoch is not initialized")
+
+	// ErrNoCommitmentToRegister represents "no commitment to register" error message
+	ErrNoCommitmentToRegister = errors.New("no commitment to register")
 )
 
+// RuntimeMode defines whether the node participates in consensus or only follows the chain
+type RuntimeMode string
+
+const (
+	// ValidatorRuntimeMode is the default mode, in which node proposes and validates blocks
+	// whenever it is a member of the current validator set
+	ValidatorRuntimeMode RuntimeMode = "validator"
+	// ObserverRuntimeMode is the mode in which node only tracks blocks and state syncs
+	// and never participates in consensus (e.g. dedicated JSON-RPC nodes)
+	ObserverRuntimeMode RuntimeMode = "observer"
+)
+
+// parseRuntimeMode parses runtime mode, defaulting to validator mode if none is provided
+func parseRuntimeMode(mode string) (RuntimeMode, error) {
+	switch RuntimeMode(mode) {
+	case "", ValidatorRuntimeMode:
+		return ValidatorRuntimeMode, nil
+	case ObserverRuntimeMode:
+		return ObserverRuntimeMode, nil
+	default:
+		return "", fmt.Errorf("unsupported runtime mode: %s", mode)
+	}
+}
+
 // txPoolInterface is an abstraction of transaction pool
 type txPoolInterface interface {
 	Prepare(uint64)
@@ -80,6 +132,20 @@ type runtimeConfig struct {
 	txPool                txPoolInterface
 	bridgeTopic           topic
 	numBlockConfirmations uint64
+	mode                  RuntimeMode
+	// requireValidatorKey indicates whether the runtime creation fails if node key does not belong
+	// to the current validator set, instead of running as an observer
+	requireValidatorKey bool
+	// logLevels are log levels configured per subsystem
+	logLevels logLevels
+	// systemStateRetries is the number of times a transient failure of a system state read is retried
+	systemStateRetries uint64
+	// maxParentLag is the maximum number of blocks the last built block can be behind the chain head,
+	// for the node to still build a block on top of it (defaultMaxParentLag if zero)
+	maxParentLag uint64
+	// syncBeforeBlockProduction indicates whether node waits to catch up with the network head
+	// (after it starts) before it participates in block production
+	syncBeforeBlockProduction bool
 }
 
 // consensusRuntime is a struct that provides consensus runtime features like epoch, state and event management
@@ -105,6 +171,10 @@ type consensusRuntime struct {
 	// activeValidatorFlag indicates whether the given node is amongst currently active validator set
 	activeValidatorFlag atomic.Bool
 
+	// syncedFlag indicates whether the node reached the network head after it started,
+	// it gates block production if sync before block production is configured
+	syncedFlag atomic.Bool
+
 	// checkpointManager represents abstraction for checkpoint submission
 	checkpointManager CheckpointManager
 
@@ -117,76 +187,210 @@ type consensusRuntime struct {
 	// manager for handling validator stake change and updating validator set
 	stakeManager StakeManager
 
+	// stateSyncExecutionCache caches state sync execution statuses for the last built block
+	stateSyncExecutionCache stateSyncExecutionCache
+
+	// epochValidatorsCache caches validator sets of already started epochs
+	epochValidatorsCache epochValidatorsCache
+
+	// uptimeRewardCurve maps validators uptime to their rewards (linear curve is used if not set)
+	uptimeRewardCurve UptimeRewardCurve
+
+	// closeCh is closed when the runtime is closed
+	closeCh chan struct{}
+
+	// closeOnce ensures the runtime resources are torn down only once
+	closeOnce sync.Once
+
 	// logger instance
 	logger hcf.Logger
 }
 
 // newConsensusRuntime creates and starts a new consensus runtime instance with event tracking
 func newConsensusRuntime(log hcf.Logger, config *runtimeConfig) (*consensusRuntime, error) {
-	proposerCalculator, err := NewProposerCalculator(config, log.Named("proposer_calculator"))
+	proposerCalculator, err := NewProposerCalculator(config, config.logLevels.named(log, "proposer_calculator"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create consensus runtime, error while creating proposer calculator %w", err)
 	}
 
+	var uptimeCurveConfig *UptimeRewardCurveConfig
+	if config.PolyBFTConfig.RewardConfig != nil {
+		uptimeCurveConfig = config.PolyBFTConfig.RewardConfig.UptimeRewardCurve
+	}
+
+	uptimeRewardCurve, err := newUptimeRewardCurve(uptimeCurveConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consensus runtime, error while creating uptime reward curve %w", err)
+	}
+
 	runtime := &consensusRuntime{
 		state:              config.State,
 		config:             config,
 		lastBuiltBlock:     config.blockchain.CurrentHeader(),
 		proposerCalculator: proposerCalculator,
-		logger:             log.Named("consensus_runtime"),
+		uptimeRewardCurve:  uptimeRewardCurve,
+		closeCh:            make(chan struct{}),
+		logger:             config.logLevels.named(log, "consensus_runtime"),
 	}
 
-	if err := runtime.initStateSyncManager(log); err != nil {
+	if err := runtime.setup(log); err != nil {
+		// stop the already started components (e.g. the event tracker of the state sync manager)
+		runtime.Close()
+
 		return nil, err
 	}
 
-	if err := runtime.initCheckpointManager(log); err != nil {
-		return nil, err
+	return runtime, nil
+}
+
+// setup initializes the managers and the epoch state of the runtime
+func (c *consensusRuntime) setup(log hcf.Logger) error {
+	if err := c.initStateSyncManager(log); err != nil {
+		return err
 	}
 
-	if err := runtime.initStakeManager(log); err != nil {
-		return nil, err
+	if err := c.initCheckpointManager(log); err != nil {
+		return err
+	}
+
+	if err := c.initStakeManager(log); err != nil {
+		return err
 	}
 
 	// we need to call restart epoch on runtime to initialize epoch state
-	runtime.epoch, err = runtime.restartEpoch(runtime.lastBuiltBlock)
+	epoch, err := c.restartEpoch(c.lastBuiltBlock)
 	if err != nil {
-		return nil, fmt.Errorf("consensus runtime creation - restart epoch failed: %w", err)
+		return fmt.Errorf("consensus runtime creation - restart epoch failed: %w", err)
 	}
 
-	return runtime, nil
+	c.epoch = epoch
+
+	if _, err := c.checkValidatorKey(); err != nil {
+		return fmt.Errorf("consensus runtime creation - %w", err)
+	}
+
+	return nil
 }
 
-// close is used to tear down allocated resources
-func (c *consensusRuntime) close() {
-	c.stateSyncManager.Close()
+// checkValidatorKey checks whether node key belongs to the current validator set.
+// Such node runs as an observer (unless observer mode is requested explicitly, it is warned about),
+// or it fails if validator key is required by the configuration
+func (c *consensusRuntime) checkValidatorKey() (bool, error) {
+	address := c.config.Key.Address()
+	if c.epoch.Validators.ContainsAddress(types.Address(address)) {
+		return true, nil
+	}
+
+	if c.isObserver() {
+		return false, nil
+	}
+
+	if c.config.requireValidatorKey {
+		return false, fmt.Errorf("%w: address %s, epoch %d", errValidatorKeyMismatch, address, c.epoch.Number)
+	}
+
+	c.logger.Warn("node address is not in the current validator set, node will run as an observer "+
+		"until it becomes a validator", "address", address, "epoch", c.epoch.Number)
+
+	return false, nil
+}
+
+// Close tears down allocated resources. It stops the state sync manager (along with its event tracker)
+// and drops the in-memory epoch state. It is safe to be called multiple times.
+func (c *consensusRuntime) Close() {
+	c.closeOnce.Do(func() {
+		if c.closeCh != nil {
+			close(c.closeCh)
+		}
+
+		if c.stateSyncManager != nil {
+			c.stateSyncManager.Close()
+		}
+
+		c.lock.Lock()
+		c.fsm = nil
+		c.lock.Unlock()
+
+		c.epochValidatorsCache.reset()
+	})
+}
+
+// isClosed indicates whether the runtime is closed
+func (c *consensusRuntime) isClosed() bool {
+	select {
+	case <-c.closeCh:
+		return true
+	default:
+		return false
+	}
 }
 
 // initStateSyncManager initializes state sync manager
 // if bridge is not enabled, then a dummy state sync manager will be used
 func (c *consensusRuntime) initStateSyncManager(logger hcf.Logger) error {
 	if c.IsBridgeEnabled() {
+		aggregateSigner, err := newAggregateSigner(c.config.PolyBFTConfig.Bridge.CommitmentSignatureScheme,
+			c.config.PolyBFTConfig.Bridge.SignatureAggregationWorkers)
+		if err != nil {
+			return err
+		}
+
+		merkleHasher, err := newMerkleHasher(c.config.PolyBFTConfig.Bridge.MerkleHashScheme)
+		if err != nil {
+			return err
+		}
+
 		stateSenderAddr := c.config.PolyBFTConfig.Bridge.StateSenderAddr
 		stateSyncManager := newStateSyncManager(
-			logger.Named("state-sync-manager"),
+			c.config.logLevels.named(logger, "state-sync-manager"),
 			c.config.State,
 			&stateSyncConfig{
-				key:                   c.config.Key,
-				stateSenderAddr:       stateSenderAddr,
-				stateSenderStartBlock: c.config.PolyBFTConfig.Bridge.EventTrackerStartBlocks[stateSenderAddr],
-				jsonrpcAddr:           c.config.PolyBFTConfig.Bridge.JSONRPCEndpoint,
-				dataDir:               c.config.DataDir,
-				topic:                 c.config.bridgeTopic,
-				maxCommitmentSize:     maxCommitmentSize,
-				numBlockConfirmations: c.config.numBlockConfirmations,
+				key:                     c.config.Key,
+				stateSenderAddr:         stateSenderAddr,
+				stateSenderStartBlock:   c.config.PolyBFTConfig.Bridge.EventTrackerStartBlocks[stateSenderAddr],
+				jsonrpcAddr:             c.config.PolyBFTConfig.Bridge.JSONRPCEndpoint,
+				dataDir:                 c.config.DataDir,
+				topic:                   c.config.bridgeTopic,
+				maxCommitmentSize:       c.config.PolyBFTConfig.MaxCommitmentSize,
+				minCommitmentSize:       c.config.PolyBFTConfig.Bridge.MinCommitmentSize,
+				numBlockConfirmations:   c.config.numBlockConfirmations,
+				rpcTimeout:              c.config.PolyBFTConfig.Bridge.JSONRPCTimeout.Duration,
+				rpcRetries:              c.config.PolyBFTConfig.Bridge.JSONRPCRetries,
+				trackerLogLevel:         c.config.logLevels.level("event_tracker"),
+				aggregateSigner:         aggregateSigner,
+				merkleHasher:            merkleHasher,
+				merkleHashScheme:        c.config.PolyBFTConfig.Bridge.MerkleHashScheme,
+				forceSprintCommitments:  c.config.PolyBFTConfig.Bridge.ForceSprintCommitments,
+				alignCommitments:        c.config.PolyBFTConfig.Bridge.SprintAlignedCommitments,
+				eventsBatchSize:         c.config.PolyBFTConfig.Bridge.EventsBatchSize,
+				eventsDecodeWorkers:     c.config.PolyBFTConfig.Bridge.EventsDecodeWorkers,
+				resubmissionTimeout:     c.config.PolyBFTConfig.Bridge.CommitmentResubmissionTimeout,
+				proofFinalityDepth:      c.config.PolyBFTConfig.Bridge.ProofFinalityDepth,
+				adaptiveCommitmentSize:  c.config.PolyBFTConfig.Bridge.AdaptiveCommitmentSize,
+				quorumWaitWarnThreshold: c.config.PolyBFTConfig.commitmentQuorumWaitWarnThreshold(),
+				commitmentGapPolicy:     c.config.PolyBFTConfig.Bridge.CommitmentGapPolicy,
+				maxVoteEpochLead:        c.config.PolyBFTConfig.Bridge.MaxVoteEpochLead,
+				proofPruningInterval:    c.config.PolyBFTConfig.Bridge.ProofPruningInterval,
 			},
 		)
 
 		c.stateSyncManager = stateSyncManager
-	} else {
-		c.stateSyncManager = &dummyStateSyncManager{}
+
+		if err := stateSyncManager.Init(); err != nil {
+			return err
+		}
+
+		systemState, err := c.getSystemState(c.lastBuiltBlock)
+		if err != nil {
+			return fmt.Errorf("failed to get system state: %w", err)
+		}
+
+		// stored commitments can be behind the contract if the db was restored from a backup
+		return stateSyncManager.reconcileCommitmentGap(systemState)
 	}
 
+	c.stateSyncManager = &dummyStateSyncManager{}
+
 	return c.stateSyncManager.Init()
 }
 
@@ -207,7 +411,7 @@ func (c *consensusRuntime) initCheckpointManager(logger hcf.Logger) error {
 			txRelayer,
 			c.config.blockchain,
 			c.config.polybftBackend,
-			logger.Named("checkpoint_manager"),
+			c.config.logLevels.named(logger, "checkpoint_manager"),
 			c.state)
 	} else {
 		c.checkpointManager = &dummyCheckpointManager{}
@@ -224,7 +428,7 @@ func (c *consensusRuntime) initStakeManager(logger hcf.Logger) error {
 	}
 
 	c.stakeManager = newStakeManager(
-		logger.Named("stake-manager"),
+		c.config.logLevels.named(logger, "stake-manager"),
 		c.state,
 		rootRelayer,
 		wallet.NewEcdsaSigner(c.config.Key),
@@ -258,12 +462,47 @@ func (c *consensusRuntime) getGuardedData() (guardedDataDTO, error) {
 	}, nil
 }
 
+// getLastBuiltBlock returns the header of the last processed block in a thread-safe manner.
+// Header must not be modified, since it is shared with the runtime.
+func (c *consensusRuntime) getLastBuiltBlock() *types.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.lastBuiltBlock
+}
+
 func (c *consensusRuntime) IsBridgeEnabled() bool {
 	return c.config.PolyBFTConfig.IsBridgeEnabled()
 }
 
+// OnReorg is called by the reorg notification subscription whenever the chain gets reorganized,
+// so that bridge data which might be based on the orphaned blocks is not built until the new canonical chain
+// is processed
+func (c *consensusRuntime) OnReorg(event *blockchain.ReorgEvent) {
+	c.logger.Info("chain reorganized",
+		"commonAncestor", event.CommonAncestor.Number,
+		"orphaned", len(event.Orphaned),
+		"newCanonical", len(event.NewCanonical))
+
+	c.stateSyncManager.ReorgStarted(event)
+}
+
 // OnBlockInserted is called whenever fsm or syncer inserts new block
 func (c *consensusRuntime) OnBlockInserted(fullBlock *types.FullBlock) {
+	if !c.handleInsertedBlock(fullBlock) {
+		return
+	}
+
+	// commitment is built once the last block of a sprint is inserted, so it gets submitted
+	// at the end of the next sprint (runtime lock is not held, since building it may take a while)
+	if err := c.stateSyncManager.PostSprint(); err != nil {
+		c.logger.Error("failed to build a commitment at the end of sprint", "error", err)
+	}
+}
+
+// handleInsertedBlock updates the runtime state and notifies its managers about the inserted block,
+// while holding the runtime lock. It returns true if the inserted block is the last block of a sprint.
+func (c *consensusRuntime) handleInsertedBlock(fullBlock *types.FullBlock) bool {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
@@ -271,7 +510,7 @@ func (c *consensusRuntime) OnBlockInserted(fullBlock *types.FullBlock) {
 		c.logger.Debug("on block inserted already handled",
 			"current", c.lastBuiltBlock.Number, "block", fullBlock.Block.Number())
 
-		return
+		return false
 	}
 
 	if err := updateBlockMetrics(fullBlock.Block, c.lastBuiltBlock); err != nil {
@@ -286,7 +525,8 @@ func (c *consensusRuntime) OnBlockInserted(fullBlock *types.FullBlock) {
 		err   error
 		// calculation of epoch and sprint end does not consider slashing currently
 
-		isEndOfEpoch = c.isFixedSizeOfEpochMet(fullBlock.Block.Header.Number, epoch)
+		isEndOfEpoch  = c.isFixedSizeOfEpochMet(fullBlock.Block.Header.Number, epoch)
+		isEndOfSprint = c.isFixedSizeOfSprintMet(fullBlock.Block.Header.Number, epoch)
 	)
 
 	postBlock := &PostBlockRequest{FullBlock: fullBlock, Epoch: epoch.Number, IsEpochEndingBlock: isEndOfEpoch}
@@ -315,17 +555,31 @@ func (c *consensusRuntime) OnBlockInserted(fullBlock *types.FullBlock) {
 		if epoch, err = c.restartEpoch(fullBlock.Block.Header); err != nil {
 			c.logger.Error("failed to restart epoch after block inserted", "error", err)
 
-			return
+			return false
 		}
 	}
 
 	// finally update runtime state (lastBuiltBlock, epoch, proposerSnapshot)
 	c.epoch = epoch
 	c.lastBuiltBlock = fullBlock.Block.Header
+
+	return isEndOfSprint
 }
 
 // FSM creates a new instance of fsm
 func (c *consensusRuntime) FSM() error {
+	if c.isClosed() {
+		return errRuntimeClosed
+	}
+
+	if c.isObserver() {
+		return errObserverMode
+	}
+
+	if c.config.syncBeforeBlockProduction && !c.isSynced() {
+		return errNotSynced
+	}
+
 	sharedData, err := c.getGuardedData()
 	if err != nil {
 		return fmt.Errorf("cannot create fsm: %w", err)
@@ -337,6 +591,12 @@ func (c *consensusRuntime) FSM() error {
 		return errNotAValidator
 	}
 
+	// chain head could have advanced by syncing, in which case node should sync instead of building on a stale parent
+	if head := c.config.blockchain.CurrentHeader(); head.Number > parent.Number+c.maxParentLag() {
+		return fmt.Errorf("%w: parent block %d is %d blocks behind the chain head %d, node needs to sync",
+			errStaleParent, parent.Number, head.Number-parent.Number, head.Number)
+	}
+
 	blockBuilder, err := c.config.blockchain.NewBlockBuilder(
 		parent,
 		types.Address(c.config.Key.Address()),
@@ -349,6 +609,13 @@ func (c *consensusRuntime) FSM() error {
 		return fmt.Errorf("cannot create block builder for fsm: %w", err)
 	}
 
+	if !isParentMinerValidator(parent, epoch) {
+		// proposer selection relies on the proposer snapshot rather than on the parent miner,
+		// so the block is still built, but a corrupt parent header is worth surfacing
+		c.logger.Warn("parent block miner is not a validator of the current epoch",
+			"parent", parent.Number, "miner", types.BytesToAddress(parent.Miner), "epoch", epoch.Number)
+	}
+
 	pendingBlockNumber := parent.Number + 1
 	// calculation of epoch and sprint end does not consider slashing currently
 	isEndOfSprint := c.isFixedSizeOfSprintMet(pendingBlockNumber, epoch)
@@ -373,13 +640,23 @@ func (c *consensusRuntime) FSM() error {
 		isEndOfEpoch:      isEndOfEpoch,
 		isEndOfSprint:     isEndOfSprint,
 		proposerSnapshot:  proposerSnapshot,
-		logger:            c.logger.Named("fsm"),
+		proposalTimeout:   c.config.PolyBFTConfig.proposalTimeout(),
+		logger:            c.config.logLevels.named(c.logger, "fsm"),
+
+		strictStateTxVerification: c.config.PolyBFTConfig.StrictStateTxVerification,
+		stateSyncManager:          c.stateSyncManager,
 	}
 
 	if isEndOfSprint {
 		commitment, err := c.stateSyncManager.Commitment()
 		if err != nil {
-			return err
+			if !c.isBridgeDataSkippedOnError() {
+				return err
+			}
+
+			// degrade to a block without bridge state transactions, rather than halting the block production
+			c.logger.Warn("could not resolve commitment, building a block without bridge state transactions",
+				"block", pendingBlockNumber, "error", err)
 		}
 
 		ff.proposerCommitmentToRegister = commitment
@@ -411,6 +688,34 @@ func (c *consensusRuntime) FSM() error {
 	return nil
 }
 
+// isParentMinerValidator checks whether the miner of the given parent block is a validator of the given epoch.
+// Genesis block and the blocks of the previous epochs (mined by the previous validator sets) are not checked.
+func isParentMinerValidator(parent *types.Header, epoch *epochMetadata) bool {
+	if parent.Number == 0 || parent.Number < epoch.FirstBlockInEpoch {
+		return true
+	}
+
+	return epoch.Validators.ContainsAddress(types.BytesToAddress(parent.Miner))
+}
+
+// getProposalTimeout returns the proposal timeout of the current fsm (zero if there is no fsm)
+func (c *consensusRuntime) getProposalTimeout() time.Duration {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.fsm == nil {
+		return 0
+	}
+
+	return c.fsm.proposalTimeout
+}
+
+// isBridgeDataSkippedOnError indicates whether a block should be built without bridge state transactions
+// in case bridge data can not be resolved
+func (c *consensusRuntime) isBridgeDataSkippedOnError() bool {
+	return c.IsBridgeEnabled() && c.config.PolyBFTConfig.Bridge.SkipBridgeDataOnError
+}
+
 // restartEpoch resets the previously run epoch and moves to the next one
 // returns *epochMetadata different from nil if the lastEpoch is not the current one and everything was successful
 func (c *consensusRuntime) restartEpoch(header *types.Header) (*epochMetadata, error) {
@@ -439,6 +744,10 @@ func (c *consensusRuntime) restartEpoch(header *types.Header) (*epochMetadata, e
 		return nil, fmt.Errorf("restart epoch - cannot get validators: %w", err)
 	}
 
+	if err := c.validateValidatorSetSize(validatorSet); err != nil {
+		return nil, fmt.Errorf("restart epoch - epoch %d: %w", epochNumber, err)
+	}
+
 	updateEpochMetrics(epochMetadata{
 		Number:     epochNumber,
 		Validators: validatorSet,
@@ -449,11 +758,15 @@ func (c *consensusRuntime) restartEpoch(header *types.Header) (*epochMetadata, e
 		return nil, err
 	}
 
-	if err := c.state.EpochStore.cleanEpochsFromDB(); err != nil {
-		c.logger.Error("Could not clean previous epochs from db.", "error", err)
+	// epoch is already in db if node was restarted in the middle of it,
+	// in which case its data (e.g. votes for pending commitments) is kept
+	if !c.state.EpochStore.isEpochInserted(epochNumber) {
+		if err := c.state.EpochStore.cleanEpochsFromDB(); err != nil {
+			c.logger.Error("Could not clean previous epochs from db.", "error", err)
+		}
 	}
 
-	if err := c.state.EpochStore.insertEpoch(epochNumber); err != nil {
+	if err := c.state.EpochStore.insertEpoch(epochNumber, validatorSet); err != nil {
 		return nil, fmt.Errorf("an error occurred while inserting new epoch in db. Reason: %w", err)
 	}
 
@@ -487,6 +800,26 @@ func (c *consensusRuntime) restartEpoch(header *types.Header) (*epochMetadata, e
 	}, nil
 }
 
+// validateValidatorSetSize checks that the validator set of a new epoch is not empty
+// and that its size is within the configured bounds (a zero bound is not enforced)
+func (c *consensusRuntime) validateValidatorSetSize(validatorSet validator.AccountSet) error {
+	size := uint64(validatorSet.Len())
+	if size == 0 {
+		return fmt.Errorf("%w: validator set is empty", errInvalidValidatorSetSize)
+	}
+
+	minSize, maxSize := c.config.PolyBFTConfig.MinValidatorSetSize, c.config.PolyBFTConfig.MaxValidatorSetSize
+	if minSize > 0 && size < minSize {
+		return fmt.Errorf("%w: %d validators, expected at least %d", errInvalidValidatorSetSize, size, minSize)
+	}
+
+	if maxSize > 0 && size > maxSize {
+		return fmt.Errorf("%w: %d validators, expected at most %d", errInvalidValidatorSetSize, size, maxSize)
+	}
+
+	return nil
+}
+
 // calculateCommitEpochInput calculates commit epoch input data for blocks starting from the last built block
 // in the current epoch, and ending at the last block of previous epoch
 func (c *consensusRuntime) calculateCommitEpochInput(
@@ -494,7 +827,9 @@ func (c *consensusRuntime) calculateCommitEpochInput(
 	epoch *epochMetadata,
 ) (*contractsapi.CommitEpochValidatorSetFn,
 	*contractsapi.DistributeRewardForRewardPoolFn, error) {
-	uptimeCounter := map[types.Address]int64{}
+	// signers of the looked back blocks belong to the previous epoch validator set,
+	// so up to two full validator sets can be counted
+	uptimeCounter := NewUptimeCounter(2 * c.config.PolyBFTConfig.MaxValidatorSetSize)
 	blockHeader := currentBlock
 	epochID := epoch.Number
 	totalBlocks := int64(0)
@@ -507,11 +842,7 @@ func (c *consensusRuntime) calculateCommitEpochInput(
 
 		totalBlocks++
 
-		for _, a := range signers.GetAddresses() {
-			uptimeCounter[a]++
-		}
-
-		return nil
+		return uptimeCounter.AddSigners(signers.GetAddresses())
 	}
 
 	blockExtra, err := GetIbftExtra(currentBlock.ExtraData)
@@ -525,7 +856,7 @@ func (c *consensusRuntime) calculateCommitEpochInput(
 			return nil, nil, err
 		}
 
-		blockHeader, blockExtra, err = getBlockData(blockHeader.Number-1, c.config.blockchain)
+		blockHeader, blockExtra, err = getUptimeBlockData(blockHeader.Number-1, c.config.blockchain)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -537,6 +868,10 @@ func (c *consensusRuntime) calculateCommitEpochInput(
 		for i := 0; i < commitEpochLookbackSize; i++ {
 			validators, err := c.config.polybftBackend.GetValidators(blockHeader.Number-2, nil)
 			if err != nil {
+				if c.isUptimeLookbackSkipped(blockHeader.Number, err) {
+					break
+				}
+
 				return nil, nil, err
 			}
 
@@ -544,8 +879,14 @@ func (c *consensusRuntime) calculateCommitEpochInput(
 				return nil, nil, err
 			}
 
-			blockHeader, blockExtra, err = getBlockData(blockHeader.Number-1, c.config.blockchain)
+			previousBlockNumber := blockHeader.Number - 1
+
+			blockHeader, blockExtra, err = getUptimeBlockData(previousBlockNumber, c.config.blockchain)
 			if err != nil {
+				if c.isUptimeLookbackSkipped(previousBlockNumber, err) {
+					break
+				}
+
 				return nil, nil, err
 			}
 		}
@@ -554,7 +895,7 @@ func (c *consensusRuntime) calculateCommitEpochInput(
 	// include the data in the uptime counter in a deterministic way
 	addrSet := []types.Address{}
 
-	for addr := range uptimeCounter {
+	for addr := range uptimeCounter.signedBlocks {
 		addrSet = append(addrSet, addr)
 	}
 
@@ -565,9 +906,14 @@ func (c *consensusRuntime) calculateCommitEpochInput(
 	})
 
 	for i, addr := range addrSet {
+		signedBlocks := int64(uptimeCounter.SignedBlocks(addr))
+		if c.uptimeRewardCurve != nil {
+			signedBlocks = applyUptimeRewardCurve(c.uptimeRewardCurve, signedBlocks, totalBlocks)
+		}
+
 		uptime[i] = &contractsapi.Uptime{
 			Validator:    addr,
-			SignedBlocks: new(big.Int).SetInt64(uptimeCounter[addr]),
+			SignedBlocks: new(big.Int).SetInt64(signedBlocks),
 		}
 	}
 
@@ -588,16 +934,310 @@ func (c *consensusRuntime) calculateCommitEpochInput(
 	return commitEpoch, distributeRewards, nil
 }
 
+// isUptimeLookbackSkipped checks whether the rest of the uptime lookback into the previous epoch
+// is skipped because of the given error, which happens if the error is caused by unavailable chain history
+// and skipping is enabled by the configuration
+func (c *consensusRuntime) isUptimeLookbackSkipped(blockNumber uint64, err error) bool {
+	if !c.config.PolyBFTConfig.SkipUnavailableUptimeLookback ||
+		(!errors.Is(err, blockchain.ErrNoBlock) && !errors.Is(err, blockchain.ErrBlockNotFound)) {
+		return false
+	}
+
+	c.logger.Warn("chain history is not available, skipping the rest of the uptime lookback",
+		"block", blockNumber, "error", err)
+
+	return true
+}
+
 // GenerateExitProof generates proof of exit and is a bridge endpoint store function
 func (c *consensusRuntime) GenerateExitProof(exitID uint64) (types.Proof, error) {
 	return c.checkpointManager.GenerateExitProof(exitID)
 }
 
+// PendingRegisterCommitment returns the commitment which would be registered by the proposer
+// of the next end of sprint block, without altering the state of the runtime.
+// ErrNoCommitmentToRegister is returned if there is no such commitment.
+func (c *consensusRuntime) PendingRegisterCommitment() (*CommitmentMessageSigned, error) {
+	commitment, err := c.stateSyncManager.Commitment()
+	if err != nil {
+		return nil, err
+	}
+
+	if commitment == nil {
+		return nil, ErrNoCommitmentToRegister
+	}
+
+	return commitment, nil
+}
+
 // GetStateSyncProof returns the proof for the state sync
 func (c *consensusRuntime) GetStateSyncProof(stateSyncID uint64) (types.Proof, error) {
 	return c.stateSyncManager.GetStateSyncProof(stateSyncID)
 }
 
+// IsStateSyncExecuted checks whether given state sync is executed as of the last built block
+func (c *consensusRuntime) IsStateSyncExecuted(stateSyncID uint64) (bool, error) {
+	lastBuiltBlock := c.getLastBuiltBlock()
+
+	if isExecuted, exists := c.stateSyncExecutionCache.get(lastBuiltBlock.Hash, stateSyncID); exists {
+		return isExecuted, nil
+	}
+
+	systemState, err := c.getSystemState(lastBuiltBlock)
+	if err != nil {
+		return false, err
+	}
+
+	isExecuted, err := systemState.IsStateSyncExecuted(stateSyncID)
+	if err != nil {
+		return false, err
+	}
+
+	c.stateSyncExecutionCache.set(lastBuiltBlock.Hash, stateSyncID, isExecuted)
+
+	return isExecuted, nil
+}
+
+// HealthStatus describes the liveness of the consensus and the bridge, as seen by the node
+type HealthStatus struct {
+	// Synced indicates whether the node reached the network head after it started
+	Synced bool
+	// ActiveValidator indicates whether the node is amongst the current validators
+	ActiveValidator bool
+	// LastBlockNumber is the number of the last processed block
+	LastBlockNumber uint64
+	// LastBlockTime is the timestamp of the last processed block
+	LastBlockTime time.Time
+	// BridgeEnabled indicates whether the rootchain bridge is enabled
+	BridgeEnabled bool
+	// PendingCommitments is the number of built commitments which are pending to be submitted
+	PendingCommitments int
+	// QuorumReachable indicates that there is no pending commitment awaiting quorum beyond the warning threshold
+	QuorumReachable bool
+}
+
+// Health returns the liveness status of the consensus and the bridge
+func (c *consensusRuntime) Health() *HealthStatus {
+	lastBuiltBlock := c.getLastBuiltBlock()
+	stateSyncStatus := c.stateSyncManager.Status()
+
+	return &HealthStatus{
+		Synced:             c.isSynced(),
+		ActiveValidator:    c.isActiveValidator(),
+		LastBlockNumber:    lastBuiltBlock.Number,
+		LastBlockTime:      time.Unix(int64(lastBuiltBlock.Timestamp), 0),
+		BridgeEnabled:      c.IsBridgeEnabled(),
+		PendingCommitments: stateSyncStatus.PendingCommitments,
+		QuorumReachable:    stateSyncStatus.QuorumReachable,
+	}
+}
+
+// PendingCommitmentInfo describes a stored commitment, which contains state syncs that are not executed yet
+type PendingCommitmentInfo struct {
+	// StartID is the id of the first state sync of the commitment
+	StartID uint64
+	// EndID is the id of the last state sync of the commitment
+	EndID uint64
+	// PendingStateSyncIDs are ids of the commitment state syncs, which are not executed as of the last built block
+	PendingStateSyncIDs []uint64
+}
+
+// GetPendingCommitments returns stored commitments, which contain state syncs not executed
+// as of the last built block, in ascending order of their state syncs. Only the state syncs committed
+// on-chain as of the last built block, which are not already known to be executed (see pruneExecutedProofs),
+// are checked.
+func (c *consensusRuntime) GetPendingCommitments() ([]*PendingCommitmentInfo, error) {
+	systemState, err := c.getSystemState(c.getLastBuiltBlock())
+	if err != nil {
+		return nil, err
+	}
+
+	nextCommittedIndex, err := systemState.GetNextCommittedIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next committed index: %w", err)
+	}
+
+	fromID, err := c.state.StateSyncStore.getPrunedProofsIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pruned proofs index: %w", err)
+	}
+
+	if nextCommittedIndex <= fromID {
+		return nil, nil
+	}
+
+	toID := nextCommittedIndex - 1
+
+	commitments, err := c.state.StateSyncStore.getCommitmentMessages(fromID, toID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stored commitments: %w", err)
+	}
+
+	var pending []*PendingCommitmentInfo
+
+	for _, commitment := range commitments {
+		info := &PendingCommitmentInfo{
+			StartID: commitment.Message.StartID.Uint64(),
+			EndID:   commitment.Message.EndID.Uint64(),
+		}
+
+		lastID := common.Min(info.EndID, toID)
+		for stateSyncID := common.Max(info.StartID, fromID); stateSyncID <= lastID; stateSyncID++ {
+			isExecuted, err := c.IsStateSyncExecuted(stateSyncID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check whether state sync %d is executed: %w", stateSyncID, err)
+			}
+
+			if !isExecuted {
+				info.PendingStateSyncIDs = append(info.PendingStateSyncIDs, stateSyncID)
+			}
+		}
+
+		if len(info.PendingStateSyncIDs) > 0 {
+			pending = append(pending, info)
+		}
+	}
+
+	return pending, nil
+}
+
+// EpochForBlock returns number of the epoch which contains the given block
+func (c *consensusRuntime) EpochForBlock(blockNumber uint64) uint64 {
+	return getEpochNumberForBlock(blockNumber, c.config.PolyBFTConfig.EpochSize)
+}
+
+// BlockRangeForEpoch returns numbers of the first and the last block of the given epoch
+// (genesis block is the only block of epoch 0)
+func (c *consensusRuntime) BlockRangeForEpoch(epoch uint64) (first, last uint64) {
+	if isGenesisEpoch(epoch) {
+		return 0, 0
+	}
+
+	epochSize := c.config.PolyBFTConfig.EpochSize
+
+	return getEndEpochBlockNumber(epoch-1, epochSize) + 1, getEndEpochBlockNumber(epoch, epochSize)
+}
+
+// CurrentValidators returns a copy of the validator set of the current epoch,
+// which callers can freely modify without affecting the runtime.
+func (c *consensusRuntime) CurrentValidators() (validator.AccountSet, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.epoch == nil {
+		return nil, errEpochNotInitialized
+	}
+
+	return c.epoch.Validators.Copy(), nil
+}
+
+// maxParentLag returns the maximum number of blocks the parent of a block being built
+// can be behind the chain head
+func (c *consensusRuntime) maxParentLag() uint64 {
+	if c.config.maxParentLag == 0 {
+		return defaultMaxParentLag
+	}
+
+	return c.config.maxParentLag
+}
+
+// NextEpochValidators returns the projected validator set of the next epoch, i.e. the current validator set
+// with the pending stake changes (validators joining, leaving or changing their voting power) applied.
+// Projection can still change until the current epoch ends. Projection is calculated on a copy of the current
+// validator set, which callers can freely modify without affecting the runtime.
+func (c *consensusRuntime) NextEpochValidators() (validator.AccountSet, error) {
+	c.lock.RLock()
+	epoch := c.epoch
+	c.lock.RUnlock()
+
+	if epoch == nil {
+		return nil, errEpochNotInitialized
+	}
+
+	validators := epoch.Validators.Copy()
+
+	delta, err := c.stakeManager.UpdateValidatorSet(epoch.Number, validators.Copy())
+	if err != nil {
+		return nil, fmt.Errorf("cannot calculate validator set of epoch %d: %w", epoch.Number+1, err)
+	}
+
+	return validators.ApplyDelta(delta)
+}
+
+// GetValidatorsForEpochNumber returns validator set of the given epoch.
+// Validator set of an epoch is the one resolved on the last block of its preceding epoch.
+func (c *consensusRuntime) GetValidatorsForEpochNumber(epoch uint64) (validator.AccountSet, error) {
+	if isGenesisEpoch(epoch) {
+		return nil, errInvalidEpochNumber
+	}
+
+	if validators, exists := c.epochValidatorsCache.get(epoch); exists {
+		return validators, nil
+	}
+
+	blockNumber := getEndEpochBlockNumber(epoch-1, c.config.PolyBFTConfig.EpochSize)
+
+	validators, err := c.config.polybftBackend.GetValidators(blockNumber, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get validators of epoch %d (block %d): %w", epoch, blockNumber, err)
+	}
+
+	c.epochValidatorsCache.set(epoch, validators)
+
+	return validators, nil
+}
+
+// ImportValidatorSnapshot persists a trusted validator set as the validator set of the epoch containing given block,
+// so that it does not need to be derived by replaying the chain.
+// Snapshot is rejected if its hash does not match the given checksum.
+func (c *consensusRuntime) ImportValidatorSnapshot(block uint64, set validator.AccountSet, checksum types.Hash) error {
+	epoch := c.EpochForBlock(block)
+	if isGenesisEpoch(epoch) {
+		return fmt.Errorf("%w: block %d belongs to the genesis epoch", errInvalidEpochNumber, block)
+	}
+
+	if set.Len() == 0 {
+		return fmt.Errorf("validator snapshot for epoch %d is empty", epoch)
+	}
+
+	hash, err := set.Hash()
+	if err != nil {
+		return fmt.Errorf("cannot calculate validator snapshot hash: %w", err)
+	}
+
+	if hash != checksum {
+		return fmt.Errorf("%w: expected %s, got %s", errValidatorSnapshotChecksum, checksum, hash)
+	}
+
+	// validator set of an epoch is the one resolved on the last block of its preceding epoch
+	snapshot := &validatorSnapshot{
+		Epoch:            epoch - 1,
+		EpochEndingBlock: getEndEpochBlockNumber(epoch-1, c.config.PolyBFTConfig.EpochSize),
+		Snapshot:         set.Copy(),
+	}
+
+	if err := c.state.EpochStore.insertValidatorSnapshot(snapshot); err != nil {
+		return fmt.Errorf("cannot store validator snapshot for epoch %d: %w", epoch, err)
+	}
+
+	c.epochValidatorsCache.set(epoch, snapshot.Snapshot)
+
+	c.lock.Lock()
+	if c.epoch != nil && c.epoch.Number == epoch {
+		// epoch metadata is shared by shallow copies, so it is replaced rather than modified
+		c.epoch = &epochMetadata{
+			Number:            c.epoch.Number,
+			Validators:        snapshot.Snapshot,
+			FirstBlockInEpoch: c.epoch.FirstBlockInEpoch,
+		}
+	}
+	c.lock.Unlock()
+
+	c.logger.Info("Imported validator snapshot", "epoch", epoch, "block", block, "validators", set.Len())
+
+	return nil
+}
+
 // setIsActiveValidator updates the activeValidatorFlag field
 func (c *consensusRuntime) setIsActiveValidator(isActiveValidator bool) {
 	c.activeValidatorFlag.Store(isActiveValidator)
@@ -608,25 +1248,68 @@ func (c *consensusRuntime) isActiveValidator() bool {
 	return c.activeValidatorFlag.Load()
 }
 
+// SetSynced signals whether the node reached the network head. Until it does, FSM can not be created
+// (if sync before block production is configured), while blocks, votes and bridge events are still tracked.
+func (c *consensusRuntime) SetSynced(synced bool) {
+	c.syncedFlag.Store(synced)
+}
+
+// isSynced indicates if node reached the network head
+func (c *consensusRuntime) isSynced() bool {
+	return c.syncedFlag.Load()
+}
+
+// isObserver indicates if node is running in observer mode, meaning it never participates in consensus
+func (c *consensusRuntime) isObserver() bool {
+	return c.config.mode == ObserverRuntimeMode
+}
+
 // isFixedSizeOfEpochMet checks if epoch reached its end that was configured by its default size
 // this is only true if no slashing occurred in the given epoch
 func (c *consensusRuntime) isFixedSizeOfEpochMet(blockNumber uint64, epoch *epochMetadata) bool {
 	return epoch.FirstBlockInEpoch+c.config.PolyBFTConfig.EpochSize-1 == blockNumber
 }
 
-// isFixedSizeOfSprintMet checks if an end of an sprint is reached with the current block
+// isFixedSizeOfSprintMet checks if an end of an sprint is reached with the current block.
+// If ragged sprint is allowed, the last (shorter) sprint of an epoch ends together with the epoch.
 func (c *consensusRuntime) isFixedSizeOfSprintMet(blockNumber uint64, epoch *epochMetadata) bool {
+	if c.config.PolyBFTConfig.AllowRaggedSprint && c.isFixedSizeOfEpochMet(blockNumber, epoch) {
+		return true
+	}
+
 	return (blockNumber-epoch.FirstBlockInEpoch+1)%c.config.PolyBFTConfig.SprintSize == 0
 }
 
-// getSystemState builds SystemState instance for the most current block header
+// getSystemState builds SystemState instance for the most current block header.
+// The epoch and the next committed index reads are bounded by the bridge JSON-RPC timeout,
+// and their transient failures are retried up to the configured number of times
+// (the bridge JSON-RPC retries, unless overridden by the node configuration),
+// so that they neither block nor abort the caller.
 func (c *consensusRuntime) getSystemState(header *types.Header) (SystemState, error) {
 	provider, err := c.config.blockchain.GetStateProviderForBlock(header)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.config.blockchain.GetSystemState(provider), nil
+	var (
+		retries = c.config.systemStateRetries
+		timeout time.Duration
+	)
+
+	if c.config.PolyBFTConfig != nil && c.config.PolyBFTConfig.IsBridgeEnabled() {
+		timeout = c.config.PolyBFTConfig.Bridge.JSONRPCTimeout.Duration
+
+		if retries == 0 {
+			retries = c.config.PolyBFTConfig.Bridge.JSONRPCRetries
+		}
+	}
+
+	systemState := c.config.blockchain.GetSystemState(provider)
+	if retries == 0 && timeout == 0 {
+		return systemState, nil
+	}
+
+	return newRetryingSystemState(systemState, retries, timeout), nil
 }
 
 func (c *consensusRuntime) IsValidProposal(rawProposal []byte) bool {
@@ -920,8 +1603,10 @@ func (c *consensusRuntime) BuildRoundChangeMessage(
 // getFirstBlockOfEpoch returns the first block of epoch in which provided header resides
 func (c *consensusRuntime) getFirstBlockOfEpoch(epochNumber uint64, latestHeader *types.Header) (uint64, error) {
 	if latestHeader.Number == 0 {
-		// if we are starting the chain, we know that the first block is block 1
-		return 1, nil
+		// if we are starting the chain, we know that the first epoch is starting
+		first, _ := firstEpochRange(c.config.PolyBFTConfig.EpochSize)
+
+		return first, nil
 	}
 
 	blockHeader := latestHeader
@@ -967,3 +1652,86 @@ func getSealersForBlock(sealersCounter map[types.Address]uint64,
 
 	return nil
 }
+
+// stateSyncExecutionCache holds state sync execution statuses queried on a single block
+type stateSyncExecutionCache struct {
+	lock      sync.Mutex
+	blockHash types.Hash
+	statuses  map[uint64]bool
+}
+
+// get returns cached execution status of given state sync, if it was queried on the given block
+func (s *stateSyncExecutionCache) get(blockHash types.Hash, stateSyncID uint64) (bool, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.blockHash != blockHash {
+		return false, false
+	}
+
+	isExecuted, exists := s.statuses[stateSyncID]
+
+	return isExecuted, exists
+}
+
+// set caches execution status of given state sync queried on the given block,
+// invalidating statuses queried on any other block
+func (s *stateSyncExecutionCache) set(blockHash types.Hash, stateSyncID uint64, isExecuted bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.blockHash != blockHash || s.statuses == nil {
+		s.blockHash = blockHash
+		s.statuses = make(map[uint64]bool)
+	}
+
+	s.statuses[stateSyncID] = isExecuted
+}
+
+// epochValidatorsCacheSize is the number of the most recently used epoch validator sets kept in memory
+const epochValidatorsCacheSize = 64
+
+// epochValidatorsCache is a cache of validator sets by epoch number, bounded to the epochValidatorsCacheSize
+// most recently used epochs. Validator sets are copied in and out, so that cached sets can not be modified.
+type epochValidatorsCache struct {
+	lock  sync.Mutex
+	cache *lru.Cache
+}
+
+// get returns a copy of the cached validator set of the given epoch
+func (e *epochValidatorsCache) get(epoch uint64) (validator.AccountSet, bool) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.cache == nil {
+		return nil, false
+	}
+
+	validators, exists := e.cache.Get(epoch)
+	if !exists {
+		return nil, false
+	}
+
+	return validators.(validator.AccountSet).Copy(), true //nolint:forcetypeassert
+}
+
+// set caches a copy of the validator set of the given epoch
+func (e *epochValidatorsCache) set(epoch uint64, validators validator.AccountSet) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.cache == nil {
+		// error is returned only for a non-positive cache size
+		e.cache, _ = lru.New(epochValidatorsCacheSize)
+	}
+
+	e.cache.Add(epoch, validators.Copy())
+}
+
+// reset drops all cached validator sets
+func (e *epochValidatorsCache) reset() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.cache = nil
+}
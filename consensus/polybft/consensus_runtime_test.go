@@ -1,14 +1,18 @@
 package polybft
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"math/big"
 	"math/rand"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/0xPolygon/go-ibft/messages/proto"
+	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/consensus"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/bitmap"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
@@ -177,6 +181,32 @@ func TestConsensusRuntime_isFixedSizeOfSprintMet_ReachedEnd(t *testing.T) {
 	}
 }
 
+func TestConsensusRuntime_isFixedSizeOfSprintMet_RaggedSprint(t *testing.T) {
+	t.Parallel()
+
+	runtime := &consensusRuntime{
+		config: &runtimeConfig{
+			PolyBFTConfig: &PolyBFTConfig{EpochSize: 10, SprintSize: 4, AllowRaggedSprint: true},
+		},
+		epoch: &epochMetadata{FirstBlockInEpoch: 1},
+	}
+
+	sprintEnds := make([]uint64, 0)
+
+	for blockNumber := uint64(1); blockNumber <= 10; blockNumber++ {
+		if runtime.isFixedSizeOfSprintMet(blockNumber, runtime.epoch) {
+			sprintEnds = append(sprintEnds, blockNumber)
+		}
+	}
+
+	// the last sprint of the epoch is two blocks long
+	require.Equal(t, []uint64{4, 8, 10}, sprintEnds)
+
+	// ragged sprint is not closed at the end of the epoch, unless it is allowed
+	runtime.config.PolyBFTConfig.AllowRaggedSprint = false
+	require.False(t, runtime.isFixedSizeOfSprintMet(10, runtime.epoch))
+}
+
 func TestConsensusRuntime_OnBlockInserted_EndOfEpoch(t *testing.T) {
 	t.Parallel()
 
@@ -210,7 +240,8 @@ func TestConsensusRuntime_OnBlockInserted_EndOfEpoch(t *testing.T) {
 	snapshot := NewProposerSnapshot(epochSize-1, validatorSet)
 	config := &runtimeConfig{
 		PolyBFTConfig: &PolyBFTConfig{
-			EpochSize: epochSize,
+			EpochSize:  epochSize,
+			SprintSize: epochSize,
 		},
 		blockchain:     blockchainMock,
 		polybftBackend: polybftBackendMock,
@@ -240,6 +271,109 @@ func TestConsensusRuntime_OnBlockInserted_EndOfEpoch(t *testing.T) {
 	systemStateMock.AssertExpectations(t)
 }
 
+func TestConsensusRuntime_OnBlockInserted_EndOfEpoch_EmptyValidatorSet(t *testing.T) {
+	t.Parallel()
+
+	const epochSize = uint64(10)
+
+	currentEpochNumber := getEpochNumber(t, epochSize, epochSize)
+	validatorSet := validator.NewTestValidators(t, 4).GetPublicIdentities()
+	header, headerMap := createTestBlocks(t, epochSize, epochSize, validatorSet)
+	builtBlock := consensus.BuildBlock(consensus.BuildBlockParams{
+		Header: header,
+	})
+
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("GetEpoch").Return(currentEpochNumber + 1).Twice()
+
+	blockchainMock := new(blockchainMock)
+	blockchainMock.On("GetStateProviderForBlock", mock.Anything).Return(new(stateProviderMock)).Twice()
+	blockchainMock.On("GetSystemState", mock.Anything, mock.Anything).Return(systemStateMock)
+	blockchainMock.On("GetHeaderByNumber", mock.Anything).Return(headerMap.getHeader)
+
+	polybftBackendMock := new(polybftBackendMock)
+	polybftBackendMock.On("GetValidators", mock.Anything, mock.Anything).Return(validator.AccountSet{})
+
+	txPool := new(txPoolMock)
+	txPool.On("ResetWithHeaders", mock.Anything).Once()
+
+	var logs bytes.Buffer
+
+	snapshot := NewProposerSnapshot(epochSize-1, validatorSet)
+	config := &runtimeConfig{
+		PolyBFTConfig: &PolyBFTConfig{
+			EpochSize:  epochSize,
+			SprintSize: epochSize,
+		},
+		blockchain:     blockchainMock,
+		polybftBackend: polybftBackendMock,
+		txPool:         txPool,
+		State:          newTestState(t),
+	}
+	previousEpoch := &epochMetadata{
+		Number:            currentEpochNumber,
+		Validators:        validatorSet,
+		FirstBlockInEpoch: header.Number - epochSize + 1,
+	}
+	runtime := &consensusRuntime{
+		proposerCalculator: NewProposerCalculatorFromSnapshot(snapshot, config, hclog.NewNullLogger()),
+		logger:             hclog.New(&hclog.LoggerOptions{Output: &logs}),
+		state:              config.State,
+		config:             config,
+		epoch:              previousEpoch,
+		lastBuiltBlock:     &types.Header{Number: header.Number - 1},
+		stateSyncManager:   &dummyStateSyncManager{},
+		checkpointManager:  &dummyCheckpointManager{},
+		stakeManager:       &dummyStakeManager{},
+	}
+	runtime.OnBlockInserted(&types.FullBlock{Block: builtBlock})
+
+	require.Same(t, previousEpoch, runtime.epoch)
+	require.False(t, runtime.state.EpochStore.isEpochInserted(currentEpochNumber+1))
+	require.Contains(t, logs.String(), "failed to restart epoch after block inserted")
+	require.Contains(t, logs.String(), errInvalidValidatorSetSize.Error())
+
+	_, err := runtime.restartEpoch(header)
+	require.ErrorIs(t, err, errInvalidValidatorSetSize)
+
+	blockchainMock.AssertExpectations(t)
+	systemStateMock.AssertExpectations(t)
+}
+
+func TestConsensusRuntime_validateValidatorSetSize(t *testing.T) {
+	t.Parallel()
+
+	validators := validator.NewTestValidators(t, 5).GetPublicIdentities()
+
+	cases := []struct {
+		name       string
+		validators validator.AccountSet
+		min, max   uint64
+		valid      bool
+	}{
+		{"empty", validator.AccountSet{}, 0, 0, false},
+		{"no bounds", validators, 0, 0, true},
+		{"within bounds", validators, 4, 5, true},
+		{"below minimum", validators, 6, 0, false},
+		{"above maximum", validators, 0, 4, false},
+	}
+
+	for _, c := range cases {
+		runtime := &consensusRuntime{
+			config: &runtimeConfig{
+				PolyBFTConfig: &PolyBFTConfig{MinValidatorSetSize: c.min, MaxValidatorSetSize: c.max},
+			},
+		}
+
+		err := runtime.validateValidatorSetSize(c.validators)
+		if c.valid {
+			require.NoError(t, err, c.name)
+		} else {
+			require.ErrorIs(t, err, errInvalidValidatorSetSize, c.name)
+		}
+	}
+}
+
 func TestConsensusRuntime_OnBlockInserted_MiddleOfEpoch(t *testing.T) {
 	t.Parallel()
 
@@ -266,7 +400,7 @@ func TestConsensusRuntime_OnBlockInserted_MiddleOfEpoch(t *testing.T) {
 
 	snapshot := NewProposerSnapshot(blockNumber, []*validator.ValidatorMetadata{})
 	config := &runtimeConfig{
-		PolyBFTConfig: &PolyBFTConfig{EpochSize: epochSize},
+		PolyBFTConfig: &PolyBFTConfig{EpochSize: epochSize, SprintSize: epochSize},
 		blockchain:    blockchainMock,
 		txPool:        txPool,
 	}
@@ -274,7 +408,7 @@ func TestConsensusRuntime_OnBlockInserted_MiddleOfEpoch(t *testing.T) {
 	runtime := &consensusRuntime{
 		lastBuiltBlock: header,
 		config: &runtimeConfig{
-			PolyBFTConfig: &PolyBFTConfig{EpochSize: epochSize},
+			PolyBFTConfig: &PolyBFTConfig{EpochSize: epochSize, SprintSize: epochSize},
 			blockchain:    blockchainMock,
 			txPool:        txPool,
 		},
@@ -290,6 +424,221 @@ func TestConsensusRuntime_OnBlockInserted_MiddleOfEpoch(t *testing.T) {
 	require.Equal(t, header.Number, runtime.lastBuiltBlock.Number)
 }
 
+func TestConsensusRuntime_OnReorg(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+
+	stateSyncManager := newTestStateSyncManager(t, vals.GetValidator("0"))
+	stateSyncManager.validatorSet = vals.ToValidatorSet()
+
+	txPool := new(txPoolMock)
+	txPool.On("ResetWithHeaders", mock.Anything)
+
+	config := &runtimeConfig{
+		PolyBFTConfig: &PolyBFTConfig{EpochSize: 10, SprintSize: 5},
+		txPool:        txPool,
+		State:         stateSyncManager.state,
+	}
+
+	lastBuiltBlock := &types.Header{Number: 1}
+	lastBuiltBlock.ComputeHash()
+
+	runtime := &consensusRuntime{
+		lastBuiltBlock:     lastBuiltBlock,
+		config:             config,
+		state:              config.State,
+		epoch:              &epochMetadata{Number: 1, FirstBlockInEpoch: 1},
+		logger:             hclog.NewNullLogger(),
+		proposerCalculator: NewProposerCalculatorFromSnapshot(NewProposerSnapshot(100, nil), config, hclog.NewNullLogger()),
+		stateSyncManager:   stateSyncManager,
+		checkpointManager:  &dummyCheckpointManager{},
+		stakeManager:       &dummyStakeManager{},
+	}
+
+	newHead := &types.Header{Number: 2, ParentHash: lastBuiltBlock.Hash, Hash: types.StringToHash("0x2b")}
+
+	for _, event := range generateStateSyncEvents(t, 5, 0) {
+		insertTestStateSyncEvents(t, stateSyncManager.state.StateSyncStore, event)
+	}
+
+	// block 2 gets replaced by the one of the new canonical chain
+	runtime.OnReorg(&blockchain.ReorgEvent{
+		CommonAncestor: lastBuiltBlock,
+		Orphaned:       []types.Hash{types.StringToHash("0x2a")},
+		NewCanonical:   []types.Hash{newHead.Hash},
+	})
+	require.True(t, stateSyncManager.reorgInProgress)
+
+	require.NoError(t, stateSyncManager.buildCommitment())
+	require.Empty(t, stateSyncManager.pendingCommitments)
+
+	// reorg settles once the head of the new canonical chain is processed, and deferred commitment is built
+	runtime.OnBlockInserted(&types.FullBlock{Block: &types.Block{Header: newHead}})
+	require.False(t, stateSyncManager.reorgInProgress)
+	require.Len(t, stateSyncManager.pendingCommitments, 1)
+	require.Equal(t, uint64(4), stateSyncManager.pendingCommitments[0].EndID.Uint64())
+}
+
+func TestConsensusRuntime_OnBlockInserted_EndOfSprint(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+
+	stateSyncManager := newTestStateSyncManager(t, vals.GetValidator("0"))
+	stateSyncManager.validatorSet = vals.ToValidatorSet()
+	stateSyncManager.config.alignCommitments = true
+
+	txPool := new(txPoolMock)
+	txPool.On("ResetWithHeaders", mock.Anything)
+
+	config := &runtimeConfig{
+		PolyBFTConfig: &PolyBFTConfig{EpochSize: 10, SprintSize: 5},
+		txPool:        txPool,
+		State:         stateSyncManager.state,
+	}
+
+	runtime := &consensusRuntime{
+		lastBuiltBlock:     &types.Header{Number: 3},
+		config:             config,
+		state:              config.State,
+		epoch:              &epochMetadata{Number: 1, FirstBlockInEpoch: 1},
+		logger:             hclog.NewNullLogger(),
+		proposerCalculator: NewProposerCalculatorFromSnapshot(NewProposerSnapshot(100, nil), config, hclog.NewNullLogger()),
+		stateSyncManager:   stateSyncManager,
+		checkpointManager:  &dummyCheckpointManager{},
+		stakeManager:       &dummyStakeManager{},
+	}
+
+	for _, event := range generateStateSyncEvents(t, 5, 0) {
+		insertTestStateSyncEvents(t, stateSyncManager.state.StateSyncStore, event)
+	}
+
+	insertBlock := func() {
+		t.Helper()
+
+		header := &types.Header{Number: runtime.lastBuiltBlock.Number + 1}
+		runtime.OnBlockInserted(&types.FullBlock{Block: &types.Block{Header: header}})
+	}
+
+	// commitment is not built in the middle of a sprint
+	insertBlock()
+	require.Empty(t, stateSyncManager.pendingCommitments)
+
+	// commitment is built once the last block of the sprint is inserted
+	insertBlock()
+	require.Len(t, stateSyncManager.pendingCommitments, 1)
+	require.Equal(t, uint64(4), stateSyncManager.pendingCommitments[0].EndID.Uint64())
+}
+
+func TestConsensusRuntime_OnBlockInserted_PostSprintWithoutLock(t *testing.T) {
+	t.Parallel()
+
+	txPool := new(txPoolMock)
+	txPool.On("ResetWithHeaders", mock.Anything)
+
+	config := &runtimeConfig{
+		PolyBFTConfig: &PolyBFTConfig{EpochSize: 10, SprintSize: 5},
+		txPool:        txPool,
+		State:         newTestState(t),
+	}
+
+	stateSyncManager := new(stateSyncManagerMock)
+
+	runtime := &consensusRuntime{
+		lastBuiltBlock:     &types.Header{Number: 4},
+		config:             config,
+		state:              config.State,
+		epoch:              &epochMetadata{Number: 1, FirstBlockInEpoch: 1},
+		logger:             hclog.NewNullLogger(),
+		proposerCalculator: NewProposerCalculatorFromSnapshot(NewProposerSnapshot(100, nil), config, hclog.NewNullLogger()),
+		stateSyncManager:   stateSyncManager,
+		checkpointManager:  &dummyCheckpointManager{},
+		stakeManager:       &dummyStakeManager{},
+	}
+
+	// commitment is built at the end of sprint, once the runtime state is updated and its lock is released
+	stateSyncManager.On("PostSprint").Run(func(mock.Arguments) {
+		require.True(t, runtime.lock.TryLock())
+		require.Equal(t, uint64(5), runtime.lastBuiltBlock.Number)
+		runtime.lock.Unlock()
+	}).Return(nil).Once()
+
+	runtime.OnBlockInserted(&types.FullBlock{Block: &types.Block{Header: &types.Header{Number: 5}}})
+
+	stateSyncManager.AssertExpectations(t)
+}
+
+func TestConsensusRuntime_OnBlockInserted_ConcurrentFSM(t *testing.T) {
+	t.Parallel()
+
+	const blocksCount = 20
+
+	validators := validator.NewTestValidators(t, 3)
+	extra := createTestExtra(validators.GetPublicIdentities(), validator.AccountSet{}, 2, 2, 2)
+
+	blockchainMock := new(blockchainMock)
+	blockchainMock.On("NewBlockBuilder", mock.Anything).Return(&BlockBuilder{}, nil)
+	blockchainMock.On("CurrentHeader").Return(&types.Header{Number: 1})
+	// proposer snapshot is not updated, which does not prevent new fsm from being built
+	blockchainMock.On("GetHeaderByNumber", mock.Anything).Return(func(uint64) *types.Header { return nil })
+
+	txPool := new(txPoolMock)
+	txPool.On("ResetWithHeaders", mock.Anything)
+
+	config := &runtimeConfig{
+		PolyBFTConfig: &PolyBFTConfig{EpochSize: 100, SprintSize: 5},
+		Key:           wallet.NewKey(validators.GetPrivateIdentities()[0]),
+		blockchain:    blockchainMock,
+		txPool:        txPool,
+	}
+
+	runtime := &consensusRuntime{
+		proposerCalculator: NewProposerCalculatorFromSnapshot(NewProposerSnapshot(1, nil), config,
+			hclog.NewNullLogger()),
+		logger: hclog.NewNullLogger(),
+		config: config,
+		epoch: &epochMetadata{
+			Number:            1,
+			Validators:        validators.GetPublicIdentities(),
+			FirstBlockInEpoch: 1,
+		},
+		lastBuiltBlock:    &types.Header{Number: 1, ExtraData: extra},
+		state:             newTestState(t),
+		stateSyncManager:  &dummyStateSyncManager{},
+		checkpointManager: &dummyCheckpointManager{},
+		stakeManager:      &dummyStakeManager{},
+	}
+	runtime.setIsActiveValidator(true)
+
+	var wg sync.WaitGroup
+
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		for i := uint64(2); i < blocksCount+2; i++ {
+			runtime.OnBlockInserted(&types.FullBlock{
+				Block: &types.Block{Header: &types.Header{Number: i, ExtraData: extra}},
+			})
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < blocksCount; i++ {
+			assert.NoError(t, runtime.FSM())
+			assert.NotNil(t, runtime.getLastBuiltBlock())
+		}
+	}()
+
+	wg.Wait()
+
+	require.Equal(t, uint64(blocksCount+1), runtime.getLastBuiltBlock().Number)
+}
+
 func TestConsensusRuntime_FSM_NotInValidatorSet(t *testing.T) {
 	t.Parallel()
 
@@ -317,34 +666,815 @@ func TestConsensusRuntime_FSM_NotInValidatorSet(t *testing.T) {
 	assert.ErrorIs(t, err, errNotAValidator)
 }
 
-func TestConsensusRuntime_FSM_NotEndOfEpoch_NotEndOfSprint(t *testing.T) {
+func TestConsensusRuntime_FSM_ObserverMode(t *testing.T) {
 	t.Parallel()
 
-	extra := &Extra{
-		Checkpoint: &CheckpointData{},
+	validators := validator.NewTestValidatorsWithAliases(t, []string{"A", "B", "C", "D"})
+
+	blockchainMock := new(blockchainMock)
+
+	config := &runtimeConfig{
+		PolyBFTConfig: &PolyBFTConfig{
+			EpochSize: 1,
+		},
+		// node is in the validator set, but it runs as an observer
+		Key:        validators.GetValidator("A").Key(),
+		blockchain: blockchainMock,
+		mode:       ObserverRuntimeMode,
 	}
-	lastBlock := &types.Header{
-		Number:    1,
-		ExtraData: extra.MarshalRLPTo(nil),
+	runtime := &consensusRuntime{
+		config: config,
+		epoch: &epochMetadata{
+			Number:     1,
+			Validators: validators.GetPublicIdentities(),
+		},
+		lastBuiltBlock: &types.Header{},
 	}
 
-	validators := validator.NewTestValidators(t, 3)
-	blockchainMock := new(blockchainMock)
-	blockchainMock.On("NewBlockBuilder", mock.Anything).Return(&BlockBuilder{}, nil).Once()
+	err := runtime.FSM()
+	require.ErrorIs(t, err, errObserverMode)
+	require.NotErrorIs(t, err, errNotAValidator)
+	require.Nil(t, runtime.fsm)
+
+	blockchainMock.AssertNotCalled(t, "NewBlockBuilder", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything)
+}
+
+func TestConsensusRuntime_checkValidatorKey(t *testing.T) {
+	t.Parallel()
+
+	validators := validator.NewTestValidatorsWithAliases(t, []string{"A", "B", "C", "D"})
+	outsider := validator.NewTestValidatorsWithAliases(t, []string{"X"}).GetValidator("X")
+
+	createRuntime := func(key *wallet.Key, mode RuntimeMode, requireValidatorKey bool) (*consensusRuntime,
+		*bytes.Buffer) {
+		var logs bytes.Buffer
+
+		config := &runtimeConfig{
+			PolyBFTConfig:       &PolyBFTConfig{EpochSize: 1},
+			Key:                 key,
+			mode:                mode,
+			requireValidatorKey: requireValidatorKey,
+		}
+
+		return &consensusRuntime{
+			proposerCalculator: NewProposerCalculatorFromSnapshot(NewProposerSnapshot(1, nil), config,
+				hclog.NewNullLogger()),
+			config: config,
+			epoch: &epochMetadata{
+				Number:     1,
+				Validators: validators.GetPublicIdentities(),
+			},
+			lastBuiltBlock: &types.Header{},
+			logger:         hclog.New(&hclog.LoggerOptions{Output: &logs}),
+		}, &logs
+	}
+
+	t.Run("key in validator set", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, logs := createRuntime(validators.GetValidator("B").Key(), ValidatorRuntimeMode, true)
+
+		isValidator, err := runtime.checkValidatorKey()
+		require.NoError(t, err)
+		require.True(t, isValidator)
+		require.Empty(t, logs.String())
+	})
+
+	t.Run("key not in validator set", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, logs := createRuntime(outsider.Key(), ValidatorRuntimeMode, false)
+
+		isValidator, err := runtime.checkValidatorKey()
+		require.NoError(t, err)
+		require.False(t, isValidator)
+		require.Contains(t, logs.String(), "[WARN]")
+		require.Contains(t, logs.String(), "node will run as an observer")
+		require.Contains(t, logs.String(), outsider.Address().String())
+
+		// node does not participate in consensus
+		require.False(t, runtime.isActiveValidator())
+		require.ErrorIs(t, runtime.FSM(), errNotAValidator)
+	})
+
+	t.Run("key not in validator set in observer mode", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, logs := createRuntime(outsider.Key(), ObserverRuntimeMode, true)
+
+		isValidator, err := runtime.checkValidatorKey()
+		require.NoError(t, err)
+		require.False(t, isValidator)
+		require.Empty(t, logs.String())
+	})
+
+	t.Run("key not in validator set is required", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, _ := createRuntime(outsider.Key(), ValidatorRuntimeMode, true)
+
+		_, err := runtime.checkValidatorKey()
+		require.ErrorIs(t, err, errValidatorKeyMismatch)
+	})
+}
+
+func TestConsensusRuntime_parseRuntimeMode(t *testing.T) {
+	t.Parallel()
+
+	mode, err := parseRuntimeMode("")
+	require.NoError(t, err)
+	require.Equal(t, ValidatorRuntimeMode, mode)
+
+	mode, err = parseRuntimeMode("observer")
+	require.NoError(t, err)
+	require.Equal(t, ObserverRuntimeMode, mode)
+
+	_, err = parseRuntimeMode("archive")
+	require.ErrorContains(t, err, "unsupported runtime mode")
+}
+
+func TestConsensusRuntime_FSM_NotEndOfEpoch_NotEndOfSprint(t *testing.T) {
+	t.Parallel()
+
+	extra := &Extra{
+		Checkpoint: &CheckpointData{},
+	}
+	lastBlock := &types.Header{
+		Number:    1,
+		ExtraData: extra.MarshalRLPTo(nil),
+	}
+
+	validators := validator.NewTestValidators(t, 3)
+	blockchainMock := new(blockchainMock)
+	blockchainMock.On("NewBlockBuilder", mock.Anything).Return(&BlockBuilder{}, nil).Once()
+	blockchainMock.On("CurrentHeader").Return(lastBlock).Once()
+
+	snapshot := NewProposerSnapshot(1, nil)
+	config := &runtimeConfig{
+		PolyBFTConfig: &PolyBFTConfig{
+			EpochSize:  10,
+			SprintSize: 5,
+		},
+		Key:        wallet.NewKey(validators.GetPrivateIdentities()[0]),
+		blockchain: blockchainMock,
+	}
+	runtime := &consensusRuntime{
+		proposerCalculator: NewProposerCalculatorFromSnapshot(snapshot, config, hclog.NewNullLogger()),
+		logger:             hclog.NewNullLogger(),
+		config:             config,
+		epoch: &epochMetadata{
+			Number:            1,
+			Validators:        validators.GetPublicIdentities(),
+			FirstBlockInEpoch: 1,
+		},
+		lastBuiltBlock:    lastBlock,
+		state:             newTestState(t),
+		stateSyncManager:  &dummyStateSyncManager{},
+		checkpointManager: &dummyCheckpointManager{},
+	}
+	runtime.setIsActiveValidator(true)
+
+	err := runtime.FSM()
+	require.NoError(t, err)
+
+	assert.True(t, runtime.isActiveValidator())
+	assert.False(t, runtime.fsm.isEndOfEpoch)
+	assert.False(t, runtime.fsm.isEndOfSprint)
+	assert.Equal(t, lastBlock.Number, runtime.fsm.parent.Number)
+
+	address := types.Address(runtime.config.Key.Address())
+	assert.True(t, runtime.fsm.ValidatorSet().Includes(address))
+
+	assert.NotNil(t, runtime.fsm.blockBuilder)
+	assert.NotNil(t, runtime.fsm.backend)
+
+	blockchainMock.AssertExpectations(t)
+}
+
+func TestConsensusRuntime_ImportValidatorSnapshot(t *testing.T) {
+	t.Parallel()
+
+	extra := &Extra{
+		Checkpoint: &CheckpointData{},
+	}
+	lastBlock := &types.Header{
+		Number:    1,
+		ExtraData: extra.MarshalRLPTo(nil),
+	}
+
+	validators := validator.NewTestValidatorsWithAliases(t, []string{"A", "B", "C", "D"})
+	staleSet := validators.GetPublicIdentities("B", "C", "D")
+	importedSet := validators.GetPublicIdentities("A", "B", "C")
+
+	blockchainMock := new(blockchainMock)
+	blockchainMock.On("NewBlockBuilder", mock.Anything).Return(&BlockBuilder{}, nil).Once()
+	blockchainMock.On("CurrentHeader").Return(lastBlock).Once()
+
+	// validators must not be recomputed, so backend mock does not expect any calls
+	polybftBackendMock := new(polybftBackendMock)
+
+	snapshot := NewProposerSnapshot(1, nil)
+	config := &runtimeConfig{
+		PolyBFTConfig: &PolyBFTConfig{
+			EpochSize:  10,
+			SprintSize: 5,
+		},
+		Key:            validators.GetValidator("A").Key(),
+		blockchain:     blockchainMock,
+		polybftBackend: polybftBackendMock,
+	}
+	runtime := &consensusRuntime{
+		proposerCalculator: NewProposerCalculatorFromSnapshot(snapshot, config, hclog.NewNullLogger()),
+		logger:             hclog.NewNullLogger(),
+		config:             config,
+		epoch: &epochMetadata{
+			Number:            1,
+			Validators:        staleSet,
+			FirstBlockInEpoch: 1,
+		},
+		lastBuiltBlock:    lastBlock,
+		state:             newTestState(t),
+		stateSyncManager:  &dummyStateSyncManager{},
+		checkpointManager: &dummyCheckpointManager{},
+	}
+
+	require.ErrorIs(t, runtime.FSM(), errNotAValidator)
+
+	checksum, err := importedSet.Hash()
+	require.NoError(t, err)
+
+	// corrupt snapshot is rejected
+	corruptSet := importedSet.Copy()
+	corruptSet[0].VotingPower = big.NewInt(1000)
+
+	require.ErrorIs(t, runtime.ImportValidatorSnapshot(5, corruptSet, checksum), errValidatorSnapshotChecksum)
+	require.Equal(t, staleSet, runtime.epoch.Validators)
+
+	// snapshot can not be imported for the genesis epoch
+	require.ErrorIs(t, runtime.ImportValidatorSnapshot(0, importedSet, checksum), errInvalidEpochNumber)
+
+	require.NoError(t, runtime.ImportValidatorSnapshot(5, importedSet, checksum))
+
+	storedSnapshot, err := runtime.state.EpochStore.getValidatorSnapshot(0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), storedSnapshot.EpochEndingBlock)
+	require.True(t, importedSet.Equals(storedSnapshot.Snapshot))
+
+	epochValidators, err := runtime.GetValidatorsForEpochNumber(1)
+	require.NoError(t, err)
+	require.True(t, importedSet.Equals(epochValidators))
+
+	require.NoError(t, runtime.FSM())
+	require.True(t, runtime.fsm.ValidatorSet().Includes(validators.GetValidator("A").Address()))
+	require.False(t, runtime.fsm.ValidatorSet().Includes(validators.GetValidator("D").Address()))
+
+	blockchainMock.AssertExpectations(t)
+	polybftBackendMock.AssertExpectations(t)
+}
+
+func TestConsensusRuntime_Close(t *testing.T) {
+	t.Parallel()
+
+	validators := validator.NewTestValidators(t, 3)
+	stateSyncManager := newTestStateSyncManager(t, validators.GetValidator("0"))
+
+	runtime := &consensusRuntime{
+		logger:           hclog.NewNullLogger(),
+		config:           &runtimeConfig{PolyBFTConfig: &PolyBFTConfig{EpochSize: 10}},
+		state:            newTestState(t),
+		stateSyncManager: stateSyncManager,
+		closeCh:          make(chan struct{}),
+		fsm:              &fsm{},
+	}
+	runtime.epochValidatorsCache.set(1, validators.GetPublicIdentities())
+
+	require.False(t, runtime.isClosed())
+
+	runtime.Close()
+
+	require.True(t, runtime.isClosed())
+	require.Nil(t, runtime.fsm)
+
+	_, exists := runtime.epochValidatorsCache.get(1)
+	require.False(t, exists)
+
+	// state sync manager is closed, so the event tracker gets stopped
+	select {
+	case <-stateSyncManager.closeCh:
+	default:
+		t.Fatal("state sync manager is not closed")
+	}
+
+	require.NotPanics(t, runtime.Close)
+	require.ErrorIs(t, runtime.FSM(), errRuntimeClosed)
+
+	// runtime whose setup failed before the state sync manager got created
+	runtime = &consensusRuntime{
+		logger:  hclog.NewNullLogger(),
+		closeCh: make(chan struct{}),
+	}
+
+	require.NotPanics(t, runtime.Close)
+	require.True(t, runtime.isClosed())
+}
+
+func TestConsensusRuntime_Health(t *testing.T) {
+	t.Parallel()
+
+	lastBlock := &types.Header{Number: 15, Timestamp: 1700000000}
+
+	stateSyncManager := &stateSyncManagerMock{}
+	stateSyncManager.On("Status").Return(&StateSyncStatus{PendingCommitments: 2}).Once()
+
+	runtime := &consensusRuntime{
+		config: &runtimeConfig{
+			PolyBFTConfig: &PolyBFTConfig{Bridge: &BridgeConfig{}},
+		},
+		lastBuiltBlock:   lastBlock,
+		stateSyncManager: stateSyncManager,
+	}
+
+	health := runtime.Health()
+	require.Equal(t, &HealthStatus{
+		LastBlockNumber:    15,
+		LastBlockTime:      time.Unix(1700000000, 0),
+		BridgeEnabled:      true,
+		PendingCommitments: 2,
+		QuorumReachable:    false,
+	}, health)
+
+	runtime.SetSynced(true)
+	runtime.setIsActiveValidator(true)
+	runtime.config.PolyBFTConfig.Bridge = nil
+	runtime.stateSyncManager = &dummyStateSyncManager{}
+
+	health = runtime.Health()
+	require.True(t, health.Synced)
+	require.True(t, health.ActiveValidator)
+	require.False(t, health.BridgeEnabled)
+	require.Zero(t, health.PendingCommitments)
+	require.True(t, health.QuorumReachable)
+
+	stateSyncManager.AssertExpectations(t)
+
+	_, err := (&Polybft{}).Health()
+	require.ErrorIs(t, err, errRuntimeNotStarted)
+}
+
+func TestConsensusRuntime_FSM_StaleParent(t *testing.T) {
+	t.Parallel()
+
+	createRuntime := func(t *testing.T, headNumber, maxParentLag uint64) *consensusRuntime {
+		t.Helper()
+
+		extra := &Extra{Checkpoint: &CheckpointData{}}
+		validators := validator.NewTestValidators(t, 3)
+
+		blockchainMock := new(blockchainMock)
+		blockchainMock.On("NewBlockBuilder", mock.Anything).Return(&BlockBuilder{}, nil).Maybe()
+		blockchainMock.On("CurrentHeader").Return(&types.Header{Number: headNumber}).Once()
+
+		config := &runtimeConfig{
+			PolyBFTConfig: &PolyBFTConfig{EpochSize: 10, SprintSize: 5},
+			Key:           wallet.NewKey(validators.GetPrivateIdentities()[0]),
+			blockchain:    blockchainMock,
+			maxParentLag:  maxParentLag,
+		}
+
+		return &consensusRuntime{
+			proposerCalculator: NewProposerCalculatorFromSnapshot(NewProposerSnapshot(1, nil), config,
+				hclog.NewNullLogger()),
+			logger: hclog.NewNullLogger(),
+			config: config,
+			epoch: &epochMetadata{
+				Number:            1,
+				Validators:        validators.GetPublicIdentities(),
+				FirstBlockInEpoch: 1,
+			},
+			lastBuiltBlock:    &types.Header{Number: 1, ExtraData: extra.MarshalRLPTo(nil)},
+			state:             newTestState(t),
+			stateSyncManager:  &dummyStateSyncManager{},
+			checkpointManager: &dummyCheckpointManager{},
+		}
+	}
+
+	// chain head is several blocks ahead of the last built block
+	runtime := createRuntime(t, 5, 0)
+	err := runtime.FSM()
+	require.ErrorIs(t, err, errStaleParent)
+	require.ErrorContains(t, err, "parent block 1 is 4 blocks behind the chain head 5")
+	require.Nil(t, runtime.fsm)
+
+	// last built block is at most one block behind the chain head
+	runtime = createRuntime(t, 2, 0)
+	require.NoError(t, runtime.FSM())
+
+	// configured lag
+	runtime = createRuntime(t, 5, 4)
+	require.NoError(t, runtime.FSM())
+
+	runtime = createRuntime(t, 6, 4)
+	require.ErrorIs(t, runtime.FSM(), errStaleParent)
+}
+
+func TestConsensusRuntime_FSM_SyncBeforeBlockProduction(t *testing.T) {
+	t.Parallel()
+
+	extra := &Extra{Checkpoint: &CheckpointData{}}
+	validators := validator.NewTestValidators(t, 3)
+	lastBlock := &types.Header{Number: 1, ExtraData: extra.MarshalRLPTo(nil)}
+
+	blockchainMock := new(blockchainMock)
+	blockchainMock.On("NewBlockBuilder", mock.Anything).Return(&BlockBuilder{}, nil).Once()
+	blockchainMock.On("CurrentHeader").Return(lastBlock).Once()
+
+	config := &runtimeConfig{
+		PolyBFTConfig:             &PolyBFTConfig{EpochSize: 10, SprintSize: 5},
+		Key:                       wallet.NewKey(validators.GetPrivateIdentities()[0]),
+		blockchain:                blockchainMock,
+		syncBeforeBlockProduction: true,
+	}
+
+	runtime := &consensusRuntime{
+		proposerCalculator: NewProposerCalculatorFromSnapshot(NewProposerSnapshot(1, nil), config,
+			hclog.NewNullLogger()),
+		logger: hclog.NewNullLogger(),
+		config: config,
+		epoch: &epochMetadata{
+			Number:            1,
+			Validators:        validators.GetPublicIdentities(),
+			FirstBlockInEpoch: 1,
+		},
+		lastBuiltBlock:    lastBlock,
+		state:             newTestState(t),
+		stateSyncManager:  &dummyStateSyncManager{},
+		checkpointManager: &dummyCheckpointManager{},
+	}
+
+	// block production is gated until the node is synced
+	require.ErrorIs(t, runtime.FSM(), errNotSynced)
+	require.ErrorIs(t, runtime.FSM(), errNotSynced)
+	require.Nil(t, runtime.fsm)
+	blockchainMock.AssertNotCalled(t, "NewBlockBuilder", mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything, mock.Anything)
+
+	runtime.SetSynced(true)
+
+	require.NoError(t, runtime.FSM())
+	require.NotNil(t, runtime.fsm)
+	blockchainMock.AssertExpectations(t)
+}
+
+func TestConsensusRuntime_FSM_ProposalTimeout(t *testing.T) {
+	t.Parallel()
+
+	createRuntime := func(t *testing.T, blockTimeDrift uint64) *consensusRuntime {
+		t.Helper()
+
+		extra := &Extra{Checkpoint: &CheckpointData{}}
+		validators := validator.NewTestValidators(t, 3)
+		lastBlock := &types.Header{Number: 1, ExtraData: extra.MarshalRLPTo(nil)}
+
+		blockchainMock := new(blockchainMock)
+		blockchainMock.On("NewBlockBuilder", mock.Anything).Return(&BlockBuilder{}, nil).Once()
+		blockchainMock.On("CurrentHeader").Return(lastBlock).Once()
+
+		config := &runtimeConfig{
+			PolyBFTConfig: &PolyBFTConfig{
+				EpochSize:      10,
+				SprintSize:     5,
+				BlockTime:      common.Duration{Duration: 2 * time.Second},
+				BlockTimeDrift: blockTimeDrift,
+			},
+			Key:        wallet.NewKey(validators.GetPrivateIdentities()[0]),
+			blockchain: blockchainMock,
+		}
+
+		return &consensusRuntime{
+			proposerCalculator: NewProposerCalculatorFromSnapshot(NewProposerSnapshot(1, nil), config,
+				hclog.NewNullLogger()),
+			logger: hclog.NewNullLogger(),
+			config: config,
+			epoch: &epochMetadata{
+				Number:            1,
+				Validators:        validators.GetPublicIdentities(),
+				FirstBlockInEpoch: 1,
+			},
+			lastBuiltBlock:    lastBlock,
+			state:             newTestState(t),
+			stateSyncManager:  &dummyStateSyncManager{},
+			checkpointManager: &dummyCheckpointManager{},
+		}
+	}
+
+	runtime := createRuntime(t, 1)
+	require.Zero(t, runtime.getProposalTimeout())
+	require.NoError(t, runtime.FSM())
+	require.Equal(t, 3*time.Second, runtime.fsm.proposalTimeout)
+	require.Equal(t, 3*time.Second, runtime.getProposalTimeout())
+
+	runtime = createRuntime(t, 5)
+	require.NoError(t, runtime.FSM())
+	require.Equal(t, 7*time.Second, runtime.getProposalTimeout())
+}
+
+func TestConsensusRuntime_getSystemState_Retries(t *testing.T) {
+	t.Parallel()
+
+	errTransient := fmt.Errorf("%w (timeout 1s)", common.ErrCallTimeout)
+	errDeterministic := errors.New("state not found")
+
+	createRuntime := func(retries uint64, readErr error) (*consensusRuntime, *systemStateMock) {
+		systemStateMock := new(systemStateMock)
+		systemStateMock.On("GetEpoch").Return(uint64(0), readErr).Once()
+		systemStateMock.On("GetEpoch").Return(uint64(5), nil).Once()
+
+		blockchainMock := new(blockchainMock)
+		blockchainMock.On("GetStateProviderForBlock", mock.Anything).Return(new(stateProviderMock), nil).Once()
+		blockchainMock.On("GetSystemState", mock.Anything).Return(systemStateMock)
+
+		return &consensusRuntime{
+			config: &runtimeConfig{
+				blockchain:         blockchainMock,
+				systemStateRetries: retries,
+			},
+		}, systemStateMock
+	}
+
+	// epoch read which fails once with a transient error is retried
+	runtime, systemStateMock := createRuntime(1, errTransient)
+
+	systemState, err := runtime.getSystemState(&types.Header{Number: 1})
+	require.NoError(t, err)
+
+	epoch, err := systemState.GetEpoch()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), epoch)
+	systemStateMock.AssertNumberOfCalls(t, "GetEpoch", 2)
+
+	// transient error aborts the operation if there are no retries
+	runtime, _ = createRuntime(0, errTransient)
+
+	systemState, err = runtime.getSystemState(&types.Header{Number: 1})
+	require.NoError(t, err)
+
+	_, err = systemState.GetEpoch()
+	require.ErrorIs(t, err, common.ErrCallTimeout)
+
+	// deterministic error is not retried
+	runtime, systemStateMock = createRuntime(3, errDeterministic)
+
+	systemState, err = runtime.getSystemState(&types.Header{Number: 1})
+	require.NoError(t, err)
+
+	_, err = systemState.GetEpoch()
+	require.ErrorIs(t, err, errDeterministic)
+	systemStateMock.AssertNumberOfCalls(t, "GetEpoch", 1)
+
+	// state provider construction fails deterministically, so it is not retried
+	blockchainMock := new(blockchainMock)
+	blockchainMock.On("GetStateProviderForBlock", mock.Anything).Return(nil, errDeterministic).Once()
+
+	runtime = &consensusRuntime{config: &runtimeConfig{blockchain: blockchainMock, systemStateRetries: 3}}
+
+	_, err = runtime.getSystemState(&types.Header{Number: 1})
+	require.ErrorIs(t, err, errDeterministic)
+	blockchainMock.AssertNumberOfCalls(t, "GetStateProviderForBlock", 1)
+}
+
+func TestConsensusRuntime_getSystemState_BridgeTimeout(t *testing.T) {
+	t.Parallel()
+
+	// the first epoch read hangs, the second one answers immediately
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("GetEpoch").Return(uint64(0), nil).After(time.Second).Once()
+	systemStateMock.On("GetEpoch").Return(uint64(5), nil).Once()
+
+	blockchainMock := new(blockchainMock)
+	blockchainMock.On("GetStateProviderForBlock", mock.Anything).Return(new(stateProviderMock), nil)
+	blockchainMock.On("GetSystemState", mock.Anything).Return(systemStateMock)
+
+	runtime := &consensusRuntime{
+		config: &runtimeConfig{
+			PolyBFTConfig: &PolyBFTConfig{
+				Bridge: &BridgeConfig{
+					JSONRPCTimeout: common.Duration{Duration: 20 * time.Millisecond},
+					JSONRPCRetries: 1,
+				},
+			},
+			blockchain: blockchainMock,
+		},
+	}
+
+	systemState, err := runtime.getSystemState(&types.Header{Number: 1})
+	require.NoError(t, err)
+
+	start := time.Now()
+	epoch, err := systemState.GetEpoch()
+
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), epoch)
+	require.Less(t, time.Since(start), time.Second)
+
+	// timed out read returns a wrapped error instead of blocking, once the retries are exhausted
+	runtime.config.PolyBFTConfig.Bridge.JSONRPCRetries = 0
+
+	systemStateMock.On("GetEpoch").Return(uint64(0), nil).After(time.Second).Once()
+
+	systemState, err = runtime.getSystemState(&types.Header{Number: 1})
+	require.NoError(t, err)
+
+	_, err = systemState.GetEpoch()
+	require.ErrorIs(t, err, common.ErrCallTimeout)
+	require.ErrorContains(t, err, "get epoch failed after 1 attempt(s)")
+}
+
+func TestConsensusRuntime_FSM_ParentMinerNotValidator(t *testing.T) {
+	t.Parallel()
+
+	extra := &Extra{Checkpoint: &CheckpointData{}}
+	validators := validator.NewTestValidators(t, 3)
+	lastBlock := &types.Header{
+		Number:    2,
+		Miner:     types.StringToAddress("0xdead").Bytes(),
+		ExtraData: extra.MarshalRLPTo(nil),
+	}
+
+	blockchainMock := new(blockchainMock)
+	blockchainMock.On("NewBlockBuilder", mock.Anything).Return(&BlockBuilder{}, nil).Once()
+	blockchainMock.On("CurrentHeader").Return(lastBlock).Once()
+
+	config := &runtimeConfig{
+		PolyBFTConfig: &PolyBFTConfig{
+			EpochSize:  10,
+			SprintSize: 5,
+		},
+		Key:        wallet.NewKey(validators.GetPrivateIdentities()[0]),
+		blockchain: blockchainMock,
+	}
+	epoch := &epochMetadata{
+		Number:            1,
+		Validators:        validators.GetPublicIdentities(),
+		FirstBlockInEpoch: 1,
+	}
+	runtime := &consensusRuntime{
+		proposerCalculator: NewProposerCalculatorFromSnapshot(NewProposerSnapshot(2, nil), config,
+			hclog.NewNullLogger()),
+		logger:            hclog.NewNullLogger(),
+		config:            config,
+		epoch:             epoch,
+		lastBuiltBlock:    lastBlock,
+		state:             newTestState(t),
+		stateSyncManager:  &dummyStateSyncManager{},
+		checkpointManager: &dummyCheckpointManager{},
+	}
+
+	require.False(t, isParentMinerValidator(lastBlock, epoch))
+
+	// block is still built with the validator set of the epoch
+	require.NoError(t, runtime.FSM())
+	require.Equal(t, lastBlock.Number, runtime.fsm.parent.Number)
+	require.True(t, runtime.fsm.ValidatorSet().Includes(types.Address(config.Key.Address())))
+
+	// validator miner, genesis parent and the previous epoch parent are accepted
+	require.True(t, isParentMinerValidator(
+		&types.Header{Number: 2, Miner: validators.GetValidator("1").Address().Bytes()}, epoch))
+	require.True(t, isParentMinerValidator(&types.Header{Number: 0}, epoch))
+	require.True(t, isParentMinerValidator(&types.Header{Number: 10, Miner: lastBlock.Miner},
+		&epochMetadata{Number: 2, FirstBlockInEpoch: 11, Validators: epoch.Validators}))
+}
+
+func TestConsensusRuntime_FSM_EndOfSprint_CommitmentError(t *testing.T) {
+	t.Parallel()
+
+	errRootchain := errors.New("rootchain is not reachable")
+
+	createRuntime := func(t *testing.T, skipBridgeDataOnError bool) (*consensusRuntime, *blockBuilderMock) {
+		t.Helper()
+
+		validators := validator.NewTestValidators(t, 3)
+		extra := createTestExtra(validators.GetPublicIdentities(), validator.AccountSet{}, 2, 2, 2)
+		// next block is the end of sprint
+		lastBlock := &types.Header{Number: 4, ExtraData: extra}
+		lastBlock.ComputeHash()
+
+		blockBuilder := newBlockBuilderMock(createDummyStateBlock(lastBlock.Number+1, lastBlock.Hash, extra))
+
+		blockchainMock := new(blockchainMock)
+		blockchainMock.On("NewBlockBuilder", mock.Anything).Return(blockBuilder, nil).Once()
+		blockchainMock.On("CurrentHeader").Return(lastBlock)
+
+		stateSyncManager := new(stateSyncManagerMock)
+		stateSyncManager.On("Commitment").Return(nil, errRootchain).Once()
+
+		config := &runtimeConfig{
+			PolyBFTConfig: &PolyBFTConfig{
+				EpochSize:  10,
+				SprintSize: 5,
+				Bridge:     &BridgeConfig{SkipBridgeDataOnError: skipBridgeDataOnError},
+			},
+			Key:        wallet.NewKey(validators.GetPrivateIdentities()[0]),
+			blockchain: blockchainMock,
+		}
+
+		return &consensusRuntime{
+			proposerCalculator: NewProposerCalculatorFromSnapshot(NewProposerSnapshot(1, nil), config,
+				hclog.NewNullLogger()),
+			logger: hclog.NewNullLogger(),
+			config: config,
+			epoch: &epochMetadata{
+				Number:            1,
+				Validators:        validators.GetPublicIdentities(),
+				FirstBlockInEpoch: 1,
+			},
+			lastBuiltBlock:    lastBlock,
+			state:             newTestState(t),
+			stateSyncManager:  stateSyncManager,
+			checkpointManager: &dummyCheckpointManager{},
+		}, blockBuilder
+	}
+
+	t.Run("fallback disabled", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, _ := createRuntime(t, false)
+
+		require.ErrorIs(t, runtime.FSM(), errRootchain)
+		require.Nil(t, runtime.fsm)
+	})
+
+	t.Run("fallback enabled", func(t *testing.T) {
+		t.Parallel()
+
+		runtime, blockBuilder := createRuntime(t, true)
+
+		require.NoError(t, runtime.FSM())
+		require.NotNil(t, runtime.fsm)
+		require.True(t, runtime.fsm.isEndOfSprint)
+		require.Nil(t, runtime.fsm.proposerCommitmentToRegister)
+
+		// block is still built, without bridge state transactions
+		proposal, err := runtime.fsm.BuildProposal(1)
+		require.NoError(t, err)
+		require.NotEmpty(t, proposal)
+
+		blockBuilder.AssertExpectations(t)
+		blockBuilder.AssertNotCalled(t, "WriteTx", mock.Anything)
+	})
+}
+
+func TestConsensusRuntime_FSM_EndOfSprint_StaleCommitment(t *testing.T) {
+	t.Parallel()
+
+	validators := validator.NewTestValidators(t, 4)
+	extra := createTestExtra(validators.GetPublicIdentities(), validator.AccountSet{}, 2, 2, 2)
+	// next block is the end of sprint
+	lastBlock := &types.Header{Number: 4, ExtraData: extra}
+	lastBlock.ComputeHash()
+
+	blockBuilder := newBlockBuilderMock(createDummyStateBlock(lastBlock.Number+1, lastBlock.Hash, extra))
+
+	blockchainMock := new(blockchainMock)
+	blockchainMock.On("NewBlockBuilder", mock.Anything).Return(blockBuilder, nil).Once()
+	blockchainMock.On("CurrentHeader").Return(lastBlock)
+
+	stateSyncManager := newTestStateSyncManager(t, validators.GetValidator("0"))
+	stateSyncManager.validatorSet = validators.ToValidatorSet()
+
+	commitment, err := NewPendingCommitment(0, generateStateSyncEvents(t, 5, 0), maxCommitmentSize)
+	require.NoError(t, err)
+
+	hash, err := commitment.Hash()
+	require.NoError(t, err)
+
+	// commitment reaches the quorum
+	for _, alias := range []string{"0", "1", "2", "3"} {
+		signedMsg, err := newMockMsg().WithHash(hash.Bytes()).sign(validators.GetValidator(alias),
+			bls.DomainStateReceiver)
+		require.NoError(t, err)
+		require.NoError(t, stateSyncManager.saveVote(signedMsg))
+	}
+
+	// state syncs from the commitment are already committed
+	stateSyncManager.pendingCommitments = []*PendingCommitment{commitment}
+	stateSyncManager.nextCommittedIndex = 2
 
-	snapshot := NewProposerSnapshot(1, nil)
 	config := &runtimeConfig{
 		PolyBFTConfig: &PolyBFTConfig{
 			EpochSize:  10,
 			SprintSize: 5,
+			Bridge:     &BridgeConfig{},
 		},
 		Key:        wallet.NewKey(validators.GetPrivateIdentities()[0]),
 		blockchain: blockchainMock,
 	}
+
 	runtime := &consensusRuntime{
-		proposerCalculator: NewProposerCalculatorFromSnapshot(snapshot, config, hclog.NewNullLogger()),
-		logger:             hclog.NewNullLogger(),
-		config:             config,
+		proposerCalculator: NewProposerCalculatorFromSnapshot(NewProposerSnapshot(1, nil), config,
+			hclog.NewNullLogger()),
+		logger: hclog.NewNullLogger(),
+		config: config,
 		epoch: &epochMetadata{
 			Number:            1,
 			Validators:        validators.GetPublicIdentities(),
@@ -352,26 +1482,71 @@ func TestConsensusRuntime_FSM_NotEndOfEpoch_NotEndOfSprint(t *testing.T) {
 		},
 		lastBuiltBlock:    lastBlock,
 		state:             newTestState(t),
-		stateSyncManager:  &dummyStateSyncManager{},
+		stateSyncManager:  stateSyncManager,
 		checkpointManager: &dummyCheckpointManager{},
 	}
-	runtime.setIsActiveValidator(true)
 
-	err := runtime.FSM()
+	// stale commitment does not halt the block production
+	require.NoError(t, runtime.FSM())
+	require.NotNil(t, runtime.fsm)
+	require.True(t, runtime.fsm.isEndOfSprint)
+	require.Nil(t, runtime.fsm.proposerCommitmentToRegister)
+
+	proposal, err := runtime.fsm.BuildProposal(1)
 	require.NoError(t, err)
+	require.NotEmpty(t, proposal)
 
-	assert.True(t, runtime.isActiveValidator())
-	assert.False(t, runtime.fsm.isEndOfEpoch)
-	assert.False(t, runtime.fsm.isEndOfSprint)
-	assert.Equal(t, lastBlock.Number, runtime.fsm.parent.Number)
+	blockBuilder.AssertNotCalled(t, "WriteTx", mock.Anything)
 
-	address := types.Address(runtime.config.Key.Address())
-	assert.True(t, runtime.fsm.ValidatorSet().Includes(address))
+	// stale commitment is discarded
+	require.Empty(t, stateSyncManager.pendingCommitments)
+}
 
-	assert.NotNil(t, runtime.fsm.blockBuilder)
-	assert.NotNil(t, runtime.fsm.backend)
+func TestConsensusRuntime_PendingRegisterCommitment(t *testing.T) {
+	t.Parallel()
 
-	blockchainMock.AssertExpectations(t)
+	t.Run("has commitment", func(t *testing.T) {
+		t.Parallel()
+
+		expected := &CommitmentMessageSigned{
+			Message: &contractsapi.StateSyncCommitment{StartID: big.NewInt(1), EndID: big.NewInt(5)},
+		}
+
+		stateSyncManager := new(stateSyncManagerMock)
+		stateSyncManager.On("Commitment").Return(expected, nil).Once()
+
+		runtime := &consensusRuntime{stateSyncManager: stateSyncManager}
+
+		commitment, err := runtime.PendingRegisterCommitment()
+		require.NoError(t, err)
+		require.Equal(t, expected, commitment)
+
+		stateSyncManager.AssertExpectations(t)
+	})
+
+	t.Run("no commitment", func(t *testing.T) {
+		t.Parallel()
+
+		stateSyncManager := new(stateSyncManagerMock)
+		stateSyncManager.On("Commitment").Return(nil, nil).Once()
+
+		runtime := &consensusRuntime{stateSyncManager: stateSyncManager}
+
+		commitment, err := runtime.PendingRegisterCommitment()
+		require.ErrorIs(t, err, ErrNoCommitmentToRegister)
+		require.Nil(t, commitment)
+
+		stateSyncManager.AssertExpectations(t)
+	})
+
+	t.Run("bridge disabled", func(t *testing.T) {
+		t.Parallel()
+
+		runtime := &consensusRuntime{stateSyncManager: &dummyStateSyncManager{}}
+
+		_, err := runtime.PendingRegisterCommitment()
+		require.ErrorIs(t, err, ErrNoCommitmentToRegister)
+	})
 }
 
 func TestConsensusRuntime_FSM_EndOfEpoch_BuildCommitEpoch(t *testing.T) {
@@ -393,10 +1568,11 @@ func TestConsensusRuntime_FSM_EndOfEpoch_BuildCommitEpoch(t *testing.T) {
 
 	blockchainMock := new(blockchainMock)
 	blockchainMock.On("NewBlockBuilder", mock.Anything).Return(&BlockBuilder{}, nil).Once()
-	blockchainMock.On("GetHeaderByNumber", mock.Anything).Return(headerMap.getHeader)
+	blockchainMock.On("CurrentHeader").Return(lastBuiltBlock).Once()
+	blockchainMock.On("HeaderByNumber", mock.Anything).Return(headerMap.getHeader)
 
 	state := newTestState(t)
-	require.NoError(t, state.EpochStore.insertEpoch(epoch))
+	require.NoError(t, state.EpochStore.insertEpoch(epoch, nil))
 
 	metadata := &epochMetadata{
 		Validators:        validators,
@@ -458,12 +1634,13 @@ func Test_NewConsensusRuntime(t *testing.T) {
 
 	systemStateMock := new(systemStateMock)
 	systemStateMock.On("GetEpoch").Return(uint64(1)).Once()
-	systemStateMock.On("GetNextCommittedIndex").Return(uint64(1)).Once()
+	// next committed index is read once by the startup commitment gap check and once by the epoch restart
+	systemStateMock.On("GetNextCommittedIndex").Return(uint64(1)).Twice()
 
 	blockchainMock := &blockchainMock{}
 	blockchainMock.On("CurrentHeader").Return(&types.Header{Number: 1, ExtraData: createTestExtraForAccounts(t, 1, validators, nil)})
-	blockchainMock.On("GetStateProviderForBlock", mock.Anything).Return(new(stateProviderMock)).Once()
-	blockchainMock.On("GetSystemState", mock.Anything, mock.Anything).Return(systemStateMock).Once()
+	blockchainMock.On("GetStateProviderForBlock", mock.Anything).Return(new(stateProviderMock)).Twice()
+	blockchainMock.On("GetSystemState", mock.Anything, mock.Anything).Return(systemStateMock).Twice()
 	blockchainMock.On("GetHeaderByNumber", uint64(0)).Return(&types.Header{Number: 0, ExtraData: createTestExtraForAccounts(t, 0, validators, nil)})
 
 	polybftBackendMock := new(polybftBackendMock)
@@ -560,7 +1737,7 @@ func TestConsensusRuntime_calculateCommitEpochInput_SecondEpoch(t *testing.T) {
 	lastBuiltBlock, headerMap := createTestBlocks(t, 19, epochSize, validators.GetPublicIdentities())
 
 	blockchainMock := new(blockchainMock)
-	blockchainMock.On("GetHeaderByNumber", mock.Anything).Return(headerMap.getHeader)
+	blockchainMock.On("HeaderByNumber", mock.Anything).Return(headerMap.getHeader)
 
 	polybftBackendMock := new(polybftBackendMock)
 	polybftBackendMock.On("GetValidators", mock.Anything, mock.Anything).Return(validators.GetPublicIdentities()).Twice()
@@ -595,6 +1772,156 @@ func TestConsensusRuntime_calculateCommitEpochInput_SecondEpoch(t *testing.T) {
 	polybftBackendMock.AssertExpectations(t)
 }
 
+func TestConsensusRuntime_calculateCommitEpochInput_ChainGap(t *testing.T) {
+	t.Parallel()
+
+	const (
+		epochSize       = 10
+		epochStartBlock = 11
+		missingBlock    = 15
+	)
+
+	validators := validator.NewTestValidatorsWithAliases(t, []string{"A", "B", "C", "D", "E"})
+	lastBuiltBlock, headerMap := createTestBlocks(t, 19, epochSize, validators.GetPublicIdentities())
+	errStorage := errors.New("storage failure")
+
+	cases := []struct {
+		name        string
+		err         error
+		expectedErr error
+	}{
+		{name: "missing block", err: blockchain.ErrBlockNotFound, expectedErr: blockchain.ErrBlockNotFound},
+		{name: "storage error", err: errStorage, expectedErr: errStorage},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			blockchainMock := new(blockchainMock)
+			blockchainMock.On("HeaderByNumber", uint64(missingBlock)).Return(nil, c.err).Once()
+			blockchainMock.On("HeaderByNumber", mock.Anything).Return(headerMap.getHeader)
+
+			consensusRuntime := &consensusRuntime{
+				config: &runtimeConfig{
+					PolyBFTConfig: &PolyBFTConfig{EpochSize: epochSize},
+					blockchain:    blockchainMock,
+				},
+				epoch: &epochMetadata{
+					Number:            2,
+					Validators:        validators.GetPublicIdentities(),
+					FirstBlockInEpoch: epochStartBlock,
+				},
+			}
+
+			_, _, err := consensusRuntime.calculateCommitEpochInput(lastBuiltBlock, consensusRuntime.epoch)
+			require.ErrorIs(t, err, c.expectedErr)
+			require.ErrorContains(t, err, fmt.Sprintf("block %d", missingBlock))
+		})
+	}
+}
+
+func TestConsensusRuntime_calculateCommitEpochInput_UnavailableLookback(t *testing.T) {
+	t.Parallel()
+
+	const (
+		epochSize       = 10
+		epochStartBlock = 11
+	)
+
+	validators := validator.NewTestValidatorsWithAliases(t, []string{"A", "B", "C", "D", "E"})
+	lastBuiltBlock, headerMap := createTestBlocks(t, 19, epochSize, validators.GetPublicIdentities())
+
+	createRuntime := func(skipLookback bool, polybftBackend polybftBackend,
+		blockchainBackend blockchainBackend) *consensusRuntime {
+		return &consensusRuntime{
+			logger: hclog.NewNullLogger(),
+			config: &runtimeConfig{
+				PolyBFTConfig: &PolyBFTConfig{
+					EpochSize:                     epochSize,
+					SkipUnavailableUptimeLookback: skipLookback,
+				},
+				blockchain:     blockchainBackend,
+				polybftBackend: polybftBackend,
+			},
+			epoch: &epochMetadata{
+				Number:            2,
+				Validators:        validators.GetPublicIdentities(),
+				FirstBlockInEpoch: epochStartBlock,
+			},
+		}
+	}
+
+	// requireSignedBlocks checks that uptime is calculated from the blocks in the given range
+	requireSignedBlocks := func(t *testing.T, uptime []*contractsapi.Uptime, from, to uint64) {
+		t.Helper()
+
+		expected := map[types.Address]int64{}
+
+		for i := from; i <= to; i++ {
+			extra, err := GetIbftExtra(headerMap.getHeader(i).ExtraData)
+			require.NoError(t, err)
+
+			signers, err := validators.GetPublicIdentities().GetFilteredValidators(extra.Parent.Bitmap)
+			require.NoError(t, err)
+
+			for _, addr := range signers.GetAddresses() {
+				expected[addr]++
+			}
+		}
+
+		actual := map[types.Address]int64{}
+		for _, u := range uptime {
+			actual[u.Validator] = u.SignedBlocks.Int64()
+		}
+
+		require.Equal(t, expected, actual)
+	}
+
+	t.Run("lookback validators not available", func(t *testing.T) {
+		t.Parallel()
+
+		blockchainMock := new(blockchainMock)
+		blockchainMock.On("HeaderByNumber", mock.Anything).Return(headerMap.getHeader)
+
+		polybftBackendMock := new(polybftBackendMock)
+		polybftBackendMock.On("GetValidators", mock.Anything, mock.Anything).Return(nil, blockchain.ErrNoBlock)
+
+		// uptime calculation fails if skipping unavailable lookback is not enabled
+		runtime := createRuntime(false, polybftBackendMock, blockchainMock)
+
+		_, _, err := runtime.calculateCommitEpochInput(lastBuiltBlock, runtime.epoch)
+		require.ErrorIs(t, err, blockchain.ErrNoBlock)
+
+		runtime = createRuntime(true, polybftBackendMock, blockchainMock)
+
+		_, distributeRewards, err := runtime.calculateCommitEpochInput(lastBuiltBlock, runtime.epoch)
+		require.NoError(t, err)
+		// the first block of the epoch needs validators of the previous epoch, so it is skipped as well
+		requireSignedBlocks(t, distributeRewards.Uptime, epochStartBlock+1, lastBuiltBlock.Number)
+	})
+
+	t.Run("lookback block not available", func(t *testing.T) {
+		t.Parallel()
+
+		blockchainMock := new(blockchainMock)
+		blockchainMock.On("HeaderByNumber", uint64(epochStartBlock-1)).Return(nil, blockchain.ErrBlockNotFound)
+		blockchainMock.On("HeaderByNumber", mock.Anything).Return(headerMap.getHeader)
+
+		polybftBackendMock := new(polybftBackendMock)
+		polybftBackendMock.On("GetValidators", mock.Anything, mock.Anything).
+			Return(validators.GetPublicIdentities(), nil)
+
+		runtime := createRuntime(true, polybftBackendMock, blockchainMock)
+
+		_, distributeRewards, err := runtime.calculateCommitEpochInput(lastBuiltBlock, runtime.epoch)
+		require.NoError(t, err)
+		// the first block of the epoch is still accounted for by the lookback
+		requireSignedBlocks(t, distributeRewards.Uptime, epochStartBlock, lastBuiltBlock.Number)
+	})
+}
+
 func TestConsensusRuntime_IsValidValidator_BasicCases(t *testing.T) {
 	t.Parallel()
 
@@ -1082,3 +2409,307 @@ func encodeExitEvents(t *testing.T, exitEvents []*ExitEvent) [][]byte {
 
 	return encodedEvents
 }
+
+func TestConsensusRuntime_IsStateSyncExecuted_Cache(t *testing.T) {
+	t.Parallel()
+
+	header := &types.Header{Number: 5, Hash: types.StringToHash("0x5")}
+
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("IsStateSyncExecuted", uint64(3)).Return(true, nil).Once()
+	systemStateMock.On("IsStateSyncExecuted", uint64(4)).Return(false, nil).Twice()
+
+	blockchainMock := new(blockchainMock)
+	blockchainMock.On("GetStateProviderForBlock", mock.Anything).Return(new(stateProviderMock))
+	blockchainMock.On("GetSystemState", mock.Anything).Return(systemStateMock)
+
+	runtime := &consensusRuntime{
+		config:         &runtimeConfig{blockchain: blockchainMock},
+		lastBuiltBlock: header,
+	}
+
+	for i := 0; i < 2; i++ {
+		isExecuted, err := runtime.IsStateSyncExecuted(3)
+		require.NoError(t, err)
+		require.True(t, isExecuted)
+
+		isExecuted, err = runtime.IsStateSyncExecuted(4)
+		require.NoError(t, err)
+		require.False(t, isExecuted)
+	}
+
+	// cached statuses are invalidated once a new block is built
+	runtime.lastBuiltBlock = &types.Header{Number: 6, Hash: types.StringToHash("0x6")}
+
+	isExecuted, err := runtime.IsStateSyncExecuted(4)
+	require.NoError(t, err)
+	require.False(t, isExecuted)
+
+	systemStateMock.AssertExpectations(t)
+}
+
+func TestConsensusRuntime_GetPendingCommitments(t *testing.T) {
+	t.Parallel()
+
+	state := newTestState(t)
+
+	for _, commitment := range []*CommitmentMessageSigned{
+		{Message: &contractsapi.StateSyncCommitment{StartID: big.NewInt(1), EndID: big.NewInt(3)}},
+		{Message: &contractsapi.StateSyncCommitment{StartID: big.NewInt(4), EndID: big.NewInt(6)}},
+		{Message: &contractsapi.StateSyncCommitment{StartID: big.NewInt(7), EndID: big.NewInt(8)}},
+		// commitment which is not registered on-chain yet
+		{Message: &contractsapi.StateSyncCommitment{StartID: big.NewInt(9), EndID: big.NewInt(10)}},
+	} {
+		require.NoError(t, state.StateSyncStore.insertCommitmentMessage(commitment))
+	}
+
+	// state syncs below the pruned proofs index are known to be executed, so they are not checked
+	_, err := state.StateSyncStore.pruneExecutedProofs(3)
+	require.NoError(t, err)
+
+	executed := map[uint64]bool{1: true, 2: true, 3: true, 4: true, 6: true}
+
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("GetNextCommittedIndex").Return(uint64(9), nil).Once()
+
+	for stateSyncID := uint64(3); stateSyncID <= 8; stateSyncID++ {
+		systemStateMock.On("IsStateSyncExecuted", stateSyncID).Return(executed[stateSyncID], nil).Once()
+	}
+
+	blockchainMock := new(blockchainMock)
+	blockchainMock.On("GetStateProviderForBlock", mock.Anything).Return(new(stateProviderMock))
+	blockchainMock.On("GetSystemState", mock.Anything).Return(systemStateMock)
+
+	runtime := &consensusRuntime{
+		config:         &runtimeConfig{blockchain: blockchainMock},
+		state:          state,
+		lastBuiltBlock: &types.Header{Number: 5, Hash: types.StringToHash("0x5")},
+	}
+
+	pending, err := runtime.GetPendingCommitments()
+	require.NoError(t, err)
+	require.Equal(t, []*PendingCommitmentInfo{
+		{StartID: 4, EndID: 6, PendingStateSyncIDs: []uint64{5}},
+		{StartID: 7, EndID: 8, PendingStateSyncIDs: []uint64{7, 8}},
+	}, pending)
+
+	systemStateMock.AssertExpectations(t)
+}
+
+func TestConsensusRuntime_EpochForBlock(t *testing.T) {
+	t.Parallel()
+
+	runtime := &consensusRuntime{config: &runtimeConfig{PolyBFTConfig: &PolyBFTConfig{EpochSize: 10}}}
+
+	cases := map[uint64]uint64{
+		0:  0, // genesis block is the only block of epoch 0
+		1:  1,
+		9:  1,
+		10: 1, // epoch ending block belongs to the epoch it ends
+		11: 2,
+		20: 2,
+		21: 3,
+	}
+
+	for blockNumber, epoch := range cases {
+		require.Equal(t, epoch, runtime.EpochForBlock(blockNumber), "block %d", blockNumber)
+		require.Equal(t, getEpochNumber(t, blockNumber, 10), runtime.EpochForBlock(blockNumber))
+	}
+}
+
+func TestConsensusRuntime_BlockRangeForEpoch(t *testing.T) {
+	t.Parallel()
+
+	runtime := &consensusRuntime{config: &runtimeConfig{PolyBFTConfig: &PolyBFTConfig{EpochSize: 10}}}
+
+	first, last := runtime.BlockRangeForEpoch(0)
+	require.Equal(t, uint64(0), first)
+	require.Equal(t, uint64(0), last)
+
+	first, last = runtime.BlockRangeForEpoch(1)
+	require.Equal(t, uint64(1), first)
+	require.Equal(t, uint64(10), last)
+
+	first, last = runtime.BlockRangeForEpoch(3)
+	require.Equal(t, uint64(21), first)
+	require.Equal(t, uint64(30), last)
+
+	// block range is the inverse of the epoch for block
+	for epoch := uint64(0); epoch < 5; epoch++ {
+		first, last := runtime.BlockRangeForEpoch(epoch)
+		require.Equal(t, epoch, runtime.EpochForBlock(first))
+		require.Equal(t, epoch, runtime.EpochForBlock(last))
+
+		if epoch > 0 {
+			require.Equal(t, epoch-1, runtime.EpochForBlock(first-1))
+		}
+
+		require.Equal(t, epoch+1, runtime.EpochForBlock(last+1))
+	}
+}
+
+func TestConsensusRuntime_CurrentValidators(t *testing.T) {
+	t.Parallel()
+
+	runtime := &consensusRuntime{}
+
+	_, err := runtime.CurrentValidators()
+	require.ErrorIs(t, err, errEpochNotInitialized)
+
+	validators := validator.NewTestValidators(t, 4).GetPublicIdentities()
+
+	validatorsHash, err := validators.Hash()
+	require.NoError(t, err)
+
+	runtime.epoch = &epochMetadata{Number: 2, Validators: validators}
+
+	currentValidators, err := runtime.CurrentValidators()
+	require.NoError(t, err)
+	require.Equal(t, validators.GetAddresses(), currentValidators.GetAddresses())
+
+	currentValidatorsHash, err := currentValidators.Hash()
+	require.NoError(t, err)
+	require.Equal(t, validatorsHash, currentValidatorsHash)
+
+	// modifying returned set does not affect the runtime
+	currentValidators[0].VotingPower.SetUint64(1000)
+	currentValidators[1].IsActive = false
+	currentValidators[2] = currentValidators[3]
+
+	hash, err := runtime.epoch.Validators.Hash()
+	require.NoError(t, err)
+	require.Equal(t, validatorsHash, hash)
+}
+
+func TestConsensusRuntime_NextEpochValidators(t *testing.T) {
+	t.Parallel()
+
+	const epochNumber = 3
+
+	vals := validator.NewTestValidators(t, 5)
+	currentValidators := vals.GetPublicIdentities("0", "1", "2", "3")
+
+	currentValidatorsHash, err := currentValidators.Hash()
+	require.NoError(t, err)
+
+	// epoch is not initialized yet
+	_, err = (&consensusRuntime{}).NextEpochValidators()
+	require.ErrorIs(t, err, errEpochNotInitialized)
+
+	updatedValidator := currentValidators[2].Copy()
+	updatedValidator.VotingPower = big.NewInt(200)
+
+	// validator "1" leaves, validator "4" joins, and voting power of validator "2" changes
+	removed := bitmap.Bitmap{}
+	removed.Set(1)
+
+	stakeManagerMock := new(stakeManagerMock)
+	stakeManagerMock.On("UpdateValidatorSet", uint64(epochNumber), currentValidators).
+		Return(&validator.ValidatorSetDelta{
+			Added:   vals.GetPublicIdentities("4"),
+			Updated: validator.AccountSet{updatedValidator},
+			Removed: removed,
+		}, nil).Once()
+	stakeManagerMock.On("UpdateValidatorSet", uint64(epochNumber), currentValidators).
+		Return(nil, errors.New("stake store error")).Once()
+
+	runtime := &consensusRuntime{
+		epoch:        &epochMetadata{Number: epochNumber, Validators: currentValidators},
+		stakeManager: stakeManagerMock,
+	}
+
+	nextValidators, err := runtime.NextEpochValidators()
+	require.NoError(t, err)
+	require.Equal(t, validator.AccountSet{
+		currentValidators[0], updatedValidator, currentValidators[3], vals.GetPublicIdentities("4")[0],
+	}, nextValidators)
+
+	// current validator set is not affected by the projection, nor by modifying it
+	nextValidators[0].VotingPower.SetUint64(1000)
+
+	hash, err := runtime.epoch.Validators.Hash()
+	require.NoError(t, err)
+	require.Equal(t, currentValidatorsHash, hash)
+
+	_, err = runtime.NextEpochValidators()
+	require.ErrorContains(t, err, "cannot calculate validator set of epoch 4")
+
+	stakeManagerMock.AssertExpectations(t)
+}
+
+func TestConsensusRuntime_GetValidatorsForEpochNumber(t *testing.T) {
+	t.Parallel()
+
+	const epochSize = 10
+
+	validators := validator.NewTestValidators(t, 5).GetPublicIdentities()
+
+	polybftBackendMock := new(polybftBackendMock)
+	// validators of the epoch 3 are resolved on the last block of the epoch 2
+	polybftBackendMock.On("GetValidators", uint64(20), mock.Anything).Return(validators, nil).Once()
+	polybftBackendMock.On("GetValidators", uint64(30), mock.Anything).Return(nil, blockchain.ErrNoBlock).Once()
+
+	runtime := &consensusRuntime{
+		config: &runtimeConfig{
+			PolyBFTConfig:  &PolyBFTConfig{EpochSize: epochSize},
+			polybftBackend: polybftBackendMock,
+		},
+	}
+
+	// second call is served from the cache
+	for i := 0; i < 2; i++ {
+		epochValidators, err := runtime.GetValidatorsForEpochNumber(3)
+		require.NoError(t, err)
+		require.Equal(t, validators, epochValidators)
+	}
+
+	_, err := runtime.GetValidatorsForEpochNumber(4)
+	require.ErrorIs(t, err, blockchain.ErrNoBlock)
+
+	_, err = runtime.GetValidatorsForEpochNumber(0)
+	require.ErrorIs(t, err, errInvalidEpochNumber)
+
+	polybftBackendMock.AssertExpectations(t)
+}
+
+func TestEpochValidatorsCache(t *testing.T) {
+	t.Parallel()
+
+	var cache epochValidatorsCache
+
+	validators := validator.NewTestValidators(t, 3).GetPublicIdentities()
+
+	_, exists := cache.get(1)
+	require.False(t, exists)
+
+	cache.set(1, validators)
+
+	// cached validator set is not affected by modifying neither the stored nor the returned set
+	validators[0].VotingPower = big.NewInt(1000)
+
+	cached, exists := cache.get(1)
+	require.True(t, exists)
+	require.NotEqual(t, validators[0].VotingPower, cached[0].VotingPower)
+
+	cached[1].VotingPower = big.NewInt(1000)
+
+	cached, exists = cache.get(1)
+	require.True(t, exists)
+	require.NotEqual(t, big.NewInt(1000), cached[1].VotingPower)
+
+	// the least recently used epochs are evicted
+	for epoch := uint64(2); epoch <= epochValidatorsCacheSize+1; epoch++ {
+		cache.set(epoch, validators)
+	}
+
+	_, exists = cache.get(1)
+	require.False(t, exists)
+
+	_, exists = cache.get(epochValidatorsCacheSize + 1)
+	require.True(t, exists)
+
+	cache.reset()
+
+	_, exists = cache.get(epochValidatorsCacheSize + 1)
+	require.False(t, exists)
+}
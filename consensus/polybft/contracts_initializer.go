@@ -1,6 +1,8 @@
 package polybft
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -8,6 +10,8 @@ import (
 	"github.com/0xPolygon/polygon-edge/contracts"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-hclog"
 	"github.com/umbracle/ethgo/abi"
 )
 
@@ -15,6 +19,15 @@ const (
 	contractCallGasLimit = 100_000_000
 )
 
+var (
+	errInsufficientRewardWallet = errors.New("reward wallet does not hold enough native tokens")
+	errGenesisStakeMismatch     = errors.New("genesis validator stake mismatch")
+
+	// nativeERC20MintableTotalSupplySlot is the storage slot of the total supply of NativeERC20Mintable contract.
+	// Reward tokens are not minted through the contract, since its mint transfers tokens from the zero address.
+	nativeERC20MintableTotalSupplySlot = types.BytesToHash(big.NewInt(54).Bytes())
+)
+
 // initValidatorSet initializes ValidatorSet SC
 func initValidatorSet(polyBFTConfig PolyBFTConfig, transition *state.Transition) error {
 	initialValidators := make([]*contractsapi.ValidatorInit, len(polyBFTConfig.InitialValidatorSet))
@@ -42,6 +55,42 @@ func initValidatorSet(polyBFTConfig PolyBFTConfig, transition *state.Transition)
 		contracts.ValidatorSetContract, input, "ValidatorSet.initialize", transition)
 }
 
+// verifyInitialValidatorsStake checks that ValidatorSet SC reports the stake of each genesis validator
+// as configured in the genesis, so that the stake-weighted quorum of the first epoch is correct
+func verifyInitialValidatorsStake(polyBFTConfig PolyBFTConfig, transition *state.Transition) error {
+	balanceOfFn := contractsapi.ValidatorSet.Abi.Methods["balanceOf"]
+
+	for _, validator := range polyBFTConfig.InitialValidatorSet {
+		input, err := balanceOfFn.Encode([]interface{}{validator.Address})
+		if err != nil {
+			return fmt.Errorf("ValidatorSet.balanceOf params encoding failed: %w", err)
+		}
+
+		result := transition.Call2(contracts.SystemCaller, contracts.ValidatorSetContract, input,
+			big.NewInt(0), contractCallGasLimit)
+		if result.Failed() {
+			return fmt.Errorf("ValidatorSet.balanceOf contract call failed: %w", result.Err)
+		}
+
+		rawResult, err := balanceOfFn.Decode(result.ReturnValue)
+		if err != nil {
+			return fmt.Errorf("ValidatorSet.balanceOf result decoding failed: %w", err)
+		}
+
+		stake, isOk := rawResult["0"].(*big.Int)
+		if !isOk {
+			return fmt.Errorf("failed to decode stake of validator %s", validator.Address)
+		}
+
+		if stake.Cmp(validator.Stake) != 0 {
+			return fmt.Errorf("%w: validator %s has stake %d in genesis, but %d in ValidatorSet contract",
+				errGenesisStakeMismatch, validator.Address, validator.Stake, stake)
+		}
+	}
+
+	return nil
+}
+
 // initRewardPool initializes RewardPool SC
 func initRewardPool(polybftConfig PolyBFTConfig, transition *state.Transition) error {
 	initFn := &contractsapi.InitializeRewardPoolFn{
@@ -217,12 +266,14 @@ func getInitERC1155PredicateACLInput(config *BridgeConfig, owner types.Address,
 	return params.EncodeAbi()
 }
 
-// mintRewardTokensToWallet mints configured amount of reward tokens to reward wallet address
-func mintRewardTokensToWallet(polyBFTConfig PolyBFTConfig, transition *state.Transition) error {
+// mintRewardTokensToWallet funds reward wallet address with the configured amount of reward tokens.
+// If native token is used as a reward token, wallet is funded through premine. If native reward minting
+// is active from genesis, wallet is either topped up by minting new native tokens (when native token
+// is mintable, capped by the configured mint cap), or it is checked to be pre-funded (when it is not).
+// It returns amount of native tokens minted to the reward wallet.
+func mintRewardTokensToWallet(polyBFTConfig PolyBFTConfig, transition *state.Transition) (*big.Int, error) {
 	if isNativeRewardToken(polyBFTConfig) {
-		// if reward token is a native erc20 token, we don't need to mint an amount of tokens
-		// for given wallet address to it since this is done in premine
-		return nil
+		return fundNativeRewardWallet(polyBFTConfig, transition)
 	}
 
 	mintFn := contractsapi.MintRootERC20Fn{
@@ -232,13 +283,122 @@ func mintRewardTokensToWallet(polyBFTConfig PolyBFTConfig, transition *state.Tra
 
 	input, err := mintFn.EncodeAbi()
 	if err != nil {
-		return fmt.Errorf("RewardToken.mint params encoding failed: %w", err)
+		return nil, fmt.Errorf("RewardToken.mint params encoding failed: %w", err)
 	}
 
-	return callContract(contracts.SystemCaller, polyBFTConfig.RewardConfig.TokenAddress, input,
+	return big.NewInt(0), callContract(contracts.SystemCaller, polyBFTConfig.RewardConfig.TokenAddress, input,
 		"RewardToken.mint", transition)
 }
 
+// fundNativeRewardWallet makes sure that reward wallet holds the configured amount of native tokens,
+// if native reward minting is active from genesis. Missing amount is minted (capped by the mint cap)
+// if native token is mintable, otherwise an error is returned.
+// It returns amount of native tokens minted to the reward wallet.
+func fundNativeRewardWallet(polyBFTConfig PolyBFTConfig, transition *state.Transition) (*big.Int, error) {
+	if !isNativeRewardMintActive(polyBFTConfig, 0) {
+		// reward wallet is funded through premine
+		return big.NewInt(0), nil
+	}
+
+	rewardCfg := polyBFTConfig.RewardConfig
+	balance := transition.GetBalance(rewardCfg.WalletAddress)
+
+	missingAmount := new(big.Int).Sub(rewardCfg.WalletAmount, balance)
+	if missingAmount.Sign() <= 0 {
+		// reward wallet is already funded through premine
+		return big.NewInt(0), nil
+	}
+
+	if !isMintableNativeToken(polyBFTConfig) {
+		return nil, fmt.Errorf("%w: wallet %s has %s, but %s is required",
+			errInsufficientRewardWallet, rewardCfg.WalletAddress, balance, rewardCfg.WalletAmount)
+	}
+
+	mintAmount, capped := capRewardMint(rewardCfg, missingAmount)
+	if capped {
+		// reward wallet holds less than the configured amount (reported the same way as capped epoch mints)
+		metrics.IncrCounter([]string{consensusMetricsPrefix, "reward_mint_capped"}, 1)
+	}
+
+	// total supply of native token is initialized after the reward wallet is funded,
+	// so the minted amount is added to the initial total supply by the caller
+	transition.Txn().AddBalance(rewardCfg.WalletAddress, mintAmount)
+
+	return mintAmount, nil
+}
+
+// nativeRewardsMintHook returns an executor hook, which tops up reward wallet with newly minted native tokens
+// right before the distribute rewards transaction of an epoch is applied, starting from the mint activation block.
+// Minted amount is capped by the mint cap (if any), which is logged and reported by a metric.
+// It returns nil if native token is not a mintable reward token or minting is not activated,
+// since reward wallet is pre-funded in that case and distribute rewards transaction fails once it is exhausted.
+func nativeRewardsMintHook(polyBFTConfig PolyBFTConfig,
+	logger hclog.Logger) func(*state.Transition, *types.Transaction) {
+	if polyBFTConfig.RewardConfig == nil || polyBFTConfig.RewardConfig.MintActivationBlock == nil ||
+		!isNativeRewardToken(polyBFTConfig) || !isMintableNativeToken(polyBFTConfig) {
+		return nil
+	}
+
+	rewardCfg := polyBFTConfig.RewardConfig
+	distributeRewardsSig := new(contractsapi.DistributeRewardForRewardPoolFn).Sig()
+
+	return func(transition *state.Transition, msg *types.Transaction) {
+		if msg.Type != types.StateTx || msg.From != contracts.SystemCaller ||
+			msg.To == nil || *msg.To != contracts.RewardPoolContract ||
+			!bytes.HasPrefix(msg.Input, distributeRewardsSig) {
+			return
+		}
+
+		blockNumber := uint64(transition.GetTxContext().Number)
+		if !isNativeRewardMintActive(polyBFTConfig, blockNumber) {
+			return
+		}
+
+		missingAmount := new(big.Int).Sub(rewardCfg.WalletAmount, transition.GetBalance(rewardCfg.WalletAddress))
+		if missingAmount.Sign() <= 0 {
+			return
+		}
+
+		mintAmount, capped := capRewardMint(rewardCfg, missingAmount)
+		if capped {
+			logger.Warn("reward tokens mint cap exceeded, reward wallet is topped up partially",
+				"block", blockNumber, "wallet", rewardCfg.WalletAddress, "missing", missingAmount,
+				"mintCap", rewardCfg.MintCap)
+			metrics.IncrCounter([]string{consensusMetricsPrefix, "reward_mint_capped"}, 1)
+		}
+
+		transition.Txn().AddBalance(rewardCfg.WalletAddress, mintAmount)
+
+		// keep total supply of native token in line with the balances
+		totalSupply := transition.GetStorage(contracts.NativeERC20TokenContract, nativeERC20MintableTotalSupplySlot)
+		totalSupply = types.BytesToHash(new(big.Int).Add(
+			new(big.Int).SetBytes(totalSupply.Bytes()), mintAmount).Bytes())
+		transition.SetState(contracts.NativeERC20TokenContract, nativeERC20MintableTotalSupplySlot, totalSupply)
+	}
+}
+
+// capRewardMint caps the given amount of native reward tokens to mint by the configured mint cap.
+// It returns the amount to mint and whether it was capped.
+func capRewardMint(rewardCfg *RewardsConfig, amount *big.Int) (*big.Int, bool) {
+	if rewardCfg.MintCap != nil && amount.Cmp(rewardCfg.MintCap) > 0 {
+		return new(big.Int).Set(rewardCfg.MintCap), true
+	}
+
+	return amount, false
+}
+
+// isNativeRewardMintActive returns true if native token is a reward token,
+// whose reward wallet is funded by minting (or checked to be pre-funded) from the given block on
+func isNativeRewardMintActive(cfg PolyBFTConfig, blockNumber uint64) bool {
+	return cfg.RewardConfig != nil && isNativeRewardToken(cfg) &&
+		cfg.RewardConfig.MintActivationBlock != nil && blockNumber >= *cfg.RewardConfig.MintActivationBlock
+}
+
+// isMintableNativeToken returns true in case a native token is mintable
+func isMintableNativeToken(cfg PolyBFTConfig) bool {
+	return cfg.NativeTokenConfig != nil && cfg.NativeTokenConfig.IsMintable
+}
+
 // approveRewardPoolAsSpender approves reward pool contract as reward token spender
 // since reward pool distributes rewards.
 func approveRewardPoolAsSpender(polyBFTConfig PolyBFTConfig, transition *state.Transition) error {
@@ -44,6 +44,7 @@ var (
 	errProposalDontMatch = errors.New("failed to insert proposal, because the validated proposal " +
 		"is either nil or it does not match the received one")
 	errValidatorSetDeltaMismatch        = errors.New("validator set delta mismatch")
+	errCommitmentTxMismatch             = errors.New("commitment transaction does not match the local state syncs")
 	errValidatorsUpdateInNonEpochEnding = errors.New("trying to update validator set in a non epoch ending block")
 )
 
@@ -101,6 +102,16 @@ type fsm struct {
 
 	// newValidatorsDelta carries the updates of validator set on epoch ending block
 	newValidatorsDelta *validator.ValidatorSetDelta
+
+	// proposalTimeout is the time given to a proposer of a round, derived from block time and block time drift
+	proposalTimeout time.Duration
+
+	// strictStateTxVerification indicates whether the commitment of a proposal
+	// must commit the locally stored state sync events
+	strictStateTxVerification bool
+
+	// stateSyncManager verifies the proposed commitment against the locally stored state sync events
+	stateSyncManager StateSyncManager
 }
 
 // BuildProposal builds a proposal for the current round (used if proposer)
@@ -441,6 +452,12 @@ func (f *fsm) VerifyStateTransactions(transactions []*types.Transaction) error {
 			if err = verifyBridgeCommitmentTx(tx.Hash, stateTxData, f.validators); err != nil {
 				return err
 			}
+
+			if f.strictStateTxVerification {
+				if err := f.stateSyncManager.VerifyCommitment(stateTxData.Message); err != nil {
+					return fmt.Errorf("%w: %v (tx hash=%s)", errCommitmentTxMismatch, err, tx.Hash)
+				}
+			}
 		case *contractsapi.CommitEpochValidatorSetFn:
 			if commitEpochTxExists {
 				// if we already validated commit epoch tx,
@@ -618,6 +618,112 @@ func TestFSM_VerifyStateTransactions_StateTransactionInvalidSignature(t *testing
 	require.ErrorContains(t, err, "invalid signature")
 }
 
+func TestFSM_VerifyStateTransactions_StrictVerification(t *testing.T) {
+	t.Parallel()
+
+	validators := validator.NewTestValidators(t, 5)
+	validatorSet := validator.NewValidatorSet(validators.GetPublicIdentities(), hclog.NewNullLogger())
+
+	stateSyncManager := newTestStateSyncManager(t, validators.GetValidator("0"))
+	stateSyncEvents := generateStateSyncEvents(t, 5, 0)
+	insertTestStateSyncEvents(t, stateSyncManager.state.StateSyncStore, stateSyncEvents[:4]...)
+
+	createSignedCommitment := func(events []*contractsapi.StateSyncedEvent) *CommitmentMessageSigned {
+		pendingCommitment, err := NewPendingCommitment(1, events, maxCommitmentSize)
+		require.NoError(t, err)
+
+		commitment := &CommitmentMessageSigned{Message: pendingCommitment.StateSyncCommitment}
+		signTestCommitment(t, validators, commitment)
+
+		return commitment
+	}
+
+	// commitment of the stored state syncs, which differs from the one the validator would propose
+	proposedCommitment := createSignedCommitment(stateSyncEvents[:4])
+	localCommitment := createSignedCommitment(stateSyncEvents[:2])
+
+	// quorum signed commitment of the stored state syncs with an altered root
+	alteredCommitment := &CommitmentMessageSigned{
+		Message: &contractsapi.StateSyncCommitment{
+			StartID: proposedCommitment.Message.StartID,
+			EndID:   proposedCommitment.Message.EndID,
+			Root:    types.StringToHash("0xabcd"),
+		},
+	}
+	signTestCommitment(t, validators, alteredCommitment)
+
+	// quorum signed commitment of a state sync which is not stored
+	unknownCommitment := createSignedCommitment(stateSyncEvents)
+
+	createFSM := func(strict bool) *fsm {
+		return &fsm{
+			isEndOfSprint:                true,
+			validators:                   validatorSet,
+			proposerCommitmentToRegister: localCommitment,
+			strictStateTxVerification:    strict,
+			stateSyncManager:             stateSyncManager,
+			logger:                       hclog.NewNullLogger(),
+		}
+	}
+
+	createCommitmentTx := func(commitment *CommitmentMessageSigned) *types.Transaction {
+		input, err := commitment.EncodeAbi()
+		require.NoError(t, err)
+
+		return createStateTransactionWithData(contracts.StateReceiverContract, input)
+	}
+
+	proposedCommitmentTx := createCommitmentTx(proposedCommitment)
+
+	t.Run("commitment of the stored state syncs is accepted", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, createFSM(true).VerifyStateTransactions([]*types.Transaction{proposedCommitmentTx}))
+	})
+
+	t.Run("altered commitment is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		alteredCommitmentTx := createCommitmentTx(alteredCommitment)
+
+		err := createFSM(true).VerifyStateTransactions([]*types.Transaction{alteredCommitmentTx})
+		require.ErrorIs(t, err, errCommitmentTxMismatch)
+
+		// it is only checked to be signed by the quorum if verification is not strict
+		require.NoError(t, createFSM(false).VerifyStateTransactions([]*types.Transaction{alteredCommitmentTx}))
+	})
+
+	t.Run("commitment of unknown state syncs is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		err := createFSM(true).VerifyStateTransactions([]*types.Transaction{createCommitmentTx(unknownCommitment)})
+		require.ErrorIs(t, err, errCommitmentTxMismatch)
+	})
+
+	t.Run("extra state transaction is rejected", func(t *testing.T) {
+		t.Parallel()
+
+		err := createFSM(true).VerifyStateTransactions(
+			[]*types.Transaction{proposedCommitmentTx, createCommitmentTx(localCommitment)})
+		require.ErrorContains(t, err, "only one commitment tx is allowed per block")
+
+		commitEpochInput, err := createTestCommitEpochInput(t, 0, 10).EncodeAbi()
+		require.NoError(t, err)
+
+		commitEpochTx := createStateTransactionWithData(contracts.ValidatorSetContract, commitEpochInput)
+
+		err = createFSM(true).VerifyStateTransactions([]*types.Transaction{proposedCommitmentTx, commitEpochTx})
+		require.ErrorIs(t, err, errCommitEpochTxNotExpected)
+	})
+
+	t.Run("block without commitment is accepted", func(t *testing.T) {
+		t.Parallel()
+
+		// the proposer may not have gathered the quorum of commitment votes yet
+		require.NoError(t, createFSM(true).VerifyStateTransactions(nil))
+	})
+}
+
 func TestFSM_ValidateCommit_WrongValidator(t *testing.T) {
 	t.Parallel()
 
@@ -1567,7 +1673,7 @@ func createTestCommitment(t *testing.T, accounts []*wallet.Account) *CommitmentM
 		bitmap.Set(uint64(i))
 	}
 
-	commitment, err := NewPendingCommitment(1, stateSyncEvents)
+	commitment, err := NewPendingCommitment(1, stateSyncEvents, uint64(len(stateSyncEvents)))
 	require.NoError(t, err)
 
 	hash, err := commitment.Hash()
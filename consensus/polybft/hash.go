@@ -3,6 +3,7 @@ package polybft
 import (
 	"sync"
 
+	"github.com/0xPolygon/polygon-edge/helper/keccak"
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
@@ -15,18 +16,34 @@ func setupHeaderHashFunc() {
 		originalHeaderHash := types.HeaderHash
 
 		types.HeaderHash = func(h *types.Header) types.Hash {
-			// when hashing the block for signing we have to remove from
-			// the extra field the seal and committed seal items
-			extra, err := GetIbftExtraClean(h.ExtraData)
-			if err != nil {
-				return types.ZeroHash
-			}
-
-			// override extra data without seals and committed seal items
-			hh := h.Copy()
-			hh.ExtraData = extra
-
-			return originalHeaderHash(hh)
+			return headerHashWithoutSeals(h, originalHeaderHash)
 		}
 	})
 }
+
+// headerHashWithoutSeals hashes the given header with the given hash function,
+// after removing the seal and committed seal items from its extra data
+func headerHashWithoutSeals(h *types.Header, hashFn func(*types.Header) types.Hash) types.Hash {
+	// when hashing the block for signing we have to remove from
+	// the extra field the seal and committed seal items
+	extra, err := GetIbftExtraClean(h.ExtraData)
+	if err != nil {
+		return types.ZeroHash
+	}
+
+	// override extra data without seals and committed seal items
+	hh := h.Copy()
+	hh.ExtraData = extra
+
+	return hashFn(hh)
+}
+
+// ComputeBlockHash computes the hash of the given header exactly as the block builder does,
+// meaning that the seals of the polybft extra data are not hashed.
+// It returns zero hash if the extra data can not be decoded.
+// Global header hash function is left intact.
+func ComputeBlockHash(header *types.Header) types.Hash {
+	return headerHashWithoutSeals(header, func(h *types.Header) types.Hash {
+		return types.BytesToHash(keccak.Keccak256(nil, h.MarshalRLP()))
+	})
+}
@@ -1,14 +1,21 @@
 package polybft
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
+	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/bitmap"
 	bls "github.com/0xPolygon/polygon-edge/consensus/polybft/signer"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/wallet"
+	"github.com/0xPolygon/polygon-edge/state"
+	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_setupHeaderHashFunc(t *testing.T) {
@@ -37,3 +44,60 @@ func Test_setupHeaderHashFunc(t *testing.T) {
 	header.ExtraData = []byte{1, 2, 3, 4, 100, 200, 255}
 	assert.Equal(t, types.ZeroHash, types.HeaderHash(header)) // to small extra data
 }
+
+func TestComputeBlockHash(t *testing.T) {
+	mstate := itrie.NewState(itrie.NewMemoryStorage())
+	executor := state.NewExecutor(&chain.Params{Forks: &chain.Forks{}}, mstate, hclog.NewNullLogger())
+	executor.GetHash = func(header *types.Header) func(i uint64) types.Hash {
+		return func(i uint64) types.Hash {
+			return types.ZeroHash
+		}
+	}
+
+	stateRoot, err := executor.WriteGenesis(nil, types.ZeroHash)
+	require.NoError(t, err)
+
+	txPool := &txPoolMock{}
+	txPool.On("Prepare", uint64(0)).Once()
+
+	bb := NewBlockBuilder(&BlockBuilderParams{
+		BlockTime: time.Second,
+		Parent:    &types.Header{Number: 4, StateRoot: stateRoot, GasLimit: 1_000_000},
+		Coinbase:  types.StringToAddress("0x1"),
+		Executor:  executor,
+		GasLimit:  1_000_000,
+		TxPool:    txPool,
+		Logger:    hclog.NewNullLogger(),
+	})
+	require.NoError(t, bb.Reset())
+
+	extra := &Extra{
+		Validators: &validator.ValidatorSetDelta{},
+		Parent:     &Signature{},
+		Checkpoint: &CheckpointData{EpochNumber: 1, BlockRound: 2},
+		Committed:  &Signature{},
+	}
+
+	fb, err := bb.Build(func(h *types.Header) {
+		h.ExtraData = extra.MarshalRLPTo(nil)
+	})
+	require.NoError(t, err)
+
+	header := fb.Block.Header.Copy()
+	require.Equal(t, fb.Block.Hash(), ComputeBlockHash(header))
+
+	// seals added to the extra data after the block is built do not change its hash
+	extra.Committed = createSignature(t, []*wallet.Account{generateTestAccount(t)},
+		types.ZeroHash, bls.DomainCheckpointManager)
+	header.ExtraData = extra.MarshalRLPTo(nil)
+	require.Equal(t, fb.Block.Hash(), ComputeBlockHash(header))
+
+	// while the other fields do
+	header.Timestamp++
+	require.NotEqual(t, fb.Block.Hash(), ComputeBlockHash(header))
+
+	// global header hash function is not replaced
+	headerHash := reflect.ValueOf(types.HeaderHash).Pointer()
+	ComputeBlockHash(header)
+	require.Equal(t, headerHash, reflect.ValueOf(types.HeaderHash).Pointer())
+}
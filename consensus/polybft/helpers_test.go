@@ -108,15 +108,26 @@ func generateStateSyncEvents(t *testing.T, eventsCount int, startIdx uint64) []*
 	stateSyncEvents := make([]*contractsapi.StateSyncedEvent, eventsCount)
 	for i := 0; i < eventsCount; i++ {
 		stateSyncEvents[i] = &contractsapi.StateSyncedEvent{
-			ID:     big.NewInt(int64(startIdx + uint64(i))),
-			Sender: types.StringToAddress(fmt.Sprintf("0x5%d", i)),
-			Data:   generateRandomBytes(t),
+			ID:       big.NewInt(int64(startIdx + uint64(i))),
+			Sender:   types.StringToAddress(fmt.Sprintf("0x5%d", i)),
+			Receiver: types.StringToAddress(fmt.Sprintf("0x6%d", i)),
+			Data:     generateRandomBytes(t),
 		}
 	}
 
 	return stateSyncEvents
 }
 
+// insertTestStateSyncEvents inserts given state sync events to the store
+func insertTestStateSyncEvents(t *testing.T, store *StateSyncStore, events ...*contractsapi.StateSyncedEvent) {
+	t.Helper()
+
+	for _, event := range events {
+		_, err := store.insertStateSyncEvent(event)
+		require.NoError(t, err)
+	}
+}
+
 // generateRandomBytes generates byte array with random data of 32 bytes length
 func generateRandomBytes(t *testing.T) (result []byte) {
 	t.Helper()
@@ -0,0 +1,66 @@
+package polybft
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// logLevels maps names of polybft subsystems (names of their loggers, e.g. "fsm" or "state-sync-manager")
+// to log levels overriding the default log level for the given subsystem
+type logLevels map[string]hclog.Level
+
+// parseLogLevels parses log levels configured per subsystem (subsystem name -> level name)
+func parseLogLevels(raw interface{}) (logLevels, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	rawLevels, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid log levels configuration: %v", raw)
+	}
+
+	levels := make(logLevels, len(rawLevels))
+
+	for subsystem, rawLevel := range rawLevels {
+		levelName, ok := rawLevel.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid log level for subsystem %s: %v", subsystem, rawLevel)
+		}
+
+		level := hclog.LevelFromString(levelName)
+		if level == hclog.NoLevel {
+			return nil, fmt.Errorf("invalid log level for subsystem %s: %s", subsystem, levelName)
+		}
+
+		levels[subsystem] = level
+	}
+
+	return levels, nil
+}
+
+// named creates a sub logger with the given subsystem name, having the log level configured for that subsystem.
+// Note that the level is overridden only for the sub logger if its parent is created with independent levels.
+func (l logLevels) named(logger hclog.Logger, subsystem string) hclog.Logger {
+	subLogger := logger.Named(subsystem)
+	l.apply(subLogger, subsystem)
+
+	return subLogger
+}
+
+// apply sets the log level configured for the given subsystem (if any) to the already named logger
+func (l logLevels) apply(logger hclog.Logger, subsystem string) {
+	if level, ok := l[subsystem]; ok {
+		logger.SetLevel(level)
+	}
+}
+
+// level returns the log level configured for the given subsystem, or hclog.NoLevel if none is configured
+func (l logLevels) level(subsystem string) hclog.Level {
+	if level, ok := l[subsystem]; ok {
+		return level
+	}
+
+	return hclog.NoLevel
+}
@@ -0,0 +1,64 @@
+package polybft
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogLevels_Parse(t *testing.T) {
+	t.Parallel()
+
+	levels, err := parseLogLevels(nil)
+	require.NoError(t, err)
+	require.Empty(t, levels)
+
+	levels, err = parseLogLevels(map[string]interface{}{"fsm": "error", "state-sync-manager": "DEBUG"})
+	require.NoError(t, err)
+	require.Equal(t, logLevels{"fsm": hclog.Error, "state-sync-manager": hclog.Debug}, levels)
+
+	_, err = parseLogLevels(map[string]interface{}{"fsm": "verbose"})
+	require.ErrorContains(t, err, "invalid log level for subsystem fsm")
+
+	_, err = parseLogLevels(map[string]interface{}{"fsm": 1})
+	require.ErrorContains(t, err, "invalid log level for subsystem fsm")
+
+	_, err = parseLogLevels("debug")
+	require.ErrorContains(t, err, "invalid log levels configuration")
+}
+
+func TestLogLevels_SubsystemLevelOverridesDefault(t *testing.T) {
+	t.Parallel()
+
+	var output bytes.Buffer
+
+	logger := hclog.New(&hclog.LoggerOptions{
+		Level:             hclog.Info,
+		Output:            &output,
+		IndependentLevels: true,
+	})
+
+	levels := logLevels{"state-sync-manager": hclog.Debug, "fsm": hclog.Error}
+
+	stateSyncLogger := levels.named(logger, "state-sync-manager")
+	fsmLogger := levels.named(logger, "fsm")
+	runtimeLogger := levels.named(logger, "consensus_runtime")
+
+	stateSyncLogger.Debug("state sync debug")
+	fsmLogger.Info("fsm info")
+	runtimeLogger.Debug("runtime debug")
+	runtimeLogger.Info("runtime info")
+	logger.Debug("root debug")
+
+	logs := output.String()
+	require.Contains(t, logs, "state-sync-manager: state sync debug")
+	require.NotContains(t, logs, "fsm info")
+	require.NotContains(t, logs, "runtime debug")
+	require.Contains(t, logs, "consensus_runtime: runtime info")
+	require.NotContains(t, logs, "root debug")
+
+	require.Equal(t, hclog.Debug, levels.level("state-sync-manager"))
+	require.Equal(t, hclog.NoLevel, levels.level("event_tracker"))
+}
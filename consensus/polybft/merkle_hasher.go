@@ -0,0 +1,48 @@
+package polybft
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"hash"
+
+	"github.com/0xPolygon/polygon-edge/crypto"
+)
+
+const (
+	// Keccak256MerkleHashScheme is the name of the default merkle hashing scheme of the commitments,
+	// which is the one used by the StateReceiver contract
+	Keccak256MerkleHashScheme = "keccak256"
+	// SHA256MerkleHashScheme is the name of the SHA-256 based merkle hashing scheme of the commitments
+	SHA256MerkleHashScheme = "sha256"
+)
+
+// MerkleHasher creates hash instances used for hashing merkle tree nodes of the commitments.
+// A new instance is created for each tree or proof verification, since hash instances are stateful.
+type MerkleHasher func() hash.Hash
+
+// defaultMerkleHasher is the merkle hasher used if no merkle hasher is provided
+var defaultMerkleHasher MerkleHasher = func() hash.Hash {
+	return crypto.NewKeccakState()
+}
+
+// newMerkleHasher creates a merkle hasher for the given hashing scheme.
+// Keccak256 hashing scheme is used if no scheme is provided.
+func newMerkleHasher(scheme string) (MerkleHasher, error) {
+	switch scheme {
+	case "", Keccak256MerkleHashScheme:
+		return defaultMerkleHasher, nil
+	case SHA256MerkleHashScheme:
+		return sha256.New, nil
+	default:
+		return nil, fmt.Errorf("unsupported merkle hash scheme: %s", scheme)
+	}
+}
+
+// newHash creates a new hash instance, falling back to the default merkle hasher if h is nil
+func (h MerkleHasher) newHash() hash.Hash {
+	if h == nil {
+		return defaultMerkleHasher()
+	}
+
+	return h()
+}
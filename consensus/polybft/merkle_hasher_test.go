@@ -0,0 +1,117 @@
+package polybft
+
+import (
+	"crypto/sha256"
+	"hash"
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
+	"github.com/0xPolygon/polygon-edge/crypto"
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerkleHasher_NewMerkleHasher(t *testing.T) {
+	t.Parallel()
+
+	for _, scheme := range []string{"", Keccak256MerkleHashScheme} {
+		hasher, err := newMerkleHasher(scheme)
+		require.NoError(t, err)
+		require.IsType(t, crypto.NewKeccakState(), hasher.newHash())
+	}
+
+	hasher, err := newMerkleHasher(SHA256MerkleHashScheme)
+	require.NoError(t, err)
+	require.IsType(t, sha256.New(), hasher.newHash())
+
+	_, err = newMerkleHasher("blake2b")
+	require.ErrorContains(t, err, "unsupported merkle hash scheme")
+
+	// nil hasher falls back to the default one
+	require.IsType(t, crypto.NewKeccakState(), MerkleHasher(nil).newHash())
+}
+
+func TestMerkleHasher_CommitmentProofs(t *testing.T) {
+	t.Parallel()
+
+	keccakHasher, err := newMerkleHasher(Keccak256MerkleHashScheme)
+	require.NoError(t, err)
+
+	sha256Hasher, err := newMerkleHasher(SHA256MerkleHashScheme)
+	require.NoError(t, err)
+
+	events := generateStateSyncEvents(t, 5, 0)
+
+	keccakCommitment, err := NewPendingCommitmentWithHasher(1, events, maxCommitmentSize, keccakHasher)
+	require.NoError(t, err)
+
+	sha256Commitment, err := NewPendingCommitmentWithHasher(1, events, maxCommitmentSize, sha256Hasher)
+	require.NoError(t, err)
+
+	// default hasher is keccak256
+	defaultCommitment, err := NewPendingCommitment(1, events, maxCommitmentSize)
+	require.NoError(t, err)
+	require.Equal(t, keccakCommitment.Root, defaultCommitment.Root)
+
+	require.NotEqual(t, keccakCommitment.Root, sha256Commitment.Root)
+
+	cases := []struct {
+		commitment  *PendingCommitment
+		hasher      MerkleHasher
+		otherHasher MerkleHasher
+	}{
+		{commitment: keccakCommitment, hasher: keccakHasher, otherHasher: sha256Hasher},
+		{commitment: sha256Commitment, hasher: sha256Hasher, otherHasher: keccakHasher},
+	}
+
+	for _, c := range cases {
+		signedCommitment := &CommitmentMessageSigned{Message: c.commitment.StateSyncCommitment}
+
+		for _, event := range events {
+			leaf, err := event.EncodeAbi()
+			require.NoError(t, err)
+
+			proof, err := c.commitment.MerkleTree.GenerateProof(leaf)
+			require.NoError(t, err)
+
+			require.NoError(t, signedCommitment.VerifyStateSyncProofWithHasher(proof, event, c.hasher))
+			require.Error(t, signedCommitment.VerifyStateSyncProofWithHasher(proof, event, c.otherHasher))
+		}
+	}
+}
+
+func TestMerkleHasher_StateSyncManager(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.config.merkleHasher = func() hash.Hash { return sha256.New() }
+	s.validatorSet = vals.ToValidatorSet()
+
+	for _, event := range generateStateSyncEvents(t, 5, 0) {
+		insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
+	}
+
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 1)
+
+	commitment := &CommitmentMessageSigned{Message: s.pendingCommitments[0].StateSyncCommitment}
+	signTestCommitment(t, vals, commitment)
+
+	txData, err := commitment.EncodeAbi()
+	require.NoError(t, err)
+
+	require.NoError(t, s.PostBlock(&PostBlockRequest{
+		FullBlock: &types.FullBlock{
+			Block: &types.Block{
+				Header:       &types.Header{Number: 1},
+				Transactions: []*types.Transaction{createStateTransactionWithData(types.Address{}, txData)},
+			},
+		},
+	}))
+
+	// proofs built by the state sync manager are consistent only with the configured hasher
+	require.NoError(t, ReplayCommitments(s.state, 0, 4, s.config.merkleHasher))
+	require.ErrorIs(t, ReplayCommitments(s.state, 0, 4, nil), errInconsistentCommitment)
+}
@@ -4,7 +4,9 @@ import (
 	"time"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/wallet"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
 	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/syncer"
@@ -50,6 +52,10 @@ func (m *blockchainMock) GetStateProviderForBlock(block *types.Header) (contract
 	args := m.Called(block)
 	stateProvider, _ := args.Get(0).(contract.Provider)
 
+	if len(args) > 1 {
+		return stateProvider, args.Error(1)
+	}
+
 	return stateProvider, nil
 }
 
@@ -83,6 +89,27 @@ func (m *blockchainMock) GetHeaderByNumber(number uint64) (*types.Header, bool)
 	panic("Unsupported mock for GetHeaderByNumber") //nolint:gocritic
 }
 
+func (m *blockchainMock) HeaderByNumber(number uint64) (*types.Header, error) {
+	args := m.Called(number)
+
+	if len(args) == 1 {
+		getHeaderCallback, ok := args.Get(0).(func(number uint64) *types.Header)
+		if ok {
+			if h := getHeaderCallback(number); h != nil {
+				return h, nil
+			}
+
+			return nil, blockchain.ErrBlockNotFound
+		}
+	} else if len(args) == 2 {
+		header, _ := args.Get(0).(*types.Header)
+
+		return header, args.Error(1)
+	}
+
+	panic("Unsupported mock for HeaderByNumber") //nolint:gocritic
+}
+
 func (m *blockchainMock) GetHeaderByHash(hash types.Hash) (*types.Header, bool) {
 	args := m.Called(hash)
 	header, ok := args.Get(0).(*types.Header)
@@ -111,6 +138,12 @@ func (m *blockchainMock) SubscribeEvents() blockchain.Subscription {
 	return nil
 }
 
+func (m *blockchainMock) SubscribeReorgs() blockchain.ReorgSubscription {
+	args := m.Called()
+
+	return args.Get(0).(blockchain.ReorgSubscription) //nolint:forcetypeassert
+}
+
 func (m *blockchainMock) CalculateGasLimit(number uint64) (uint64, error) {
 	return 0, nil
 }
@@ -238,6 +271,43 @@ func (m *systemStateMock) GetEpoch() (uint64, error) {
 	return 0, nil
 }
 
+func (m *systemStateMock) IsStateSyncExecuted(stateSyncID uint64) (bool, error) {
+	args := m.Called(stateSyncID)
+
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *systemStateMock) GetCommitmentByStateSyncID(stateSyncID uint64) (*contractsapi.StateSyncCommitment, error) {
+	args := m.Called(stateSyncID)
+
+	commitment, _ := args.Get(0).(*contractsapi.StateSyncCommitment)
+
+	return commitment, args.Error(1)
+}
+
+var _ StakeManager = (*stakeManagerMock)(nil)
+
+type stakeManagerMock struct {
+	mock.Mock
+}
+
+func (m *stakeManagerMock) PostBlock(req *PostBlockRequest) error {
+	return m.Called(req).Error(0)
+}
+
+func (m *stakeManagerMock) PostEpoch(req *PostEpochRequest) error {
+	return m.Called(req).Error(0)
+}
+
+func (m *stakeManagerMock) UpdateValidatorSet(epoch uint64,
+	currentValidatorSet validator.AccountSet) (*validator.ValidatorSetDelta, error) {
+	args := m.Called(epoch, currentValidatorSet)
+
+	delta, _ := args.Get(0).(*validator.ValidatorSetDelta)
+
+	return delta, args.Error(1)
+}
+
 var _ contract.Provider = (*stateProviderMock)(nil)
 
 type stateProviderMock struct {
@@ -379,3 +449,55 @@ func init() {
 	// setup custom hash header func
 	setupHeaderHashFunc()
 }
+
+var _ AggregateSigner = (*aggregateSignerMock)(nil)
+
+type aggregateSignerMock struct {
+	mock.Mock
+}
+
+func (a *aggregateSignerMock) Sign(key *wallet.Key, message, domain []byte) ([]byte, error) {
+	args := a.Called(key, message, domain)
+
+	return args.Get(0).([]byte), args.Error(1) //nolint:forcetypeassert
+}
+
+func (a *aggregateSignerMock) Verify(signer *validator.ValidatorMetadata, signature, message, domain []byte) error {
+	args := a.Called(signer, signature, message, domain)
+
+	return args.Error(0)
+}
+
+func (a *aggregateSignerMock) Aggregate(signatures [][]byte) ([]byte, error) {
+	args := a.Called(signatures)
+
+	return args.Get(0).([]byte), args.Error(1) //nolint:forcetypeassert
+}
+
+var _ StateSyncManager = (*stateSyncManagerMock)(nil)
+
+// stateSyncManagerMock is a state sync manager, whose commitment resolution can be mocked
+type stateSyncManagerMock struct {
+	dummyStateSyncManager
+	mock.Mock
+}
+
+func (s *stateSyncManagerMock) Status() *StateSyncStatus {
+	args := s.Called()
+
+	return args.Get(0).(*StateSyncStatus) //nolint:forcetypeassert
+}
+
+func (s *stateSyncManagerMock) Commitment() (*CommitmentMessageSigned, error) {
+	args := s.Called()
+
+	commitment, _ := args.Get(0).(*CommitmentMessageSigned)
+
+	return commitment, args.Error(1)
+}
+
+func (s *stateSyncManagerMock) PostSprint() error {
+	args := s.Called()
+
+	return args.Error(0)
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/consensus"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
@@ -34,6 +35,7 @@ const (
 
 var (
 	errMissingBridgeConfig = errors.New("invalid genesis configuration, missing bridge configuration")
+	errRuntimeNotStarted   = errors.New("consensus runtime is not started")
 )
 
 // polybftBackend is an interface defining polybft methods needed by fsm and sync tracker
@@ -44,15 +46,21 @@ type polybftBackend interface {
 
 // Factory is the factory function to create a discovery consensus
 func Factory(params *consensus.Params) (consensus.Consensus, error) {
-	logger := params.Logger.Named("polybft")
+	logLevels, err := parseLogLevels(params.Config.Config["logLevels"])
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logLevels.named(params.Logger, "polybft")
 
 	setupHeaderHashFunc()
 
 	polybft := &Polybft{
-		config:  params,
-		closeCh: make(chan struct{}),
-		logger:  logger,
-		txPool:  params.TxPool,
+		config:    params,
+		closeCh:   make(chan struct{}),
+		logger:    logger,
+		txPool:    params.TxPool,
+		logLevels: logLevels,
 	}
 
 	// initialize polybft consensus config
@@ -66,6 +74,15 @@ func Factory(params *consensus.Params) (consensus.Consensus, error) {
 		return nil, err
 	}
 
+	rawRuntimeMode, _ := params.Config.Config["runtimeMode"].(string)
+
+	polybft.runtimeMode, err = parseRuntimeMode(rawRuntimeMode)
+	if err != nil {
+		return nil, err
+	}
+
+	polybft.requireValidatorKey, _ = params.Config.Config["requireValidatorKey"].(bool)
+
 	return polybft, nil
 }
 
@@ -117,6 +134,15 @@ type Polybft struct {
 
 	// tx pool as interface
 	txPool txPoolInterface
+
+	// runtimeMode defines whether node participates in consensus or only follows the chain
+	runtimeMode RuntimeMode
+
+	// requireValidatorKey defines whether node fails to start if its key is not in the current validator set
+	requireValidatorKey bool
+
+	// logLevels are log levels configured per subsystem
+	logLevels logLevels
 }
 
 func GenesisPostHookFactory(config *chain.Chain, engineName string) func(txn *state.Transition) error {
@@ -149,16 +175,24 @@ func GenesisPostHookFactory(config *chain.Chain, engineName string) func(txn *st
 			return err
 		}
 
+		if err = verifyInitialValidatorsStake(polyBFTConfig, transition); err != nil {
+			return err
+		}
+
 		// approve reward pool
 		if err = approveRewardPoolAsSpender(polyBFTConfig, transition); err != nil {
 			return err
 		}
 
 		// mint reward tokens to reward wallet
-		if err = mintRewardTokensToWallet(polyBFTConfig, transition); err != nil {
+		mintedRewards, err := mintRewardTokensToWallet(polyBFTConfig, transition)
+		if err != nil {
 			return err
 		}
 
+		// newly minted native reward tokens are part of the initial total supply as well
+		initialTotalSupply.Add(initialTotalSupply, mintedRewards)
+
 		// initialize RewardPool SC
 		if err = initRewardPool(polyBFTConfig, transition); err != nil {
 			return err
@@ -375,6 +409,12 @@ func ForkManagerFactory(forks *chain.Forks) error {
 func (p *Polybft) Initialize() error {
 	p.logger.Info("initializing polybft...")
 
+	if !p.consensusConfig.isSprintAligned() && !p.consensusConfig.AllowRaggedSprint {
+		p.logger.Warn("epoch size is not a multiple of sprint size, so the last sprint of each epoch does not end "+
+			"(allowRaggedSprint ends it together with the epoch)",
+			"epoch size", p.consensusConfig.EpochSize, "sprint size", p.consensusConfig.SprintSize)
+	}
+
 	// read account
 	account, err := wallet.NewAccountFromSecret(p.config.SecretsManager)
 	if err != nil {
@@ -386,7 +426,7 @@ func (p *Polybft) Initialize() error {
 
 	// create and set syncer
 	p.syncer = syncer.NewSyncer(
-		p.config.Logger.Named("syncer"),
+		p.logLevels.named(p.config.Logger, "syncer"),
 		p.config.Network,
 		p.config.Blockchain,
 		time.Duration(p.config.BlockTime)*3*time.Second,
@@ -398,6 +438,13 @@ func (p *Polybft) Initialize() error {
 		executor:   p.config.Executor,
 	}
 
+	// top up reward wallet with minted native tokens at the end of each epoch
+	// (if native token is mintable and minting is activated)
+	if p.config.Executor != nil {
+		p.config.Executor.PreHook = nativeRewardsMintHook(*p.consensusConfig,
+			p.logLevels.named(p.config.Logger, "rewards"))
+	}
+
 	// create bridge and consensus topics
 	if err = p.createTopics(); err != nil {
 		return fmt.Errorf("cannot create topics: %w", err)
@@ -420,6 +467,7 @@ func (p *Polybft) Initialize() error {
 
 	p.state = stt
 	p.validatorsCache = newValidatorsSnapshotCache(p.config.Logger, stt, p.blockchain)
+	p.logLevels.apply(p.validatorsCache.logger, "validators_snapshot")
 
 	// create runtime
 	if err := p.initRuntime(); err != nil {
@@ -459,6 +507,9 @@ func (p *Polybft) Start() error {
 		return fmt.Errorf("failed to start syncer. Error: %w", err)
 	}
 
+	// notify consensus runtime about chain reorgs (subscribed before syncing, so that no reorg is missed)
+	go p.trackReorgs(p.blockchain.SubscribeReorgs())
+
 	// sync concurrently, retrying indefinitely
 	go common.RetryForever(context.Background(), time.Second, func(context.Context) error {
 		blockHandler := func(b *types.FullBlock) bool {
@@ -486,6 +537,31 @@ func (p *Polybft) Start() error {
 	return nil
 }
 
+// trackReorgs delivers chain reorganization events of the given subscription to the consensus runtime,
+// until polybft is closed
+func (p *Polybft) trackReorgs(reorgSub blockchain.ReorgSubscription) {
+	defer reorgSub.Close()
+
+	for {
+		select {
+		case <-p.closeCh:
+			return
+		case ev := <-reorgSub.GetReorgCh():
+			p.runtime.OnReorg(ev)
+		}
+	}
+}
+
+// roundTimeoutExtension returns the duration by which the IBFT round timeout is extended,
+// so that proposer has the whole block time slot to propose a block (zero if the extension is disabled)
+func (p *Polybft) roundTimeoutExtension() time.Duration {
+	if !p.consensusConfig.ExtendRoundTimeout {
+		return 0
+	}
+
+	return p.runtime.getProposalTimeout()
+}
+
 // initRuntime creates consensus runtime
 func (p *Polybft) initRuntime() error {
 	runtimeConfig := &runtimeConfig{
@@ -498,6 +574,13 @@ func (p *Polybft) initRuntime() error {
 		txPool:                p.txPool,
 		bridgeTopic:           p.bridgeTopic,
 		numBlockConfirmations: p.config.NumBlockConfirmations,
+		mode:                  p.runtimeMode,
+		requireValidatorKey:   p.requireValidatorKey,
+		logLevels:             p.logLevels,
+		systemStateRetries:    p.config.SystemStateRetries,
+		maxParentLag:          p.config.MaxParentLag,
+
+		syncBeforeBlockProduction: p.config.SyncBeforeBlockProduction,
 	}
 
 	runtime, err := newConsensusRuntime(p.logger, runtimeConfig)
@@ -563,20 +646,48 @@ func (p *Polybft) startConsensusProtocol() {
 			p.logger.Error("failed to query current validator set", "block number", latestHeader.Number, "error", err)
 		}
 
-		isValidator := currentValidators.ContainsNodeID(p.key.String())
+		// observer node never participates in consensus, even if it is in the validator set
+		isValidator := !p.runtime.isObserver() && currentValidators.ContainsNodeID(p.key.String())
 		p.runtime.setIsActiveValidator(isValidator)
 
 		p.txPool.SetSealing(isValidator) // update tx pool
 
 		if isValidator {
+			// node reached the network head once none of its peers is ahead of it
+			if !p.runtime.isSynced() && !p.syncer.HasSyncPeer() {
+				p.runtime.SetSynced(true)
+			}
+
 			// initialze FSM as a stateless ibft backend via runtime as an adapter
 			err = p.runtime.FSM()
 			if err != nil {
-				p.logger.Error("failed to create fsm", "block number", latestHeader.Number, "error", err)
+				switch {
+				case errors.Is(err, errNotSynced):
+					// expected while the node catches up after it starts, FSM creation is retried
+					p.logger.Debug("waiting to sync with the network before producing blocks",
+						"block number", latestHeader.Number)
+
+					// wait for the syncer to make progress
+					select {
+					case <-syncerBlockCh:
+					case <-time.After(p.consensusConfig.BlockTime.Duration):
+					case <-p.closeCh:
+						return
+					}
+				case errors.Is(err, errStaleParent):
+					// FSM creation is retried once the node syncs
+					p.logger.Warn("failed to create fsm", "block number", latestHeader.Number, "error", err)
+				default:
+					p.logger.Error("failed to create fsm", "block number", latestHeader.Number, "error", err)
+				}
 
 				continue
 			}
 
+			if extension := p.roundTimeoutExtension(); extension > 0 {
+				p.ibft.ExtendRoundTimeout(extension)
+			}
+
 			sequenceCh, stopSequence = p.ibft.runSequence(latestHeader.Number + 1)
 		}
 
@@ -626,11 +737,21 @@ func (p *Polybft) Close() error {
 	}
 
 	close(p.closeCh)
-	p.runtime.close()
+	p.runtime.Close()
 
 	return nil
 }
 
+// Health returns the liveness status of the consensus and the bridge,
+// so that orchestration can decide whether the node needs a restart
+func (p *Polybft) Health() (*HealthStatus, error) {
+	if p.runtime == nil {
+		return nil, errRuntimeNotStarted
+	}
+
+	return p.runtime.Health(), nil
+}
+
 // GetSyncProgression retrieves the current sync progression, if any
 func (p *Polybft) GetSyncProgression() *progress.Progression {
 	return p.syncer.GetSyncProgression()
@@ -2,7 +2,10 @@ package polybft
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
@@ -12,6 +15,11 @@ import (
 
 const ConsensusName = "polybft"
 
+var (
+	errInvalidPolyBFTConfig = errors.New("invalid polybft configuration")
+	errPolyBFTConfigMissing = errors.New("polybft engine config missing")
+)
+
 // PolyBFTConfig is the configuration file for the Polybft consensus protocol.
 type PolyBFTConfig struct {
 	// InitialValidatorSet are the genesis validators
@@ -29,9 +37,22 @@ type PolyBFTConfig struct {
 	// SprintSize is size of sprint
 	SprintSize uint64 `json:"sprintSize"`
 
+	// AllowRaggedSprint indicates whether the last sprint of an epoch, if epoch size is not a multiple
+	// of sprint size, is shorter and ends together with the epoch
+	AllowRaggedSprint bool `json:"allowRaggedSprint,omitempty"`
+
+	// StrictStateTxVerification indicates whether the bridge commitment of a proposed block must commit
+	// the locally stored state sync events (its merkle root is rebuilt and compared),
+	// rather than only being well-formed and signed
+	StrictStateTxVerification bool `json:"strictStateTxVerification,omitempty"`
+
 	// BlockTime is target frequency of blocks production
 	BlockTime common.Duration `json:"blockTime"`
 
+	// ExtendRoundTimeout indicates whether the IBFT round timeout is extended by the proposal timeout,
+	// so that the proposer has the whole block time slot to propose a block
+	ExtendRoundTimeout bool `json:"extendRoundTimeout,omitempty"`
+
 	// Governance is the initial governance address
 	Governance types.Address `json:"governance"`
 
@@ -54,6 +75,13 @@ type PolyBFTConfig struct {
 
 	// BlockTimeDrift defines the time slot in which a new block can be created
 	BlockTimeDrift uint64 `json:"blockTimeDrift"`
+
+	// MaxCommitmentSize is the maximum number of state sync events committed in a single sprint
+	MaxCommitmentSize uint64 `json:"maxCommitmentSize,omitempty"`
+
+	// SkipUnavailableUptimeLookback indicates whether uptime of the previous epoch blocks,
+	// which are not available in the chain history, is skipped instead of failing the uptime calculation
+	SkipUnavailableUptimeLookback bool `json:"skipUnavailableUptimeLookback,omitempty"`
 }
 
 // LoadPolyBFTConfig loads chain config from provided path and unmarshals PolyBFTConfig
@@ -73,19 +101,102 @@ func LoadPolyBFTConfig(chainConfigFile string) (PolyBFTConfig, error) {
 
 // GetPolyBFTConfig deserializes provided chain config and returns PolyBFTConfig
 func GetPolyBFTConfig(chainConfig *chain.Chain) (PolyBFTConfig, error) {
+	if chainConfig.Params == nil {
+		return PolyBFTConfig{}, errPolyBFTConfigMissing
+	}
+
 	consensusConfigJSON, err := json.Marshal(chainConfig.Params.Engine[ConsensusName])
 	if err != nil {
 		return PolyBFTConfig{}, err
 	}
 
-	var polyBFTConfig PolyBFTConfig
+	// engine section is either not present, or it is present but empty
+	if s := string(consensusConfigJSON); s == "null" || s == "{}" {
+		return PolyBFTConfig{}, fmt.Errorf("%w: chain config has no non-empty %s engine section",
+			errPolyBFTConfigMissing, ConsensusName)
+	}
+
+	// populate defaults for the values which are not provided in the chain config
+	polyBFTConfig := PolyBFTConfig{MaxCommitmentSize: maxCommitmentSize}
 	if err = json.Unmarshal(consensusConfigJSON, &polyBFTConfig); err != nil {
 		return PolyBFTConfig{}, err
 	}
 
+	if err = polyBFTConfig.Validate(); err != nil {
+		return PolyBFTConfig{}, err
+	}
+
 	return polyBFTConfig, nil
 }
 
+// Validate validates PolyBFTConfig values
+func (p *PolyBFTConfig) Validate() error {
+	if p.MaxCommitmentSize < 1 {
+		return fmt.Errorf("%w: max commitment size must be at least 1", errInvalidPolyBFTConfig)
+	}
+
+	if p.Bridge != nil && p.Bridge.MinCommitmentSize > p.MaxCommitmentSize {
+		return fmt.Errorf("%w: min commitment size (%d) must not exceed max commitment size (%d)",
+			errInvalidPolyBFTConfig, p.Bridge.MinCommitmentSize, p.MaxCommitmentSize)
+	}
+
+	if p.Bridge != nil && p.Bridge.QuorumWaitWarnFraction < 0 {
+		return fmt.Errorf("%w: quorum wait warn fraction must not be negative", errInvalidPolyBFTConfig)
+	}
+
+	if p.Bridge != nil {
+		switch p.Bridge.CommitmentGapPolicy {
+		case "", StrictCommitmentGapPolicy, RecoverCommitmentGapPolicy:
+		default:
+			return fmt.Errorf("%w: unsupported commitment gap policy: %s",
+				errInvalidPolyBFTConfig, p.Bridge.CommitmentGapPolicy)
+		}
+	}
+
+	if err := p.validateInitialValidators(); err != nil {
+		return err
+	}
+
+	if p.RewardConfig != nil {
+		if _, err := newUptimeRewardCurve(p.RewardConfig.UptimeRewardCurve); err != nil {
+			return fmt.Errorf("%w: %v", errInvalidPolyBFTConfig, err)
+		}
+	}
+
+	return nil
+}
+
+// validateInitialValidators checks that initial validators have well formed BLS keys,
+// and that there are no duplicate addresses or BLS keys amongst them
+func (p *PolyBFTConfig) validateInitialValidators() error {
+	addresses := make(map[types.Address]struct{}, len(p.InitialValidatorSet))
+	blsKeys := make(map[string]types.Address, len(p.InitialValidatorSet))
+
+	for _, v := range p.InitialValidatorSet {
+		if _, exists := addresses[v.Address]; exists {
+			return fmt.Errorf("%w: duplicate initial validator address %s", errInvalidPolyBFTConfig, v.Address)
+		}
+
+		addresses[v.Address] = struct{}{}
+
+		blsKey, err := v.UnmarshalBLSPublicKey()
+		if err != nil {
+			return fmt.Errorf("%w: invalid BLS key of initial validator %s: %v", errInvalidPolyBFTConfig, v.Address, err)
+		}
+
+		// keys are compared in their canonical (marshaled) form
+		rawBlsKey := string(blsKey.Marshal())
+		if other, exists := blsKeys[rawBlsKey]; exists {
+			return fmt.Errorf("%w: initial validators %s and %s have the same BLS key",
+				errInvalidPolyBFTConfig, other, v.Address)
+		}
+
+		blsKeys[rawBlsKey] = v.Address
+	}
+
+	return nil
+}
+
 // BridgeConfig is the rootchain configuration, needed for bridging
 type BridgeConfig struct {
 	StateSenderAddr                   types.Address `json:"stateSenderAddress"`
@@ -107,6 +218,82 @@ type BridgeConfig struct {
 
 	JSONRPCEndpoint         string                   `json:"jsonRPCEndpoint"`
 	EventTrackerStartBlocks map[types.Address]uint64 `json:"eventTrackerStartBlocks"`
+
+	// JSONRPCTimeout is the maximum duration of a single rootchain JSON-RPC call (zero means no timeout)
+	JSONRPCTimeout common.Duration `json:"jsonRPCTimeout,omitempty"`
+	// JSONRPCRetries is the number of times a failed rootchain JSON-RPC call is retried
+	JSONRPCRetries uint64 `json:"jsonRPCRetries,omitempty"`
+	// CommitmentSignatureScheme is the signature scheme used for signing commitments (BLS by default)
+	CommitmentSignatureScheme string `json:"commitmentSignatureScheme,omitempty"`
+	// CommitmentGapPolicy defines how stored commitments being behind the on-chain committed index on startup
+	// (e.g. after a db restore) are handled: "strict" refuses to start, "recover" fetches the missing commitments
+	// from the contract (the gap is only logged by default)
+	CommitmentGapPolicy string `json:"commitmentGapPolicy,omitempty"`
+	// MaxVoteEpochLead is the maximum number of epochs a received commitment vote can be ahead
+	// of the current epoch, votes for later epochs are rejected (zero means that the default of 1 is used)
+	MaxVoteEpochLead uint64 `json:"maxVoteEpochLead,omitempty"`
+	// ProofPruningInterval is the number of epochs between prunings of the stored proofs of executed state syncs,
+	// which are rebuilt from the retained state sync events if requested again (zero disables pruning)
+	ProofPruningInterval uint64 `json:"proofPruningInterval,omitempty"`
+	// SignatureAggregationWorkers is the number of workers aggregating commitment signatures in parallel batches
+	// (signatures are aggregated serially if it is not above 1)
+	SignatureAggregationWorkers uint64 `json:"signatureAggregationWorkers,omitempty"`
+	// ForceSprintCommitments indicates whether a commitment build is attempted at the end of each sprint
+	ForceSprintCommitments bool `json:"forceSprintCommitments,omitempty"`
+	// SprintAlignedCommitments indicates whether commitments are built only at the end of each sprint
+	// (from at least min commitment size state syncs), rather than on arrival of new state sync events
+	SprintAlignedCommitments bool `json:"sprintAlignedCommitments,omitempty"`
+	// SkipBridgeDataOnError indicates whether a block is still built (without bridge state transactions)
+	// if bridge data can not be resolved, instead of refusing to produce a block
+	SkipBridgeDataOnError bool `json:"skipBridgeDataOnError,omitempty"`
+	// EventsBatchSize is the number of state sync events saved at once while catching up with the rootchain
+	// (zero means that events are saved one by one)
+	EventsBatchSize uint64 `json:"eventsBatchSize,omitempty"`
+	// CommitmentResubmissionTimeout is the number of blocks to wait for the registration of a submitted commitment
+	// to be confirmed, before submitting it again (zero disables resubmission)
+	CommitmentResubmissionTimeout uint64 `json:"commitmentResubmissionTimeout,omitempty"`
+	// AdaptiveCommitmentSize indicates whether commitment size is shrunk when commitment transactions
+	// use a large portion of the block gas limit, and grown back (up to max commitment size) when they are cheap
+	AdaptiveCommitmentSize bool `json:"adaptiveCommitmentSize,omitempty"`
+	// ProofFinalityDepth is the number of blocks built on top of a block with a commitment,
+	// before proofs of the committed state syncs are built (zero means that proofs are built immediately)
+	ProofFinalityDepth uint64 `json:"proofFinalityDepth,omitempty"`
+	// MinCommitmentSize is the minimum number of state sync events in a commitment, which allows waiting
+	// for larger batches of events before committing them (zero means that the default minimum is used)
+	MinCommitmentSize uint64 `json:"minCommitmentSize,omitempty"`
+	// QuorumWaitWarnFraction is the fraction of the epoch duration the largest pending commitment
+	// can await quorum, before a warning is logged (zero disables the warning)
+	QuorumWaitWarnFraction float64 `json:"quorumWaitWarnFraction,omitempty"`
+	// MerkleHashScheme is the hashing scheme of the commitment merkle trees, which must match the one
+	// used by the rootchain contracts (keccak256 by default)
+	MerkleHashScheme string `json:"merkleHashScheme,omitempty"`
+	// EventsDecodeWorkers is the maximum number of state sync event logs decoded concurrently while catching up
+	// with the rootchain in batches (zero means that logs are decoded one by one)
+	EventsDecodeWorkers uint64 `json:"eventsDecodeWorkers,omitempty"`
+}
+
+// proposalTimeout returns the time given to a proposer to build and propagate a block,
+// which is the block time extended by the block time drift
+func (p *PolyBFTConfig) proposalTimeout() time.Duration {
+	return p.BlockTime.Duration + time.Duration(p.BlockTimeDrift)*time.Second
+}
+
+// commitmentQuorumWaitWarnThreshold returns the time the largest pending commitment can await quorum,
+// before a warning is logged, derived from the configured fraction of the epoch duration
+func (p *PolyBFTConfig) commitmentQuorumWaitWarnThreshold() time.Duration {
+	if p.Bridge == nil {
+		return 0
+	}
+
+	epochDuration := time.Duration(p.EpochSize) * p.BlockTime.Duration
+
+	return time.Duration(p.Bridge.QuorumWaitWarnFraction * float64(epochDuration))
+}
+
+// isSprintAligned returns true if epoch size is a multiple of sprint size,
+// meaning that the last sprint of each epoch ends together with the epoch
+func (p *PolyBFTConfig) isSprintAligned() bool {
+	return p.SprintSize == 0 || p.EpochSize%p.SprintSize == 0
 }
 
 func (p *PolyBFTConfig) IsBridgeEnabled() bool {
@@ -177,6 +364,19 @@ type RewardsConfig struct {
 
 	// WalletAmount is the amount of tokens in reward wallet
 	WalletAmount *big.Int
+
+	// MintCap is the maximum amount of native tokens which can be minted to reward wallet at once
+	// (at genesis and at the end of each epoch) in case native token is mintable (nil means no cap)
+	MintCap *big.Int
+
+	// MintActivationBlock is the block from which reward wallet of a native reward token is topped up
+	// with newly minted tokens at the end of each epoch (if native token is mintable). Activation at block 0
+	// funds the wallet at genesis as well, and requires it to be pre-funded if native token is not mintable.
+	// Nil disables minting, so that reward wallet is funded only through premine.
+	MintActivationBlock *uint64
+
+	// UptimeRewardCurve maps validators uptime to their rewards (linear curve is used if not set)
+	UptimeRewardCurve *UptimeRewardCurveConfig
 }
 
 func (r *RewardsConfig) MarshalJSON() ([]byte, error) {
@@ -184,6 +384,13 @@ func (r *RewardsConfig) MarshalJSON() ([]byte, error) {
 		TokenAddress:  r.TokenAddress,
 		WalletAddress: r.WalletAddress,
 		WalletAmount:  types.EncodeBigInt(r.WalletAmount),
+		UptimeCurve:   r.UptimeRewardCurve,
+
+		MintActivationBlock: r.MintActivationBlock,
+	}
+
+	if r.MintCap != nil {
+		raw.MintCap = types.EncodeBigInt(r.MintCap)
 	}
 
 	return json.Marshal(raw)
@@ -201,12 +408,21 @@ func (r *RewardsConfig) UnmarshalJSON(data []byte) error {
 
 	r.TokenAddress = raw.TokenAddress
 	r.WalletAddress = raw.WalletAddress
+	r.UptimeRewardCurve = raw.UptimeCurve
+	r.MintActivationBlock = raw.MintActivationBlock
 
 	r.WalletAmount, err = types.ParseUint256orHex(raw.WalletAmount)
 	if err != nil {
 		return err
 	}
 
+	if raw.MintCap != nil {
+		r.MintCap, err = types.ParseUint256orHex(raw.MintCap)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -214,4 +430,9 @@ type rewardsConfigRaw struct {
 	TokenAddress  types.Address `json:"rewardTokenAddress"`
 	WalletAddress types.Address `json:"rewardWalletAddress"`
 	WalletAmount  *string       `json:"rewardWalletAmount"`
+	MintCap       *string       `json:"rewardMintCap,omitempty"`
+
+	MintActivationBlock *uint64 `json:"rewardMintActivationBlock,omitempty"`
+
+	UptimeCurve *UptimeRewardCurveConfig `json:"uptimeRewardCurve,omitempty"`
 }
@@ -1,16 +1,23 @@
 package polybft
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"math/big"
 	"testing"
 	"time"
 
+	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/chain"
 	"github.com/0xPolygon/polygon-edge/consensus"
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
 	bls "github.com/0xPolygon/polygon-edge/consensus/polybft/signer"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/wallet"
+	"github.com/0xPolygon/polygon-edge/contracts"
 	"github.com/0xPolygon/polygon-edge/helper/progress"
+	"github.com/0xPolygon/polygon-edge/state"
 	"github.com/0xPolygon/polygon-edge/txpool"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
@@ -229,6 +236,89 @@ func TestPolybft_Close(t *testing.T) {
 	syncer.AssertExpectations(t)
 }
 
+type testReorgSubscription struct {
+	reorgCh chan *blockchain.ReorgEvent
+	closed  chan struct{}
+}
+
+func (s *testReorgSubscription) GetReorgCh() <-chan *blockchain.ReorgEvent {
+	return s.reorgCh
+}
+
+func (s *testReorgSubscription) Close() {
+	close(s.closed)
+}
+
+func TestPolybft_TrackReorgs(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+	stateSyncManager := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	polybft := &Polybft{
+		closeCh: make(chan struct{}),
+		runtime: &consensusRuntime{
+			logger:           hclog.NewNullLogger(),
+			stateSyncManager: stateSyncManager,
+		},
+	}
+
+	reorgSub := &testReorgSubscription{
+		reorgCh: make(chan *blockchain.ReorgEvent),
+		closed:  make(chan struct{}),
+	}
+
+	go polybft.trackReorgs(reorgSub)
+
+	reorgSub.reorgCh <- &blockchain.ReorgEvent{
+		CommonAncestor: &types.Header{Number: 1},
+		NewCanonical:   []types.Hash{types.StringToHash("0x2")},
+	}
+
+	require.Eventually(t, func() bool {
+		stateSyncManager.lock.RLock()
+		defer stateSyncManager.lock.RUnlock()
+
+		return stateSyncManager.reorgInProgress
+	}, time.Second, 10*time.Millisecond)
+
+	close(polybft.closeCh)
+
+	select {
+	case <-reorgSub.closed:
+	case <-time.After(time.Second):
+		require.Fail(t, "reorg subscription is not closed")
+	}
+}
+
+func TestPolybft_RoundTimeoutExtension(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		enabled  bool
+		expected time.Duration
+	}{
+		{name: "disabled", enabled: false, expected: 0},
+		{name: "enabled", enabled: true, expected: 3 * time.Second},
+	}
+
+	for _, c := range cases {
+		c := c
+
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			polybft := &Polybft{
+				consensusConfig: &PolyBFTConfig{ExtendRoundTimeout: c.enabled},
+				runtime:         &consensusRuntime{fsm: &fsm{proposalTimeout: 3 * time.Second}},
+			}
+
+			require.Equal(t, c.expected, polybft.roundTimeoutExtension())
+		})
+	}
+}
+
 func TestPolybft_GetSyncProgression(t *testing.T) {
 	t.Parallel()
 
@@ -336,7 +426,9 @@ func Test_GenesisPostHookFactory(t *testing.T) {
 			initHandler := GenesisPostHookFactory(chainConfig, ConsensusName)
 			require.NotNil(t, initHandler)
 
-			transition := newTestTransition(t, nil)
+			transition := newTestTransition(t, map[types.Address]*chain.GenesisAccount{
+				contracts.ValidatorSetContract: {Code: contractsapi.ValidatorSet.DeployedBytecode},
+			})
 			if tc.expectedErr == nil {
 				require.NoError(t, initHandler(transition))
 			} else {
@@ -345,3 +437,483 @@ func Test_GenesisPostHookFactory(t *testing.T) {
 		})
 	}
 }
+
+func Test_MintRewardTokensToWallet_NativeToken(t *testing.T) {
+	t.Parallel()
+
+	walletAddr := types.StringToAddress("0xDEADBEEF")
+	genesisActivation, laterActivation := uint64(0), uint64(100)
+
+	cases := []struct {
+		name           string
+		isMintable     bool
+		activation     *uint64
+		premine        *big.Int
+		walletAmount   *big.Int
+		mintCap        *big.Int
+		expectedMinted *big.Int
+		expectedErr    error
+	}{
+		{
+			name:           "non-mintable, pre-funded wallet",
+			activation:     &genesisActivation,
+			premine:        ethgo.Ether(1000),
+			walletAmount:   ethgo.Ether(1000),
+			expectedMinted: big.NewInt(0),
+		},
+		{
+			name:         "non-mintable, insufficient wallet",
+			activation:   &genesisActivation,
+			premine:      ethgo.Ether(10),
+			walletAmount: ethgo.Ether(1000),
+			expectedErr:  errInsufficientRewardWallet,
+		},
+		{
+			name:         "non-mintable, empty wallet",
+			activation:   &genesisActivation,
+			walletAmount: ethgo.Ether(1),
+			expectedErr:  errInsufficientRewardWallet,
+		},
+		{
+			name:           "non-mintable, minting not activated",
+			premine:        ethgo.Ether(10),
+			walletAmount:   ethgo.Ether(1000),
+			expectedMinted: big.NewInt(0),
+		},
+		{
+			name:           "mintable, wallet is topped up",
+			isMintable:     true,
+			activation:     &genesisActivation,
+			premine:        ethgo.Ether(10),
+			walletAmount:   ethgo.Ether(1000),
+			expectedMinted: ethgo.Ether(990),
+		},
+		{
+			name:           "mintable, minted amount within cap",
+			isMintable:     true,
+			activation:     &genesisActivation,
+			walletAmount:   ethgo.Ether(1000),
+			mintCap:        ethgo.Ether(1000),
+			expectedMinted: ethgo.Ether(1000),
+		},
+		{
+			name:           "mintable, minted amount is capped",
+			isMintable:     true,
+			activation:     &genesisActivation,
+			walletAmount:   ethgo.Ether(1000),
+			mintCap:        ethgo.Ether(999),
+			expectedMinted: ethgo.Ether(999),
+		},
+		{
+			name:           "mintable, minting not activated",
+			isMintable:     true,
+			premine:        ethgo.Ether(10),
+			walletAmount:   ethgo.Ether(1000),
+			expectedMinted: big.NewInt(0),
+		},
+		{
+			name:           "mintable, minting activated after genesis",
+			isMintable:     true,
+			activation:     &laterActivation,
+			premine:        ethgo.Ether(10),
+			walletAmount:   ethgo.Ether(1000),
+			expectedMinted: big.NewInt(0),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			alloc := map[types.Address]*chain.GenesisAccount{}
+			if tc.premine != nil {
+				alloc[walletAddr] = &chain.GenesisAccount{Balance: tc.premine}
+			}
+
+			config := PolyBFTConfig{
+				NativeTokenConfig: &TokenConfig{Name: "Test", Symbol: "TEST", Decimals: 18, IsMintable: tc.isMintable},
+				RewardConfig: &RewardsConfig{
+					TokenAddress:        contracts.NativeERC20TokenContract,
+					WalletAddress:       walletAddr,
+					WalletAmount:        tc.walletAmount,
+					MintCap:             tc.mintCap,
+					MintActivationBlock: tc.activation,
+				},
+			}
+
+			transition := newTestTransition(t, alloc)
+
+			minted, err := mintRewardTokensToWallet(config, transition)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedMinted, minted)
+
+			expectedBalance := new(big.Int).Set(tc.expectedMinted)
+			if tc.premine != nil {
+				expectedBalance.Add(expectedBalance, tc.premine)
+			}
+
+			require.Equal(t, expectedBalance, transition.GetBalance(walletAddr))
+		})
+	}
+}
+
+func Test_NativeRewardsMintHook(t *testing.T) {
+	t.Parallel()
+
+	walletAddr := types.StringToAddress("0xDEADBEEF")
+	initialSupply := ethgo.Ether(5000)
+
+	createConfig := func(isMintable bool, mintCap *big.Int, activation *uint64) PolyBFTConfig {
+		return PolyBFTConfig{
+			NativeTokenConfig: &TokenConfig{Name: "Test", Symbol: "TEST", Decimals: 18, IsMintable: isMintable},
+			RewardConfig: &RewardsConfig{
+				TokenAddress:        contracts.NativeERC20TokenContract,
+				WalletAddress:       walletAddr,
+				WalletAmount:        ethgo.Ether(1000),
+				MintCap:             mintCap,
+				MintActivationBlock: activation,
+			},
+		}
+	}
+
+	// createTransition creates a transition with initialized NativeERC20Mintable contract
+	createTransition := func(t *testing.T, walletBalance *big.Int) *state.Transition {
+		t.Helper()
+
+		transition := newTestTransition(t, map[types.Address]*chain.GenesisAccount{
+			walletAddr:                         {Balance: walletBalance},
+			contracts.NativeERC20TokenContract: {Code: contractsapi.NativeERC20Mintable.DeployedBytecode},
+		})
+
+		input, err := (&contractsapi.InitializeNativeERC20MintableFn{
+			Predicate_:   contracts.ChildERC20PredicateContract,
+			Owner_:       types.StringToAddress("0x1"),
+			Name_:        "Test",
+			Symbol_:      "TEST",
+			Decimals_:    18,
+			TokenSupply_: initialSupply,
+		}).EncodeAbi()
+		require.NoError(t, err)
+
+		require.NoError(t, callContract(contracts.SystemCaller, contracts.NativeERC20TokenContract, input,
+			"NativeERC20Mintable", transition))
+
+		return transition
+	}
+
+	getTotalSupply := func(t *testing.T, transition *state.Transition) *big.Int {
+		t.Helper()
+
+		result := transition.Call2(contracts.SystemCaller, contracts.NativeERC20TokenContract,
+			contractsapi.NativeERC20Mintable.Abi.Methods["totalSupply"].ID(), big.NewInt(0), contractCallGasLimit)
+		require.NoError(t, result.Err)
+
+		return new(big.Int).SetBytes(result.ReturnValue)
+	}
+
+	distributeRewardsInput, err := (&contractsapi.DistributeRewardForRewardPoolFn{
+		EpochID: big.NewInt(1),
+		Uptime:  []*contractsapi.Uptime{},
+	}).EncodeAbi()
+	require.NoError(t, err)
+
+	distributeRewardsTx := createStateTransactionWithData(contracts.RewardPoolContract, distributeRewardsInput)
+	activeFromGenesis := uint64(0)
+
+	t.Run("non-mintable native token", func(t *testing.T) {
+		t.Parallel()
+
+		require.Nil(t, nativeRewardsMintHook(createConfig(false, nil, &activeFromGenesis), hclog.NewNullLogger()))
+	})
+
+	t.Run("minting not activated", func(t *testing.T) {
+		t.Parallel()
+
+		require.Nil(t, nativeRewardsMintHook(createConfig(true, nil, nil), hclog.NewNullLogger()))
+	})
+
+	t.Run("activation block not reached", func(t *testing.T) {
+		t.Parallel()
+
+		activation := uint64(10)
+
+		hook := nativeRewardsMintHook(createConfig(true, nil, &activation), hclog.NewNullLogger())
+		require.NotNil(t, hook)
+
+		transition := createTransition(t, ethgo.Ether(900))
+
+		hook(transition, distributeRewardsTx)
+		require.Equal(t, ethgo.Ether(900), transition.GetBalance(walletAddr))
+		require.Equal(t, initialSupply, getTotalSupply(t, transition))
+	})
+
+	t.Run("wallet is topped up on each epoch", func(t *testing.T) {
+		t.Parallel()
+
+		hook := nativeRewardsMintHook(createConfig(true, nil, &activeFromGenesis), hclog.NewNullLogger())
+		require.NotNil(t, hook)
+
+		transition := createTransition(t, ethgo.Ether(1000))
+
+		for epoch := 0; epoch < 3; epoch++ {
+			// rewards distributed in the previous epoch
+			require.NoError(t, transition.Txn().SubBalance(walletAddr, ethgo.Ether(100)))
+
+			hook(transition, distributeRewardsTx)
+			require.Equal(t, ethgo.Ether(1000), transition.GetBalance(walletAddr))
+		}
+
+		// total supply grows by the minted amount
+		require.Equal(t, new(big.Int).Add(initialSupply, ethgo.Ether(300)), getTotalSupply(t, transition))
+
+		// other transactions do not mint anything
+		require.NoError(t, transition.Txn().SubBalance(walletAddr, ethgo.Ether(100)))
+
+		hook(transition, createStateTransactionWithData(contracts.ValidatorSetContract, distributeRewardsInput))
+		hook(transition, &types.Transaction{To: &contracts.RewardPoolContract, Input: distributeRewardsInput})
+		require.Equal(t, ethgo.Ether(900), transition.GetBalance(walletAddr))
+	})
+
+	t.Run("minted amount is capped", func(t *testing.T) {
+		t.Parallel()
+
+		var logs bytes.Buffer
+
+		hook := nativeRewardsMintHook(createConfig(true, ethgo.Ether(50), &activeFromGenesis),
+			hclog.New(&hclog.LoggerOptions{Output: &logs}))
+		require.NotNil(t, hook)
+
+		transition := createTransition(t, ethgo.Ether(800))
+
+		hook(transition, distributeRewardsTx)
+		require.Equal(t, ethgo.Ether(850), transition.GetBalance(walletAddr))
+		require.Equal(t, new(big.Int).Add(initialSupply, ethgo.Ether(50)), getTotalSupply(t, transition))
+		require.Contains(t, logs.String(), "reward tokens mint cap exceeded")
+	})
+}
+
+func TestRewardsConfig_MintJSON(t *testing.T) {
+	t.Parallel()
+
+	activation := uint64(1000)
+	config := &RewardsConfig{
+		WalletAmount:        big.NewInt(1000),
+		MintCap:             big.NewInt(50),
+		MintActivationBlock: &activation,
+	}
+
+	raw, err := json.Marshal(config)
+	require.NoError(t, err)
+
+	var decoded RewardsConfig
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	require.Equal(t, config.MintCap, decoded.MintCap)
+	require.Equal(t, config.MintActivationBlock, decoded.MintActivationBlock)
+
+	// minting is disabled unless activation block is configured
+	raw, err = json.Marshal(&RewardsConfig{WalletAmount: big.NewInt(1000)})
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "rewardMintActivationBlock")
+
+	decoded = RewardsConfig{}
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	require.Nil(t, decoded.MintActivationBlock)
+}
+
+func TestGetPolyBFTConfig_MaxCommitmentSize(t *testing.T) {
+	t.Parallel()
+
+	getConfig := func(engineConfig map[string]interface{}) (PolyBFTConfig, error) {
+		return GetPolyBFTConfig(&chain.Chain{
+			Params: &chain.Params{Engine: map[string]interface{}{ConsensusName: engineConfig}},
+		})
+	}
+
+	// default value is used when max commitment size is not provided
+	config, err := getConfig(map[string]interface{}{"epochSize": 10})
+	require.NoError(t, err)
+	require.Equal(t, uint64(maxCommitmentSize), config.MaxCommitmentSize)
+
+	config, err = getConfig(map[string]interface{}{"maxCommitmentSize": 25})
+	require.NoError(t, err)
+	require.Equal(t, uint64(25), config.MaxCommitmentSize)
+
+	_, err = getConfig(map[string]interface{}{"maxCommitmentSize": 0})
+	require.ErrorIs(t, err, errInvalidPolyBFTConfig)
+}
+
+func TestGetPolyBFTConfig_MissingEngineSection(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		params *chain.Params
+	}{
+		{name: "no params", params: nil},
+		{name: "no engines", params: &chain.Params{}},
+		{name: "other engine only", params: &chain.Params{Engine: map[string]interface{}{"ibft": map[string]interface{}{}}}},
+		{name: "nil section", params: &chain.Params{Engine: map[string]interface{}{ConsensusName: nil}}},
+		{name: "empty section", params: &chain.Params{Engine: map[string]interface{}{ConsensusName: map[string]interface{}{}}}},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := GetPolyBFTConfig(&chain.Chain{Params: c.params})
+			require.ErrorIs(t, err, errPolyBFTConfigMissing)
+			require.ErrorContains(t, err, "polybft engine config missing")
+		})
+	}
+}
+
+func TestPolyBFTConfig_Validate_InitialValidators(t *testing.T) {
+	t.Parallel()
+
+	createConfig := func(t *testing.T) *PolyBFTConfig {
+		t.Helper()
+
+		return &PolyBFTConfig{
+			InitialValidatorSet: validator.NewTestValidators(t, 3).GetParamValidators(),
+			MaxCommitmentSize:   maxCommitmentSize,
+		}
+	}
+
+	t.Run("valid validators", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, createConfig(t).Validate())
+	})
+
+	t.Run("malformed BLS key", func(t *testing.T) {
+		t.Parallel()
+
+		config := createConfig(t)
+		config.InitialValidatorSet[1].BlsKey = "0x1234"
+
+		err := config.Validate()
+		require.ErrorIs(t, err, errInvalidPolyBFTConfig)
+		require.ErrorContains(t, err, "invalid BLS key of initial validator")
+	})
+
+	t.Run("duplicate address", func(t *testing.T) {
+		t.Parallel()
+
+		config := createConfig(t)
+		config.InitialValidatorSet[2].Address = config.InitialValidatorSet[0].Address
+
+		err := config.Validate()
+		require.ErrorIs(t, err, errInvalidPolyBFTConfig)
+		require.ErrorContains(t, err, "duplicate initial validator address")
+	})
+
+	t.Run("duplicate BLS key", func(t *testing.T) {
+		t.Parallel()
+
+		config := createConfig(t)
+		config.InitialValidatorSet[2].BlsKey = config.InitialValidatorSet[0].BlsKey
+
+		err := config.Validate()
+		require.ErrorIs(t, err, errInvalidPolyBFTConfig)
+		require.ErrorContains(t, err, "have the same BLS key")
+	})
+
+	t.Run("unsupported commitment gap policy", func(t *testing.T) {
+		t.Parallel()
+
+		config := createConfig(t)
+		config.Bridge = &BridgeConfig{CommitmentGapPolicy: "ignore"}
+
+		err := config.Validate()
+		require.ErrorIs(t, err, errInvalidPolyBFTConfig)
+		require.ErrorContains(t, err, "unsupported commitment gap policy")
+	})
+
+	t.Run("min commitment size above max", func(t *testing.T) {
+		t.Parallel()
+
+		config := createConfig(t)
+		config.Bridge = &BridgeConfig{MinCommitmentSize: maxCommitmentSize + 1}
+
+		err := config.Validate()
+		require.ErrorIs(t, err, errInvalidPolyBFTConfig)
+		require.ErrorContains(t, err, "must not exceed max commitment size")
+	})
+}
+
+func TestPolyBFTConfig_isSprintAligned(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		epochSize  uint64
+		sprintSize uint64
+		aligned    bool
+	}{
+		{name: "epoch size is a multiple of sprint size", epochSize: 10, sprintSize: 5, aligned: true},
+		{name: "sprint size equal to epoch size", epochSize: 10, sprintSize: 10, aligned: true},
+		{name: "ragged sprint", epochSize: 10, sprintSize: 4},
+		{name: "sprint size above epoch size", epochSize: 4, sprintSize: 5},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := &PolyBFTConfig{
+				EpochSize:         c.epochSize,
+				SprintSize:        c.sprintSize,
+				MaxCommitmentSize: maxCommitmentSize,
+			}
+
+			require.Equal(t, c.aligned, config.isSprintAligned())
+			// misaligned sprint is only warned about, so that the existing configurations remain valid
+			require.NoError(t, config.Validate())
+		})
+	}
+}
+
+func Test_VerifyInitialValidatorsStake(t *testing.T) {
+	t.Parallel()
+
+	validators := validator.NewTestValidatorsWithAliases(t, []string{"A", "B", "C"}, []uint64{10, 20, 30})
+
+	initialValidators := make([]*validator.GenesisValidator, 0, validators.ToValidatorSet().Len())
+	for _, val := range validators.GetPublicIdentities() {
+		initialValidators = append(initialValidators, &validator.GenesisValidator{
+			Address: val.Address,
+			Stake:   val.VotingPower,
+		})
+	}
+
+	polyBFTConfig := PolyBFTConfig{
+		InitialValidatorSet: initialValidators,
+		EpochSize:           10,
+		Bridge: &BridgeConfig{
+			CustomSupernetManagerAddr: types.StringToAddress("0x12312451"),
+		},
+	}
+
+	transition := newTestTransition(t, map[types.Address]*chain.GenesisAccount{
+		contracts.ValidatorSetContract: {Code: contractsapi.ValidatorSet.DeployedBytecode},
+	})
+
+	require.NoError(t, initValidatorSet(polyBFTConfig, transition))
+
+	// stakes read from the contract match the genesis ones
+	require.NoError(t, verifyInitialValidatorsStake(polyBFTConfig, transition))
+
+	// stake which was not written to the contract is detected
+	initialValidators[1].Stake = big.NewInt(21)
+
+	require.ErrorIs(t, verifyInitialValidatorsStake(polyBFTConfig, transition), errGenesisStakeMismatch)
+}
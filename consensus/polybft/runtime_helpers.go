@@ -1,6 +1,8 @@
 package polybft
 
 import (
+	"fmt"
+
 	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/types"
 )
@@ -11,6 +13,35 @@ func isEndOfPeriod(blockNumber, periodSize uint64) bool {
 	return blockNumber%periodSize == 0
 }
 
+// getEndEpochBlockNumber returns number of the last block of the given epoch,
+// assuming that epochs are of fixed size (epoch 0 ends with the genesis block)
+func getEndEpochBlockNumber(epoch, epochSize uint64) uint64 {
+	return epoch * epochSize
+}
+
+// isGenesisEpoch checks if the given epoch is epoch 0, whose only block is the genesis block.
+// Regular epochs start with epoch 1, which begins with block 1.
+func isGenesisEpoch(epoch uint64) bool {
+	return epoch == 0
+}
+
+// firstEpochRange returns numbers of the first and the last block of epoch 1,
+// which is the first epoch after the genesis epoch
+func firstEpochRange(epochSize uint64) (first, last uint64) {
+	return 1, getEndEpochBlockNumber(1, epochSize)
+}
+
+// getEpochNumberForBlock returns number of the epoch which contains the given block,
+// assuming that epochs are of fixed size (genesis block is the only block of epoch 0,
+// and epoch ending block is the last block of its epoch)
+func getEpochNumberForBlock(blockNumber, epochSize uint64) uint64 {
+	if isEndOfPeriod(blockNumber, epochSize) {
+		return blockNumber / epochSize
+	}
+
+	return blockNumber/epochSize + 1
+}
+
 // getBlockData returns block header and extra
 func getBlockData(blockNumber uint64, blockchainBackend blockchainBackend) (*types.Header, *Extra, error) {
 	blockHeader, found := blockchainBackend.GetHeaderByNumber(blockNumber)
@@ -26,6 +57,22 @@ func getBlockData(blockNumber uint64, blockchainBackend blockchainBackend) (*typ
 	return blockHeader, blockExtra, nil
 }
 
+// getUptimeBlockData returns block header and extra, failing with an error which tells apart
+// a gap in the chain (blockchain.ErrBlockNotFound) from a storage failure
+func getUptimeBlockData(blockNumber uint64, blockchainBackend blockchainBackend) (*types.Header, *Extra, error) {
+	blockHeader, err := blockchainBackend.HeaderByNumber(blockNumber)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get block %d for uptime calculation: %w", blockNumber, err)
+	}
+
+	blockExtra, err := GetIbftExtra(blockHeader.ExtraData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return blockHeader, blockExtra, nil
+}
+
 // isEpochEndingBlock checks if given block is an epoch ending block
 func isEpochEndingBlock(blockNumber uint64, extra *Extra, blockchain blockchainBackend) (bool, error) {
 	if !extra.Validators.IsEmpty() {
@@ -1,6 +1,7 @@
 package polybft
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/blockchain"
@@ -11,6 +12,42 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestHelpers_EpochBoundaries(t *testing.T) {
+	t.Parallel()
+
+	for _, epochSize := range []uint64{1, 5, 10} {
+		epochSize := epochSize
+
+		cases := []struct {
+			blockNumber uint64
+			epoch       uint64
+			isEpochEnd  bool
+		}{
+			{blockNumber: 0, epoch: 0, isEpochEnd: true},
+			{blockNumber: 1, epoch: 1, isEpochEnd: epochSize == 1},
+			{blockNumber: epochSize, epoch: 1, isEpochEnd: true},
+			{blockNumber: epochSize + 1, epoch: 2, isEpochEnd: epochSize == 1},
+			{blockNumber: 2 * epochSize, epoch: 2, isEpochEnd: true},
+		}
+
+		t.Run(fmt.Sprintf("epoch size %d", epochSize), func(t *testing.T) {
+			t.Parallel()
+
+			first, last := firstEpochRange(epochSize)
+			require.Equal(t, uint64(1), first)
+			require.Equal(t, epochSize, last)
+
+			for _, c := range cases {
+				epoch := getEpochNumberForBlock(c.blockNumber, epochSize)
+				require.Equal(t, c.epoch, epoch, "block %d", c.blockNumber)
+				require.Equal(t, c.isEpochEnd, isEndOfPeriod(c.blockNumber, epochSize), "block %d", c.blockNumber)
+				require.Equal(t, c.blockNumber == 0, isGenesisEpoch(epoch), "block %d", c.blockNumber)
+				require.Equal(t, c.blockNumber >= first && c.blockNumber <= last, epoch == 1, "block %d", c.blockNumber)
+			}
+		})
+	}
+}
+
 func TestHelpers_isEpochEndingBlock_DeltaNotEmpty(t *testing.T) {
 	t.Parallel()
 
@@ -86,3 +123,11 @@ func TestHelpers_isEpochEndingBlock_EpochsAreTheSame(t *testing.T) {
 	require.NoError(t, err)
 	require.False(t, isEndOfEpoch)
 }
+
+func TestHelpers_getEndEpochBlockNumber(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, uint64(0), getEndEpochBlockNumber(0, 10))
+	require.Equal(t, uint64(10), getEndEpochBlockNumber(1, 10))
+	require.Equal(t, uint64(50), getEndEpochBlockNumber(5, 10))
+}
@@ -3,6 +3,8 @@ package polybft
 import (
 	"fmt"
 
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
+	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/hashicorp/go-hclog"
 	bolt "go.etcd.io/bbolt"
 
@@ -33,22 +35,11 @@ type MessageSignature struct {
 	Signature []byte
 }
 
-// TransportMessage represents the payload which is gossiped across the network
-type TransportMessage struct {
-	// Hash is encoded data
-	Hash []byte
-	// Message signature
-	Signature []byte
-	// From is the address of the message signer
-	From string
-	// Number of epoch
-	EpochNumber uint64
-}
-
 // State represents a persistence layer which persists consensus data off-chain
 type State struct {
-	db    *bolt.DB
-	close chan struct{}
+	db     *bolt.DB
+	close  chan struct{}
+	logger hclog.Logger
 
 	StateSyncStore        *StateSyncStore
 	CheckpointStore       *CheckpointStore
@@ -67,6 +58,7 @@ func newState(path string, logger hclog.Logger, closeCh chan struct{}) (*State,
 	s := &State{
 		db:                    db,
 		close:                 closeCh,
+		logger:                logger,
 		StateSyncStore:        &StateSyncStore{db: db},
 		CheckpointStore:       &CheckpointStore{db: db},
 		EpochStore:            &EpochStore{db: db},
@@ -81,6 +73,87 @@ func newState(path string, logger hclog.Logger, closeCh chan struct{}) (*State,
 	return s, nil
 }
 
+// GetStateSyncEvent returns the stored state sync event with the given id,
+// or stateSyncEventNotFoundError if it is not stored
+func (s *State) GetStateSyncEvent(stateSyncID uint64) (*contractsapi.StateSyncedEvent, error) {
+	return s.StateSyncStore.getStateSyncEvent(stateSyncID)
+}
+
+// InsertValidatedStateSyncEvent inserts a state sync event emitted in the given rootchain block,
+// which was missed by the event tracker (e.g. due to a bug), without re-scanning the rootchain.
+// The event is accepted only if it has both sender and receiver set and it fills a gap of stored state sync events
+// (in the id order), so that it never overwrites a different stored event.
+// It returns true if the event was inserted (false if the identical event is already stored).
+func (s *State) InsertValidatedStateSyncEvent(event *contractsapi.StateSyncedEvent,
+	rootchainBlock uint64) (bool, error) {
+	if event == nil || event.ID == nil {
+		return false, errInvalidStateSyncEvent
+	}
+
+	if event.Sender == types.ZeroAddress || event.Receiver == types.ZeroAddress {
+		return false, fmt.Errorf("%w: state sync event %d from rootchain block %d has no sender or receiver",
+			errInvalidStateSyncEvent, event.ID.Uint64(), rootchainBlock)
+	}
+
+	isInserted, err := s.StateSyncStore.insertValidatedStateSyncEvent(event)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert state sync event %d from rootchain block %d: %w",
+			event.ID.Uint64(), rootchainBlock, err)
+	}
+
+	return isInserted, nil
+}
+
+// FindCommitmentGaps checks that every state sync id from 0 up to the end id of the last stored commitment
+// is covered by exactly one stored commitment, and returns the ranges which are either not covered
+// or covered more than once (none if there is no stored commitment)
+func (s *State) FindCommitmentGaps() ([]*StateSyncRange, error) {
+	lastCommitment, err := s.StateSyncStore.getLastCommitmentMessage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the last commitment: %w", err)
+	}
+
+	if lastCommitment == nil {
+		return nil, nil
+	}
+
+	return s.StateSyncStore.findCommitmentGaps(0, lastCommitment.Message.EndID.Uint64())
+}
+
+// GetCommitmentSigners returns addresses of the validators whose signatures are aggregated
+// in the stored commitment, which contains the given state sync.
+// An error is returned if the validator set of the epoch in which the commitment was submitted
+// is not known or not stored (anymore), since signers can not be resolved without it.
+func (s *State) GetCommitmentSigners(fromIndex uint64) ([]types.Address, error) {
+	commitment, err := s.StateSyncStore.getCommitmentForStateSync(fromIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commitment for state sync %d: %w", fromIndex, err)
+	}
+
+	if commitment.Epoch == 0 {
+		return nil, fmt.Errorf("%w: epoch of the commitment for state sync %d is unknown",
+			errCommitmentValidatorsNotStored, fromIndex)
+	}
+
+	// validator set of an epoch is stored as a snapshot of the previous epoch ending block
+	snapshot, err := s.EpochStore.getValidatorSnapshot(commitment.Epoch - 1)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot == nil {
+		return nil, fmt.Errorf("%w: commitment for state sync %d was submitted in epoch %d",
+			errCommitmentValidatorsNotStored, fromIndex, commitment.Epoch)
+	}
+
+	signers, err := snapshot.Snapshot.GetFilteredValidators(commitment.AggSignature.Bitmap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve signers of commitment for state sync %d: %w", fromIndex, err)
+	}
+
+	return signers.GetAddresses(), nil
+}
+
 // initStorages initializes data storages
 func (s *State) initStorages() error {
 	// init the buckets
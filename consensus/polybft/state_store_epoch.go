@@ -1,9 +1,12 @@
 package polybft
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
 	"github.com/0xPolygon/polygon-edge/helper/common"
 	bolt "go.etcd.io/bbolt"
 )
@@ -24,6 +27,12 @@ var (
 
 	// bucket to store validator snapshots
 	validatorSnapshotsBucket = []byte("validatorSnapshots")
+
+	// key of the validator set hash in the epoch bucket
+	epochValidatorsHashKey = []byte("validatorsHash")
+
+	// errEpochConflict represents "epoch is already inserted with a different validator set" error message
+	errEpochConflict = errors.New("epoch is already inserted with a different validator set")
 )
 
 /*
@@ -31,6 +40,7 @@ Bolt DB schema:
 
 epochs/
 |--> epochNumber
+	|--> validatorsHash -> validator set hash
 	|--> hash -> []*MessageSignatures (json marshalled)
 
 epochs/
@@ -105,8 +115,20 @@ func (s *EpochStore) getLastSnapshot() (*validatorSnapshot, error) {
 	return snapshot, err
 }
 
-// insertEpoch inserts a new epoch to db with its meta data
-func (s *EpochStore) insertEpoch(epoch uint64) error {
+// insertEpoch inserts a new epoch to db with its meta data. Inserting an already inserted epoch succeeds,
+// unless the given validator set differs from the one the epoch was inserted with (nil validator set is not checked).
+func (s *EpochStore) insertEpoch(epoch uint64, validators validator.AccountSet) error {
+	var validatorsHash []byte
+
+	if validators != nil {
+		hash, err := validators.Hash()
+		if err != nil {
+			return err
+		}
+
+		validatorsHash = hash.Bytes()
+	}
+
 	return s.db.Update(func(tx *bolt.Tx) error {
 		epochBucket, err := tx.Bucket(epochsBucket).CreateBucketIfNotExists(common.EncodeUint64ToBytes(epoch))
 		if err != nil {
@@ -117,7 +139,19 @@ func (s *EpochStore) insertEpoch(epoch uint64) error {
 			return err
 		}
 
-		return err
+		if validatorsHash == nil {
+			return nil
+		}
+
+		if existingHash := epochBucket.Get(epochValidatorsHashKey); existingHash != nil {
+			if !bytes.Equal(existingHash, validatorsHash) {
+				return fmt.Errorf("%w: epoch %d", errEpochConflict, epoch)
+			}
+
+			return nil
+		}
+
+		return epochBucket.Put(epochValidatorsHashKey, validatorsHash)
 	})
 }
 
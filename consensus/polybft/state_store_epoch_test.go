@@ -95,12 +95,52 @@ func TestState_cleanValidatorSnapshotsFromDb(t *testing.T) {
 	}
 }
 
+func TestState_insertEpoch_Reinsert(t *testing.T) {
+	t.Parallel()
+
+	const epoch = uint64(3)
+
+	state := newTestState(t)
+	vals := validator.NewTestValidators(t, 5)
+
+	require.NoError(t, state.EpochStore.insertEpoch(epoch, vals.GetPublicIdentities("0", "1", "2", "3")))
+
+	hash := []byte{1, 2}
+	_, err := state.StateSyncStore.insertMessageVote(epoch, hash, &MessageSignature{From: "NODE_1"})
+	require.NoError(t, err)
+
+	// re-inserting the same epoch (e.g. on restart) succeeds, and it keeps the epoch data
+	require.NoError(t, state.EpochStore.insertEpoch(epoch, vals.GetPublicIdentities("0", "1", "2", "3")))
+
+	votes, err := state.StateSyncStore.getMessageVotes(epoch, hash)
+	require.NoError(t, err)
+	require.Len(t, votes, 1)
+}
+
+func TestState_insertEpoch_Conflict(t *testing.T) {
+	t.Parallel()
+
+	const epoch = uint64(3)
+
+	state := newTestState(t)
+	vals := validator.NewTestValidators(t, 5)
+
+	require.NoError(t, state.EpochStore.insertEpoch(epoch, vals.GetPublicIdentities("0", "1", "2", "3")))
+
+	// same epoch number with different validators is a conflict
+	err := state.EpochStore.insertEpoch(epoch, vals.GetPublicIdentities("1", "2", "3", "4"))
+	require.ErrorIs(t, err, errEpochConflict)
+
+	// a different epoch may have different validators
+	require.NoError(t, state.EpochStore.insertEpoch(epoch+1, vals.GetPublicIdentities("1", "2", "3", "4")))
+}
+
 func TestState_InsertVoteConcurrent(t *testing.T) {
 	t.Parallel()
 
 	state := newTestState(t)
 	epoch := uint64(1)
-	assert.NoError(t, state.EpochStore.insertEpoch(epoch))
+	assert.NoError(t, state.EpochStore.insertEpoch(epoch, nil))
 
 	hash := []byte{1, 2}
 
@@ -134,7 +174,7 @@ func TestState_Insert_And_Cleanup(t *testing.T) {
 
 	for i := uint64(1); i <= 500; i++ {
 		epoch := i
-		err := state.EpochStore.insertEpoch(epoch)
+		err := state.EpochStore.insertEpoch(epoch, nil)
 
 		assert.NoError(t, err)
 
@@ -165,7 +205,7 @@ func TestState_Insert_And_Cleanup(t *testing.T) {
 
 	for i := uint64(501); i <= 1000; i++ {
 		epoch := i
-		err := state.EpochStore.insertEpoch(epoch)
+		err := state.EpochStore.insertEpoch(epoch, nil)
 		assert.NoError(t, err)
 
 		_, _ = state.StateSyncStore.insertMessageVote(epoch, hash1, &MessageSignature{
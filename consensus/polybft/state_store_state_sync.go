@@ -1,9 +1,11 @@
 package polybft
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
 	"github.com/0xPolygon/polygon-edge/helper/common"
@@ -15,10 +17,26 @@ var (
 	stateSyncEventsBucket = []byte("stateSyncEvents")
 	// bucket to store commitments
 	commitmentsBucket = []byte("commitments")
+	// bucket to index stored commitments by the epoch in which they were submitted
+	commitmentEpochsBucket = []byte("commitmentEpochs")
 	// bucket to store state sync proofs
 	stateSyncProofsBucket = []byte("stateSyncProofs")
 	// bucket to store message votes (signatures)
 	messageVotesBucket = []byte("votes")
+	// bucket to store pending (built, but not yet submitted) commitments
+	pendingCommitmentsBucket = []byte("pendingCommitments")
+	// bucket to store submitted commitments whose proofs are not built yet
+	pendingProofsBucket = []byte("pendingProofs")
+	// bucket to store the adaptive commitment size
+	commitmentSizeBucket = []byte("commitmentSize")
+	// commitmentSizeKey is a static key which is used to save the adaptive commitment size
+	// (there will always be one value in bucket)
+	commitmentSizeKey = []byte("commitmentSizeKey")
+	// bucket to store the proofs pruning watermark
+	prunedProofsBucket = []byte("prunedProofs")
+	// prunedProofsKey is a static key which is used to save the id of the first state sync
+	// which is not known to be executed (there will always be one value in bucket)
+	prunedProofsKey = []byte("prunedProofsKey")
 
 	// errNotEnoughStateSyncs error message
 	errNotEnoughStateSyncs = errors.New("there is either a gap or not enough sync events")
@@ -26,8 +44,39 @@ var (
 	errCommitmentNotBuilt = errors.New("there is no built commitment to register")
 	// errNoCommitmentForStateSync error message
 	errNoCommitmentForStateSync = errors.New("no commitment found for given state sync event")
+	// errStateSyncsNotContiguous error message
+	errStateSyncsNotContiguous = errors.New("state sync events are not contiguous")
+	// errStaleCommitment error message
+	errStaleCommitment = errors.New("stale commitment")
+	// errStateSyncEventConflict error message
+	errStateSyncEventConflict = errors.New("a different state sync event with the same id is already stored")
+	// errStateSyncEventNotInGap error message
+	errStateSyncEventNotInGap = errors.New("state sync event does not fill a gap of stored state sync events")
+	// errCommitmentValidatorsNotStored error message
+	errCommitmentValidatorsNotStored = errors.New("validator set of the commitment epoch is not stored")
+	// errInvalidStateSyncEvent error message
+	errInvalidStateSyncEvent = errors.New("invalid state sync event")
+	// errStateSyncEventsNotOrdered error message
+	errStateSyncEventsNotOrdered = errors.New("state sync events are not in strictly increasing id order")
 )
 
+type stateSyncEventNotFoundError struct {
+	stateSyncID uint64
+}
+
+func (e *stateSyncEventNotFoundError) Error() string {
+	return fmt.Sprintf("could not find any state sync event that has an id: %v", e.stateSyncID)
+}
+
+// StateSyncRange is an inclusive range of state sync event ids,
+// which is either not covered by any commitment, or covered by more than one commitment
+type StateSyncRange struct {
+	From uint64
+	To   uint64
+	// Overlapping indicates whether the range is covered by more than one commitment (otherwise it is not covered)
+	Overlapping bool
+}
+
 /*
 Bolt DB schema:
 
@@ -37,8 +86,23 @@ state sync events/
 commitments/
 |--> commitment.Message.ToIndex -> *CommitmentMessageSigned (json marshalled)
 
+commitmentEpochs/
+|--> commitment.Epoch + commitment.Message.ToIndex -> nil
+
 stateSyncProofs/
 |--> stateSyncProof.StateSync.Id -> *StateSyncProof (json marshalled)
+
+pendingCommitments/
+|--> pendingCommitment.EndID -> *PendingCommitment (json marshalled, without merkle tree)
+
+pendingProofs/
+|--> block number -> *CommitmentMessageSigned (json marshalled)
+
+commitmentSize/
+|--> commitmentSizeKey - adaptive commitment size derived from the last submitted commitment -> uint64
+
+prunedProofs/
+|--> prunedProofsKey - id of the first state sync which is not known to be executed -> uint64
 */
 
 type StateSyncStore struct {
@@ -55,25 +119,158 @@ func (s *StateSyncStore) initialize(tx *bolt.Tx) error {
 		return fmt.Errorf("failed to create bucket=%s: %w", string(commitmentsBucket), err)
 	}
 
+	if _, err := tx.CreateBucketIfNotExists(commitmentEpochsBucket); err != nil {
+		return fmt.Errorf("failed to create bucket=%s: %w", string(commitmentEpochsBucket), err)
+	}
+
 	if _, err := tx.CreateBucketIfNotExists(stateSyncProofsBucket); err != nil {
 		return fmt.Errorf("failed to create bucket=%s: %w", string(stateSyncProofsBucket), err)
 	}
 
+	if _, err := tx.CreateBucketIfNotExists(pendingCommitmentsBucket); err != nil {
+		return fmt.Errorf("failed to create bucket=%s: %w", string(pendingCommitmentsBucket), err)
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(pendingProofsBucket); err != nil {
+		return fmt.Errorf("failed to create bucket=%s: %w", string(pendingProofsBucket), err)
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(commitmentSizeBucket); err != nil {
+		return fmt.Errorf("failed to create bucket=%s: %w", string(commitmentSizeBucket), err)
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(prunedProofsBucket); err != nil {
+		return fmt.Errorf("failed to create bucket=%s: %w", string(prunedProofsBucket), err)
+	}
+
 	return nil
 }
 
 // insertStateSyncEvent inserts a new state sync event to state event bucket in db
-func (s *StateSyncStore) insertStateSyncEvent(event *contractsapi.StateSyncedEvent) error {
-	return s.db.Update(func(tx *bolt.Tx) error {
+func (s *StateSyncStore) insertStateSyncEvent(event *contractsapi.StateSyncedEvent) (bool, error) {
+	isInserted := false
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(stateSyncEventsBucket)
+		key := common.EncodeUint64ToBytes(event.ID.Uint64())
+
+		if bucket.Get(key) != nil {
+			// event is already inserted (e.g. re-delivered after event tracker restart)
+			return nil
+		}
+
 		raw, err := json.Marshal(event)
 		if err != nil {
 			return err
 		}
 
+		if err := bucket.Put(key, raw); err != nil {
+			return err
+		}
+
+		isInserted = true
+
+		return nil
+	})
+
+	return isInserted, err
+}
+
+// insertValidatedStateSyncEvent inserts the given state sync event, only if it fills a gap of stored events,
+// that is if its id is lower than the highest stored event id, the event preceding it is stored
+// (so the gap is filled in the id order) and it is not stored yet.
+// Inserting an already stored identical event is a no-op, while a different event with the same id is rejected.
+// It returns true if the event was inserted.
+func (s *StateSyncStore) insertValidatedStateSyncEvent(event *contractsapi.StateSyncedEvent) (bool, error) {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return false, err
+	}
+
+	isInserted := false
+
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(stateSyncEventsBucket)
+		id := event.ID.Uint64()
+
+		if existing := bucket.Get(common.EncodeUint64ToBytes(id)); existing != nil {
+			var stored contractsapi.StateSyncedEvent
+			if err := json.Unmarshal(existing, &stored); err != nil {
+				return err
+			}
+
+			if !isSameStateSyncEvent(&stored, event) {
+				return fmt.Errorf("%w: %d", errStateSyncEventConflict, id)
+			}
+
+			return nil
+		}
+
+		lastKey, _ := bucket.Cursor().Last()
+		if lastKey == nil || common.EncodeBytesToUint64(lastKey) < id {
+			return fmt.Errorf("%w: %d", errStateSyncEventNotInGap, id)
+		}
+
+		if id > 0 && bucket.Get(common.EncodeUint64ToBytes(id-1)) == nil {
+			return fmt.Errorf("%w: %d (previous event %d is not stored)", errStateSyncEventNotInGap, id, id-1)
+		}
+
+		isInserted = true
+
+		return bucket.Put(common.EncodeUint64ToBytes(id), raw)
+	})
+
+	return isInserted, err
+}
+
+// isSameStateSyncEvent returns true if the given state sync events have the same id, sender, receiver and data
+func isSameStateSyncEvent(a, b *contractsapi.StateSyncedEvent) bool {
+	return a.ID.Cmp(b.ID) == 0 &&
+		a.Sender == b.Sender &&
+		a.Receiver == b.Receiver &&
+		bytes.Equal(a.Data, b.Data)
+}
+
+// insertNewStateSyncEvents inserts given state sync events to state event bucket in db in a single transaction,
+// skipping the ones which are already inserted. Events must be ordered by strictly increasing ids,
+// otherwise the transaction is rolled back and none of the events is inserted. It returns the newly inserted events.
+func (s *StateSyncStore) insertNewStateSyncEvents(
+	events []*contractsapi.StateSyncedEvent) ([]*contractsapi.StateSyncedEvent, error) {
+	insertedEvents := make([]*contractsapi.StateSyncedEvent, 0, len(events))
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(stateSyncEventsBucket)
 
-		return bucket.Put(common.EncodeUint64ToBytes(event.ID.Uint64()), raw)
+		for i, event := range events {
+			if i > 0 && event.ID.Cmp(events[i-1].ID) <= 0 {
+				return fmt.Errorf("%w: event %d follows event %d",
+					errStateSyncEventsNotOrdered, event.ID.Uint64(), events[i-1].ID.Uint64())
+			}
+
+			key := common.EncodeUint64ToBytes(event.ID.Uint64())
+			if bucket.Get(key) != nil {
+				continue
+			}
+
+			raw, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+
+			if err := bucket.Put(key, raw); err != nil {
+				return err
+			}
+
+			insertedEvents = append(insertedEvents, event)
+		}
+
+		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	return insertedEvents, nil
 }
 
 // list iterates through all events in events bucket in db, un-marshals them, and returns as array
@@ -98,7 +295,26 @@ func (s *StateSyncStore) list() ([]*contractsapi.StateSyncedEvent, error) {
 	return events, nil
 }
 
-// getStateSyncEventsForCommitment returns state sync events for commitment
+// getStateSyncEvent returns the state sync event with the given id,
+// or stateSyncEventNotFoundError if it is not stored
+func (s *StateSyncStore) getStateSyncEvent(stateSyncID uint64) (*contractsapi.StateSyncedEvent, error) {
+	var event *contractsapi.StateSyncedEvent
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(stateSyncEventsBucket).Get(common.EncodeUint64ToBytes(stateSyncID))
+		if v == nil {
+			return &stateSyncEventNotFoundError{stateSyncID: stateSyncID}
+		}
+
+		return json.Unmarshal(v, &event)
+	})
+
+	return event, err
+}
+
+// getStateSyncEventsForCommitment returns state sync events for commitment.
+// Events are keyed by their ID, so they are returned in strictly ascending order of IDs,
+// starting from fromIndex, regardless of the order in which they were inserted
 func (s *StateSyncStore) getStateSyncEventsForCommitment(
 	fromIndex, toIndex uint64) ([]*contractsapi.StateSyncedEvent, error) {
 	var events []*contractsapi.StateSyncedEvent
@@ -154,20 +370,39 @@ func (s *StateSyncStore) getCommitmentForStateSync(stateSyncID uint64) (*Commitm
 // insertCommitmentMessage inserts signed commitment to db
 func (s *StateSyncStore) insertCommitmentMessage(commitment *CommitmentMessageSigned) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
-		raw, err := json.Marshal(commitment)
-		if err != nil {
-			return err
-		}
+		return putCommitmentMessage(tx, commitment)
+	})
+}
 
-		if err := tx.Bucket(commitmentsBucket).Put(
-			common.EncodeUint64ToBytes(commitment.Message.EndID.Uint64()), raw); err != nil {
+// insertSubmittedCommitmentMessage inserts the commitment submitted in a block of its epoch to db,
+// and indexes it by that epoch
+func (s *StateSyncStore) insertSubmittedCommitmentMessage(commitment *CommitmentMessageSigned) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := putCommitmentMessage(tx, commitment); err != nil {
 			return err
 		}
 
-		return nil
+		return tx.Bucket(commitmentEpochsBucket).Put(
+			commitmentEpochKey(commitment.Epoch, commitment.Message.EndID.Uint64()), nil)
 	})
 }
 
+// putCommitmentMessage puts the given commitment to the commitments bucket
+func putCommitmentMessage(tx *bolt.Tx, commitment *CommitmentMessageSigned) error {
+	raw, err := json.Marshal(commitment)
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket(commitmentsBucket).Put(common.EncodeUint64ToBytes(commitment.Message.EndID.Uint64()), raw)
+}
+
+// commitmentEpochKey returns the key of the commitment epoch index, which is made of the epoch
+// and the commitment end id, so that the commitments of an epoch are ordered by their state syncs
+func commitmentEpochKey(epoch, endID uint64) []byte {
+	return append(common.EncodeUint64ToBytes(epoch), common.EncodeUint64ToBytes(endID)...)
+}
+
 // getCommitmentMessage queries the signed commitment from the db
 func (s *StateSyncStore) getCommitmentMessage(toIndex uint64) (*CommitmentMessageSigned, error) {
 	var commitment *CommitmentMessageSigned
@@ -184,6 +419,278 @@ func (s *StateSyncStore) getCommitmentMessage(toIndex uint64) (*CommitmentMessag
 	return commitment, err
 }
 
+// getLastCommitmentMessage returns the stored signed commitment with the highest end id (nil if there is none)
+func (s *StateSyncStore) getLastCommitmentMessage() (*CommitmentMessageSigned, error) {
+	var commitment *CommitmentMessageSigned
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		_, raw := tx.Bucket(commitmentsBucket).Cursor().Last()
+		if raw == nil {
+			return nil
+		}
+
+		return json.Unmarshal(raw, &commitment)
+	})
+
+	return commitment, err
+}
+
+// getCommitmentMessages returns stored signed commitments, which contain any of the state syncs
+// in the given range, in ascending order of their state syncs
+func (s *StateSyncStore) getCommitmentMessages(fromIndex, toIndex uint64) ([]*CommitmentMessageSigned, error) {
+	var commitments []*CommitmentMessageSigned
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(commitmentsBucket).Cursor()
+
+		// commitments are keyed by their end id, so the first one containing fromIndex ends at or after it
+		for k, v := c.Seek(common.EncodeUint64ToBytes(fromIndex)); k != nil; k, v = c.Next() {
+			var commitment *CommitmentMessageSigned
+			if err := json.Unmarshal(v, &commitment); err != nil {
+				return err
+			}
+
+			if commitment.Message.StartID.Uint64() > toIndex {
+				break
+			}
+
+			commitments = append(commitments, commitment)
+		}
+
+		return nil
+	})
+
+	return commitments, err
+}
+
+// getEpochCommitmentMessages returns stored signed commitments, which were submitted in the given epoch,
+// in ascending order of their state syncs. Commitments are looked up through the commitment epoch index,
+// skipping the indexed commitments which got removed (or replaced) since.
+func (s *StateSyncStore) getEpochCommitmentMessages(epoch uint64) ([]*CommitmentMessageSigned, error) {
+	var commitments []*CommitmentMessageSigned
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		prefix := common.EncodeUint64ToBytes(epoch)
+		bucket := tx.Bucket(commitmentsBucket)
+		c := tx.Bucket(commitmentEpochsBucket).Cursor()
+
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			raw := bucket.Get(k[len(prefix):])
+			if raw == nil {
+				continue
+			}
+
+			var commitment *CommitmentMessageSigned
+			if err := json.Unmarshal(raw, &commitment); err != nil {
+				return err
+			}
+
+			if commitment.Epoch == epoch {
+				commitments = append(commitments, commitment)
+			}
+		}
+
+		return nil
+	})
+
+	return commitments, err
+}
+
+// findCommitmentGaps checks that every state sync id in the given (inclusive) range is covered
+// by exactly one stored commitment, and returns the ranges which are either not covered or covered more than once
+func (s *StateSyncStore) findCommitmentGaps(fromIndex, toIndex uint64) ([]*StateSyncRange, error) {
+	if fromIndex > toIndex {
+		return nil, nil
+	}
+
+	commitments, err := s.getCommitmentMessages(fromIndex, toIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// commitments are ordered by their end id, so they are sorted by their start id to find gaps and overlaps
+	sort.SliceStable(commitments, func(i, j int) bool {
+		return commitments[i].Message.StartID.Cmp(commitments[j].Message.StartID) < 0
+	})
+
+	var (
+		anomalies []*StateSyncRange
+		// nextUncovered is the lowest state sync id not covered by any of already processed commitments
+		nextUncovered = fromIndex
+	)
+
+	for _, commitment := range commitments {
+		startID := commitment.Message.StartID.Uint64()
+		endID := commitment.Message.EndID.Uint64()
+
+		startID = common.Max(startID, fromIndex)
+		endID = common.Min(endID, toIndex)
+
+		if startID > nextUncovered {
+			anomalies = append(anomalies, &StateSyncRange{From: nextUncovered, To: startID - 1})
+		} else if startID < nextUncovered {
+			anomalies = append(anomalies, &StateSyncRange{
+				From:        startID,
+				To:          common.Min(endID, nextUncovered-1),
+				Overlapping: true,
+			})
+		}
+
+		nextUncovered = common.Max(nextUncovered, endID+1)
+	}
+
+	if nextUncovered <= toIndex {
+		anomalies = append(anomalies, &StateSyncRange{From: nextUncovered, To: toIndex})
+	}
+
+	return anomalies, nil
+}
+
+// removeUncommittedCommitments removes stored commitments (and state sync proofs built from them)
+// which are not committed on-chain, meaning that they end at or after the given next committed index
+func (s *StateSyncStore) removeUncommittedCommitments(nextCommittedIndex uint64) (int, error) {
+	removedCount := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		from := common.EncodeUint64ToBytes(nextCommittedIndex)
+
+		commitmentsCursor := tx.Bucket(commitmentsBucket).Cursor()
+		for k, _ := commitmentsCursor.Seek(from); k != nil; k, _ = commitmentsCursor.Seek(from) {
+			if err := commitmentsCursor.Delete(); err != nil {
+				return err
+			}
+
+			removedCount++
+		}
+
+		proofsCursor := tx.Bucket(stateSyncProofsBucket).Cursor()
+		for k, _ := proofsCursor.Seek(from); k != nil; k, _ = proofsCursor.Seek(from) {
+			if err := proofsCursor.Delete(); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return removedCount, err
+}
+
+// insertPendingCommitment inserts given pending commitment to db.
+// Merkle tree of the commitment is not persisted, since it can be rebuilt from the state sync events.
+func (s *StateSyncStore) insertPendingCommitment(commitment *PendingCommitment) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		raw, err := json.Marshal(commitment)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(pendingCommitmentsBucket).Put(common.EncodeUint64ToBytes(commitment.EndID.Uint64()), raw)
+	})
+}
+
+// getPendingCommitments returns persisted pending commitments (without merkle trees), ordered by their end index
+func (s *StateSyncStore) getPendingCommitments() ([]*PendingCommitment, error) {
+	var commitments []*PendingCommitment
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(pendingCommitmentsBucket).ForEach(func(k, v []byte) error {
+			var commitment *PendingCommitment
+			if err := json.Unmarshal(v, &commitment); err != nil {
+				return err
+			}
+
+			commitments = append(commitments, commitment)
+
+			return nil
+		})
+	})
+
+	return commitments, err
+}
+
+// removePendingCommitments removes all the persisted pending commitments
+func (s *StateSyncStore) removePendingCommitments() error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.DeleteBucket(pendingCommitmentsBucket); err != nil {
+			return err
+		}
+
+		_, err := tx.CreateBucket(pendingCommitmentsBucket)
+
+		return err
+	})
+}
+
+// insertPendingProof persists the commitment submitted in the given block, whose proofs are not built yet
+func (s *StateSyncStore) insertPendingProof(blockNumber uint64, commitment *CommitmentMessageSigned) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		raw, err := json.Marshal(commitment)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(pendingProofsBucket).Put(common.EncodeUint64ToBytes(blockNumber), raw)
+	})
+}
+
+// getPendingProofs returns persisted submitted commitments whose proofs are not built yet (block number -> commitment)
+func (s *StateSyncStore) getPendingProofs() (map[uint64]*CommitmentMessageSigned, error) {
+	commitments := make(map[uint64]*CommitmentMessageSigned)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(pendingProofsBucket).ForEach(func(k, v []byte) error {
+			var commitment *CommitmentMessageSigned
+			if err := json.Unmarshal(v, &commitment); err != nil {
+				return err
+			}
+
+			commitments[common.EncodeBytesToUint64(k)] = commitment
+
+			return nil
+		})
+	})
+
+	return commitments, err
+}
+
+// removePendingProofs removes persisted submitted commitments of the given blocks
+func (s *StateSyncStore) removePendingProofs(blockNumbers ...uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(pendingProofsBucket)
+
+		for _, blockNumber := range blockNumbers {
+			if err := bucket.Delete(common.EncodeUint64ToBytes(blockNumber)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// getCommitmentSize returns the persisted adaptive commitment size (zero if it was never written)
+func (s *StateSyncStore) getCommitmentSize() (uint64, error) {
+	var size uint64
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if value := tx.Bucket(commitmentSizeBucket).Get(commitmentSizeKey); value != nil {
+			size = common.EncodeBytesToUint64(value)
+		}
+
+		return nil
+	})
+
+	return size, err
+}
+
+// writeCommitmentSize persists the adaptive commitment size
+func (s *StateSyncStore) writeCommitmentSize(size uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(commitmentSizeBucket).Put(commitmentSizeKey, common.EncodeUint64ToBytes(size))
+	})
+}
+
 // insertMessageVote inserts given vote to signatures bucket of given epoch
 func (s *StateSyncStore) insertMessageVote(epoch uint64, key []byte, vote *MessageSignature) (int, error) {
 	var numSignatures int
@@ -306,3 +813,70 @@ func (s *StateSyncStore) getStateSyncProof(stateSyncID uint64) (*StateSyncProof,
 
 	return ssp, err
 }
+
+// getFirstStateSyncProofID returns the lowest state sync id for which a proof is stored,
+// and whether there is any stored proof at all
+func (s *StateSyncStore) getFirstStateSyncProofID() (uint64, bool, error) {
+	var (
+		stateSyncID uint64
+		exists      bool
+	)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if k, _ := tx.Bucket(stateSyncProofsBucket).Cursor().First(); k != nil {
+			stateSyncID = common.EncodeBytesToUint64(k)
+			exists = true
+		}
+
+		return nil
+	})
+
+	return stateSyncID, exists, err
+}
+
+// getPrunedProofsIndex returns the id of the first state sync which is not known to be executed,
+// i.e. the id up to which the proofs got pruned (zero if proofs were never pruned)
+func (s *StateSyncStore) getPrunedProofsIndex() (uint64, error) {
+	var index uint64
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		if value := tx.Bucket(prunedProofsBucket).Get(prunedProofsKey); value != nil {
+			index = common.EncodeBytesToUint64(value)
+		}
+
+		return nil
+	})
+
+	return index, err
+}
+
+// pruneExecutedProofs removes stored proofs of the state syncs with ids lower than the given one,
+// and persists the given id as the pruned proofs index, if it is higher than the current one.
+// State sync events are retained, so that the pruned proofs can be rebuilt on demand.
+func (s *StateSyncStore) pruneExecutedProofs(beforeID uint64) (int, error) {
+	prunedCount := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		before := common.EncodeUint64ToBytes(beforeID)
+
+		prunedProofs := tx.Bucket(prunedProofsBucket)
+		if index := prunedProofs.Get(prunedProofsKey); index == nil || bytes.Compare(index, before) < 0 {
+			if err := prunedProofs.Put(prunedProofsKey, before); err != nil {
+				return err
+			}
+		}
+
+		cursor := tx.Bucket(stateSyncProofsBucket).Cursor()
+		for k, _ := cursor.First(); k != nil && bytes.Compare(k, before) < 0; k, _ = cursor.First() {
+			if err := cursor.Delete(); err != nil {
+				return err
+			}
+
+			prunedCount++
+		}
+
+		return nil
+	})
+
+	return prunedCount, err
+}
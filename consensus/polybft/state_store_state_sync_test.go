@@ -6,7 +6,9 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/bitmap"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
 	"github.com/0xPolygon/polygon-edge/merkle-tree"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
@@ -25,12 +27,171 @@ func TestState_InsertEvent(t *testing.T) {
 		Data:     []byte{},
 	}
 
-	err := state.StateSyncStore.insertStateSyncEvent(event1)
+	isInserted, err := state.StateSyncStore.insertStateSyncEvent(event1)
 	assert.NoError(t, err)
+	assert.True(t, isInserted)
 
 	events, err := state.StateSyncStore.list()
 	assert.NoError(t, err)
 	assert.Len(t, events, 1)
+
+	// inserting the same event again is a no-op
+	isInserted, err = state.StateSyncStore.insertStateSyncEvent(event1)
+	assert.NoError(t, err)
+	assert.False(t, isInserted)
+
+	events, err = state.StateSyncStore.list()
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestState_GetCommitmentSigners(t *testing.T) {
+	t.Parallel()
+
+	state := newTestState(t)
+	epochValidators := validator.NewTestValidators(t, 5).GetPublicIdentities()
+	lastValidators := validator.NewTestValidators(t, 5).GetPublicIdentities()
+
+	// validators of epoch 3 are stored as the snapshot of epoch 2
+	require.NoError(t, state.EpochStore.insertValidatorSnapshot(
+		&validatorSnapshot{Epoch: 2, Snapshot: epochValidators}))
+	require.NoError(t, state.EpochStore.insertValidatorSnapshot(
+		&validatorSnapshot{Epoch: 4, Snapshot: lastValidators}))
+
+	signersBitmap := bitmap.Bitmap{}
+	signersBitmap.Set(0)
+	signersBitmap.Set(2)
+	signersBitmap.Set(3)
+
+	insertCommitment := func(startID, endID, epoch uint64) {
+		t.Helper()
+
+		require.NoError(t, state.StateSyncStore.insertCommitmentMessage(&CommitmentMessageSigned{
+			Message: &contractsapi.StateSyncCommitment{
+				StartID: new(big.Int).SetUint64(startID),
+				EndID:   new(big.Int).SetUint64(endID),
+			},
+			AggSignature: Signature{Bitmap: signersBitmap},
+			Epoch:        epoch,
+		}))
+	}
+
+	insertCommitment(1, 5, 3)
+	insertCommitment(6, 10, 7)
+	insertCommitment(11, 15, 0)
+
+	// bitmap is mapped to the validator set of the commitment epoch
+	signers, err := state.GetCommitmentSigners(3)
+	require.NoError(t, err)
+	require.Equal(t, []types.Address{
+		epochValidators[0].Address, epochValidators[2].Address, epochValidators[3].Address,
+	}, signers)
+
+	// validator set of epoch 7 is not stored, so signers can not be resolved
+	// (even though the validator set of a later epoch is stored)
+	_, err = state.GetCommitmentSigners(6)
+	require.ErrorIs(t, err, errCommitmentValidatorsNotStored)
+
+	// epoch of the commitment is unknown
+	_, err = state.GetCommitmentSigners(11)
+	require.ErrorIs(t, err, errCommitmentValidatorsNotStored)
+
+	_, err = state.GetCommitmentSigners(16)
+	require.ErrorIs(t, err, errNoCommitmentForStateSync)
+}
+
+func TestState_InsertValidatedStateSyncEvent(t *testing.T) {
+	t.Parallel()
+
+	state := newTestState(t)
+	events := generateStateSyncEvents(t, 6, 0)
+
+	insert := func(event *contractsapi.StateSyncedEvent) error {
+		_, err := state.InsertValidatedStateSyncEvent(event, 1)
+
+		return err
+	}
+
+	// there are no stored events, so there is no gap to fill
+	require.ErrorIs(t, insert(events[0]), errStateSyncEventNotInGap)
+
+	// events 2 and 3 are missing
+	insertTestStateSyncEvents(t, state.StateSyncStore, events[0], events[1], events[4], events[5])
+
+	// event beyond the highest stored one does not fill a gap
+	_, err := state.InsertValidatedStateSyncEvent(generateStateSyncEvents(t, 1, 6)[0], 10)
+	require.ErrorIs(t, err, errStateSyncEventNotInGap)
+	require.ErrorContains(t, err, "rootchain block 10")
+
+	// gap is filled in the id order
+	require.ErrorIs(t, insert(events[3]), errStateSyncEventNotInGap)
+
+	// event without sender or receiver is rejected
+	for _, modify := range []func(*contractsapi.StateSyncedEvent){
+		func(e *contractsapi.StateSyncedEvent) { e.Sender = types.ZeroAddress },
+		func(e *contractsapi.StateSyncedEvent) { e.Receiver = types.ZeroAddress },
+	} {
+		invalidEvent := *events[2]
+		modify(&invalidEvent)
+		require.ErrorIs(t, insert(&invalidEvent), errInvalidStateSyncEvent)
+	}
+
+	// different event with the id of the stored one is not overwritten
+	for _, modify := range []func(*contractsapi.StateSyncedEvent){
+		func(e *contractsapi.StateSyncedEvent) { e.Sender = types.StringToAddress("0x99") },
+		func(e *contractsapi.StateSyncedEvent) { e.Receiver = types.StringToAddress("0x99") },
+		func(e *contractsapi.StateSyncedEvent) { e.Data = []byte{0x1, 0x2} },
+	} {
+		conflictingEvent := *events[1]
+		modify(&conflictingEvent)
+		require.ErrorIs(t, insert(&conflictingEvent), errStateSyncEventConflict)
+	}
+
+	// identical stored event is accepted, but not inserted again
+	isInserted, err := state.InsertValidatedStateSyncEvent(events[1], 1)
+	require.NoError(t, err)
+	require.False(t, isInserted)
+
+	// missing events fill the gap
+	for _, event := range events[2:4] {
+		isInserted, err = state.InsertValidatedStateSyncEvent(event, 1)
+		require.NoError(t, err)
+		require.True(t, isInserted)
+	}
+
+	stored, err := state.StateSyncStore.getStateSyncEventsForCommitment(0, 5)
+	require.NoError(t, err)
+	require.Equal(t, events, stored)
+}
+
+func TestState_insertNewStateSyncEvents(t *testing.T) {
+	t.Parallel()
+
+	state := newTestState(t)
+	events := generateStateSyncEvents(t, 6, 0)
+
+	inserted, err := state.StateSyncStore.insertNewStateSyncEvents(events[:3])
+	require.NoError(t, err)
+	require.Equal(t, events[:3], inserted)
+
+	// batch fails on the duplicate id after events 3 and 4 are written within the transaction,
+	// so the whole batch is rolled back
+	_, err = state.StateSyncStore.insertNewStateSyncEvents(
+		[]*contractsapi.StateSyncedEvent{events[3], events[4], events[4], events[5]})
+	require.ErrorIs(t, err, errStateSyncEventsNotOrdered)
+
+	stored, err := state.StateSyncStore.list()
+	require.NoError(t, err)
+	require.Equal(t, events[:3], stored)
+
+	// already stored events are skipped
+	inserted, err = state.StateSyncStore.insertNewStateSyncEvents(events[2:])
+	require.NoError(t, err)
+	require.Equal(t, events[3:], inserted)
+
+	stored, err = state.StateSyncStore.list()
+	require.NoError(t, err)
+	require.Equal(t, events, stored)
 }
 
 func TestState_Insert_And_Get_MessageVotes(t *testing.T) {
@@ -38,7 +199,7 @@ func TestState_Insert_And_Get_MessageVotes(t *testing.T) {
 
 	state := newTestState(t)
 	epoch := uint64(1)
-	assert.NoError(t, state.EpochStore.insertEpoch(epoch))
+	assert.NoError(t, state.EpochStore.insertEpoch(epoch, nil))
 
 	hash := []byte{1, 2}
 	_, err := state.StateSyncStore.insertMessageVote(1, hash, &MessageSignature{
@@ -61,10 +222,10 @@ func TestState_getStateSyncEventsForCommitment_NotEnoughEvents(t *testing.T) {
 	state := newTestState(t)
 
 	for i := 0; i < maxCommitmentSize-2; i++ {
-		assert.NoError(t, state.StateSyncStore.insertStateSyncEvent(&contractsapi.StateSyncedEvent{
+		insertTestStateSyncEvents(t, state.StateSyncStore, &contractsapi.StateSyncedEvent{
 			ID:   big.NewInt(int64(i)),
 			Data: []byte{1, 2},
-		}))
+		})
 	}
 
 	_, err := state.StateSyncStore.getStateSyncEventsForCommitment(0, maxCommitmentSize-1)
@@ -77,10 +238,10 @@ func TestState_getStateSyncEventsForCommitment(t *testing.T) {
 	state := newTestState(t)
 
 	for i := 0; i < maxCommitmentSize; i++ {
-		assert.NoError(t, state.StateSyncStore.insertStateSyncEvent(&contractsapi.StateSyncedEvent{
+		insertTestStateSyncEvents(t, state.StateSyncStore, &contractsapi.StateSyncedEvent{
 			ID:   big.NewInt(int64(i)),
 			Data: []byte{1, 2},
-		}))
+		})
 	}
 
 	t.Run("Return all - forced. Enough events", func(t *testing.T) {
@@ -115,6 +276,58 @@ func TestState_getStateSyncEventsForCommitment(t *testing.T) {
 	})
 }
 
+func TestState_getStateSyncEventsForCommitment_OutOfOrderInsertion(t *testing.T) {
+	t.Parallel()
+
+	const eventsCount = 8
+
+	state := newTestState(t)
+	events := generateStateSyncEvents(t, eventsCount, 0)
+
+	// insert events in reverse order
+	for i := eventsCount - 1; i >= 0; i-- {
+		insertTestStateSyncEvents(t, state.StateSyncStore, events[i])
+	}
+
+	result, err := state.StateSyncStore.getStateSyncEventsForCommitment(2, eventsCount-1)
+	require.NoError(t, err)
+	require.Len(t, result, eventsCount-2)
+
+	for i, event := range result {
+		require.Equal(t, uint64(i+2), event.ID.Uint64())
+	}
+
+	require.NoError(t, checkStateSyncsContiguity(result, 2))
+}
+
+func TestState_GetStateSyncEvent(t *testing.T) {
+	t.Parallel()
+
+	state := newTestState(t)
+	events := generateStateSyncEvents(t, 5, 1)
+
+	// leave a gap at id 3
+	for _, event := range events {
+		if event.ID.Uint64() != 3 {
+			insertTestStateSyncEvents(t, state.StateSyncStore, event)
+		}
+	}
+
+	event, err := state.GetStateSyncEvent(4)
+	require.NoError(t, err)
+	require.Equal(t, events[3], event)
+
+	for _, id := range []uint64{0, 3, 6} {
+		event, err = state.GetStateSyncEvent(id)
+		require.Nil(t, event)
+
+		var notFoundErr *stateSyncEventNotFoundError
+		require.ErrorAs(t, err, &notFoundErr)
+		require.Equal(t, id, notFoundErr.stateSyncID)
+		require.NotErrorIs(t, err, errNotEnoughStateSyncs)
+	}
+}
+
 func TestState_insertCommitmentMessage(t *testing.T) {
 	t.Parallel()
 
@@ -130,6 +343,75 @@ func TestState_insertCommitmentMessage(t *testing.T) {
 	assert.Equal(t, commitment, commitmentFromDB)
 }
 
+func TestState_FindCommitmentGaps(t *testing.T) {
+	t.Parallel()
+
+	insertCommitments := func(t *testing.T, state *State, ranges ...[2]int64) {
+		t.Helper()
+
+		for _, r := range ranges {
+			commitment := newTestCommitmentSigned(t, types.Hash{}, r[0], r[1])
+			require.NoError(t, state.StateSyncStore.insertCommitmentMessage(commitment))
+		}
+	}
+
+	t.Run("no gaps", func(t *testing.T) {
+		t.Parallel()
+
+		state := newTestState(t)
+		insertCommitments(t, state, [2]int64{0, 4}, [2]int64{5, 9}, [2]int64{10, 14})
+
+		anomalies, err := state.StateSyncStore.findCommitmentGaps(0, 14)
+		require.NoError(t, err)
+		require.Empty(t, anomalies)
+
+		// range not covered by any commitment
+		anomalies, err = state.StateSyncStore.findCommitmentGaps(0, 16)
+		require.NoError(t, err)
+		require.Equal(t, []*StateSyncRange{{From: 15, To: 16}}, anomalies)
+	})
+
+	t.Run("missing commitment", func(t *testing.T) {
+		t.Parallel()
+
+		state := newTestState(t)
+		insertCommitments(t, state, [2]int64{0, 4}, [2]int64{10, 14})
+
+		anomalies, err := state.FindCommitmentGaps()
+		require.NoError(t, err)
+		require.Equal(t, []*StateSyncRange{{From: 5, To: 9}}, anomalies)
+	})
+
+	t.Run("overlapping commitments", func(t *testing.T) {
+		t.Parallel()
+
+		state := newTestState(t)
+		insertCommitments(t, state, [2]int64{0, 6}, [2]int64{5, 9}, [2]int64{12, 14})
+
+		anomalies, err := state.FindCommitmentGaps()
+		require.NoError(t, err)
+		require.Equal(t, []*StateSyncRange{
+			{From: 5, To: 6, Overlapping: true},
+			{From: 10, To: 11},
+		}, anomalies)
+	})
+
+	t.Run("empty range", func(t *testing.T) {
+		t.Parallel()
+
+		state := newTestState(t)
+
+		anomalies, err := state.StateSyncStore.findCommitmentGaps(1, 0)
+		require.NoError(t, err)
+		require.Empty(t, anomalies)
+
+		// there are no stored commitments
+		anomalies, err = state.FindCommitmentGaps()
+		require.NoError(t, err)
+		require.Empty(t, anomalies)
+	})
+}
+
 func TestState_StateSync_insertAndGetStateSyncProof(t *testing.T) {
 	t.Parallel()
 
@@ -224,7 +506,7 @@ func TestState_GetNestedBucketInEpoch(t *testing.T) {
 			)
 
 			s := newTestState(t)
-			require.NoError(t, s.EpochStore.insertEpoch(c.epochNumber))
+			require.NoError(t, s.EpochStore.insertEpoch(c.epochNumber, nil))
 			err = s.db.View(func(tx *bbolt.Tx) error {
 				nestedBucket, err = getNestedBucketInEpoch(tx, c.epochNumber, c.bucketName)
 
@@ -264,6 +546,50 @@ func createTestCommitmentMessage(t *testing.T, fromIndex uint64) *CommitmentMess
 	}
 }
 
+func TestState_pruneExecutedProofs(t *testing.T) {
+	t.Parallel()
+
+	state := newTestState(t)
+
+	_, exists, err := state.StateSyncStore.getFirstStateSyncProofID()
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	insertTestStateSyncProofs(t, state, 10)
+
+	prunedCount, err := state.StateSyncStore.pruneExecutedProofs(4)
+	require.NoError(t, err)
+	require.Equal(t, 4, prunedCount)
+
+	prunedIndex, err := state.StateSyncStore.getPrunedProofsIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), prunedIndex)
+
+	firstID, exists, err := state.StateSyncStore.getFirstStateSyncProofID()
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, uint64(4), firstID)
+
+	for id := uint64(0); id < 10; id++ {
+		proof, err := state.StateSyncStore.getStateSyncProof(id)
+		require.NoError(t, err)
+		require.Equal(t, id < 4, proof == nil, "state sync %d", id)
+	}
+
+	// pruning again up to the same id is a no-op
+	prunedCount, err = state.StateSyncStore.pruneExecutedProofs(4)
+	require.NoError(t, err)
+	require.Zero(t, prunedCount)
+
+	// pruned proofs index never goes backwards
+	_, err = state.StateSyncStore.pruneExecutedProofs(2)
+	require.NoError(t, err)
+
+	prunedIndex, err = state.StateSyncStore.getPrunedProofsIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), prunedIndex)
+}
+
 func insertTestCommitments(t *testing.T, state *State, numberOfCommitments uint64) {
 	t.Helper()
 
@@ -297,3 +623,37 @@ func createTestStateSync(index int64) *contractsapi.StateSyncedEvent {
 		Data:     []byte{0, 1},
 	}
 }
+
+func TestState_PendingCommitments(t *testing.T) {
+	t.Parallel()
+
+	state := newTestState(t)
+
+	events := generateStateSyncEvents(t, 4, 0)
+
+	commitment1, err := NewPendingCommitment(1, events[:2], maxCommitmentSize)
+	require.NoError(t, err)
+
+	commitment2, err := NewPendingCommitment(1, events, maxCommitmentSize)
+	require.NoError(t, err)
+
+	require.NoError(t, state.StateSyncStore.insertPendingCommitment(commitment2))
+	require.NoError(t, state.StateSyncStore.insertPendingCommitment(commitment1))
+
+	commitments, err := state.StateSyncStore.getPendingCommitments()
+	require.NoError(t, err)
+	require.Len(t, commitments, 2)
+
+	// commitments are ordered by end index and persisted without merkle trees
+	for i, expected := range []*PendingCommitment{commitment1, commitment2} {
+		require.Equal(t, expected.Epoch, commitments[i].Epoch)
+		require.Equal(t, expected.StateSyncCommitment, commitments[i].StateSyncCommitment)
+		require.Nil(t, commitments[i].MerkleTree)
+	}
+
+	require.NoError(t, state.StateSyncStore.removePendingCommitments())
+
+	commitments, err = state.StateSyncStore.getPendingCommitments()
+	require.NoError(t, err)
+	require.Empty(t, commitments)
+}
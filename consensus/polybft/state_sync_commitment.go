@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
+	bls "github.com/0xPolygon/polygon-edge/consensus/polybft/signer"
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
 	"github.com/0xPolygon/polygon-edge/crypto"
 	"github.com/0xPolygon/polygon-edge/merkle-tree"
 	"github.com/0xPolygon/polygon-edge/state/runtime/precompiled"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/hashicorp/go-hclog"
 )
 
 const (
@@ -17,16 +21,41 @@ const (
 	stTypeEndEpoch         = "end-epoch"
 )
 
+var (
+	// errInvalidCommitmentSignature is returned when aggregated signature of the commitment can not be verified
+	errInvalidCommitmentSignature = errors.New("invalid commitment aggregated signature")
+	// errInvalidCommitmentRange is returned when commitment range of state sync indexes is inverted
+	// or wider than the maximum commitment size
+	errInvalidCommitmentRange = errors.New("invalid commitment range")
+)
+
 // PendingCommitment holds merkle trie of bridge transactions accompanied by epoch number
 type PendingCommitment struct {
 	*contractsapi.StateSyncCommitment
-	MerkleTree *merkle.MerkleTree
+	MerkleTree *merkle.MerkleTree `json:"-"`
 	Epoch      uint64
 }
 
-// NewPendingCommitment creates a new commitment object
-func NewPendingCommitment(epoch uint64, stateSyncEvents []*contractsapi.StateSyncedEvent) (*PendingCommitment, error) {
-	tree, err := createMerkleTree(stateSyncEvents)
+// NewPendingCommitment creates a new commitment object, using the default merkle hasher.
+// It returns an error if the range of given state sync events is wider than the maximum commitment size.
+func NewPendingCommitment(epoch uint64, stateSyncEvents []*contractsapi.StateSyncedEvent,
+	maxCommitmentSize uint64) (*PendingCommitment, error) {
+	return NewPendingCommitmentWithHasher(epoch, stateSyncEvents, maxCommitmentSize, defaultMerkleHasher)
+}
+
+// NewPendingCommitmentWithHasher creates a new commitment object, whose merkle tree is built using the given hasher
+func NewPendingCommitmentWithHasher(epoch uint64, stateSyncEvents []*contractsapi.StateSyncedEvent,
+	maxCommitmentSize uint64, hasher MerkleHasher) (*PendingCommitment, error) {
+	if len(stateSyncEvents) == 0 {
+		return nil, fmt.Errorf("%w: no state sync events", errInvalidCommitmentRange)
+	}
+
+	startID, endID := stateSyncEvents[0].ID, stateSyncEvents[len(stateSyncEvents)-1].ID
+	if err := validateCommitmentRange(startID.Uint64(), endID.Uint64(), maxCommitmentSize); err != nil {
+		return nil, err
+	}
+
+	tree, err := createMerkleTree(stateSyncEvents, hasher)
 	if err != nil {
 		return nil, err
 	}
@@ -35,18 +64,57 @@ func NewPendingCommitment(epoch uint64, stateSyncEvents []*contractsapi.StateSyn
 		MerkleTree: tree,
 		Epoch:      epoch,
 		StateSyncCommitment: &contractsapi.StateSyncCommitment{
-			StartID: stateSyncEvents[0].ID,
-			EndID:   stateSyncEvents[len(stateSyncEvents)-1].ID,
+			StartID: startID,
+			EndID:   endID,
 			Root:    tree.Hash(),
 		},
 	}, nil
 }
 
+// validateCommitmentRange checks that the commitment range of state sync indexes is not inverted,
+// and that it does not exceed the maximum commitment size (which would be rejected by on-chain verification)
+func validateCommitmentRange(fromIndex, toIndex, maxCommitmentSize uint64) error {
+	if fromIndex > toIndex {
+		return fmt.Errorf("%w: from index %d is greater than to index %d",
+			errInvalidCommitmentRange, fromIndex, toIndex)
+	}
+
+	if size := toIndex - fromIndex + 1; size > maxCommitmentSize {
+		return fmt.Errorf("%w: commitment size %d (from index %d to index %d) exceeds maximum commitment size %d",
+			errInvalidCommitmentRange, size, fromIndex, toIndex, maxCommitmentSize)
+	}
+
+	return nil
+}
+
 // Hash calculates hash value for commitment object.
 func (cm *PendingCommitment) Hash() (types.Hash, error) {
-	data, err := cm.StateSyncCommitment.EncodeAbi()
+	return hashStateSyncCommitment(cm.StateSyncCommitment)
+}
+
+// ComputeCommitmentHash calculates the hash of a commitment of state syncs in the given range
+// with the given merkle root, which is the hash validators sign and vote for.
+// It yields the same value as Hash of a commitment built from the matching state sync events.
+// Note that the epoch is not part of the hash, it only scopes the votes.
+func ComputeCommitmentHash(fromIndex, toIndex uint64, root types.Hash) (types.Hash, error) {
+	if fromIndex > toIndex {
+		return types.Hash{}, fmt.Errorf("%w: from index %d is greater than to index %d",
+			errInvalidCommitmentRange, fromIndex, toIndex)
+	}
+
+	return hashStateSyncCommitment(&contractsapi.StateSyncCommitment{
+		StartID: new(big.Int).SetUint64(fromIndex),
+		EndID:   new(big.Int).SetUint64(toIndex),
+		Root:    root,
+	})
+}
+
+// hashStateSyncCommitment calculates keccak hash of ABI encoded state sync commitment
+func hashStateSyncCommitment(commitment *contractsapi.StateSyncCommitment) (types.Hash, error) {
+	data, err := commitment.EncodeAbi()
 	if err != nil {
-		return types.Hash{}, err
+		return types.Hash{}, fmt.Errorf("failed to encode state sync commitment (from index %s to index %s): %w",
+			commitment.StartID, commitment.EndID, err)
 	}
 
 	return crypto.Keccak256Hash(data), nil
@@ -59,22 +127,27 @@ type CommitmentMessageSigned struct {
 	Message      *contractsapi.StateSyncCommitment
 	AggSignature Signature
 	PublicKeys   [][]byte
+	// Epoch is the epoch in which the commitment was submitted (zero if unknown).
+	// It is only stored locally and it is not a part of the commitment transaction.
+	Epoch uint64 `json:",omitempty"`
 }
 
 // Hash calculates hash value for commitment object.
 func (cm *CommitmentMessageSigned) Hash() (types.Hash, error) {
-	data, err := cm.Message.EncodeAbi()
-	if err != nil {
-		return types.Hash{}, err
-	}
-
-	return crypto.Keccak256Hash(data), nil
+	return hashStateSyncCommitment(cm.Message)
 }
 
 // VerifyStateSyncProof validates given state sync proof
-// against merkle tree root hash contained in the CommitmentMessage
+// against merkle tree root hash contained in the CommitmentMessage, using the default merkle hasher
 func (cm *CommitmentMessageSigned) VerifyStateSyncProof(proof []types.Hash,
 	stateSync *contractsapi.StateSyncedEvent) error {
+	return cm.VerifyStateSyncProofWithHasher(proof, stateSync, defaultMerkleHasher)
+}
+
+// VerifyStateSyncProofWithHasher validates given state sync proof
+// against merkle tree root hash contained in the CommitmentMessage, using the given merkle hasher
+func (cm *CommitmentMessageSigned) VerifyStateSyncProofWithHasher(proof []types.Hash,
+	stateSync *contractsapi.StateSyncedEvent, hasher MerkleHasher) error {
 	if stateSync == nil {
 		return errors.New("no state sync event")
 	}
@@ -89,8 +162,8 @@ func (cm *CommitmentMessageSigned) VerifyStateSyncProof(proof []types.Hash,
 		return err
 	}
 
-	return merkle.VerifyProof(stateSync.ID.Uint64()-cm.Message.StartID.Uint64(),
-		hash, proof, cm.Message.Root)
+	return merkle.VerifyProofUsing(stateSync.ID.Uint64()-cm.Message.StartID.Uint64(),
+		hash, proof, cm.Message.Root, hasher.newHash())
 }
 
 // ContainsStateSync checks if commitment contains given state sync event
@@ -98,6 +171,42 @@ func (cm *CommitmentMessageSigned) ContainsStateSync(stateSyncID uint64) bool {
 	return cm.Message.StartID.Uint64() <= stateSyncID && cm.Message.EndID.Uint64() >= stateSyncID
 }
 
+// VerifyCommitmentSignature verifies that given signed commitment would pass on-chain verification,
+// meaning that validators marked in the signature bitmap reach the quorum in the provided validator set,
+// and that the aggregated signature is valid for commitment hash against their BLS public keys
+func VerifyCommitmentSignature(commitment *CommitmentMessageSigned, validators validator.AccountSet) error {
+	if commitment == nil || commitment.Message == nil {
+		return errors.New("commitment message is not provided")
+	}
+
+	hash, err := commitment.Hash()
+	if err != nil {
+		return fmt.Errorf("failed to calculate commitment hash: %w", err)
+	}
+
+	signers, err := validators.GetFilteredValidators(commitment.AggSignature.Bitmap)
+	if err != nil {
+		return fmt.Errorf("failed to get commitment signers: %w", err)
+	}
+
+	validatorSet := validator.NewValidatorSet(validators, hclog.NewNullLogger())
+	if !validatorSet.HasQuorum(signers.GetAddressesAsSet()) {
+		return fmt.Errorf("%w: %d out of %d validators signed the commitment",
+			errQuorumNotReached, len(signers), validators.Len())
+	}
+
+	signature, err := bls.UnmarshalSignature(commitment.AggSignature.AggregatedSignature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvalidCommitmentSignature, err)
+	}
+
+	if !signature.VerifyAggregated(signers.GetBlsKeys(), hash.Bytes(), bls.DomainStateReceiver) {
+		return errInvalidCommitmentSignature
+	}
+
+	return nil
+}
+
 // EncodeAbi contains logic for encoding arbitrary data into ABI format
 func (cm *CommitmentMessageSigned) EncodeAbi() ([]byte, error) {
 	blsVerificationPart, err := precompiled.BlsVerificationABIType.Encode(
@@ -162,12 +271,9 @@ func (cm *CommitmentMessageSigned) DecodeAbi(txData []byte) error {
 
 // getCommitmentMessageSignedTx returns a CommitmentMessageSigned object from a commit state transaction
 func getCommitmentMessageSignedTx(txs []*types.Transaction) (*CommitmentMessageSigned, error) {
-	var commitFn contractsapi.CommitStateReceiverFn
 	for _, tx := range txs {
 		// skip non state CommitmentMessageSigned transactions
-		if tx.Type != types.StateTx ||
-			len(tx.Input) < abiMethodIDLength ||
-			!bytes.Equal(tx.Input[:abiMethodIDLength], commitFn.Sig()) {
+		if !isCommitmentMessageSignedTx(tx) {
 			continue
 		}
 
@@ -183,10 +289,21 @@ func getCommitmentMessageSignedTx(txs []*types.Transaction) (*CommitmentMessageS
 	return nil, nil
 }
 
-// createMerkleTree creates a merkle tree from provided state sync events
-// if only one state sync event is provided, a second, empty leaf will be added to merkle tree
-// so that we can have a commitment with a single state sync event
-func createMerkleTree(stateSyncEvents []*contractsapi.StateSyncedEvent) (*merkle.MerkleTree, error) {
+// isCommitmentMessageSignedTx checks whether the given transaction is a state transaction,
+// which registers a commitment
+func isCommitmentMessageSignedTx(tx *types.Transaction) bool {
+	var commitFn contractsapi.CommitStateReceiverFn
+
+	return tx.Type == types.StateTx &&
+		len(tx.Input) >= abiMethodIDLength &&
+		bytes.Equal(tx.Input[:abiMethodIDLength], commitFn.Sig())
+}
+
+// createMerkleTree creates a merkle tree from provided state sync events, using the given hasher
+// (the default one if nil). If only one state sync event is provided, a second, empty leaf will be added
+// to merkle tree so that we can have a commitment with a single state sync event
+func createMerkleTree(stateSyncEvents []*contractsapi.StateSyncedEvent,
+	hasher MerkleHasher) (*merkle.MerkleTree, error) {
 	stateSyncData := make([][]byte, len(stateSyncEvents))
 
 	for i, sse := range stateSyncEvents {
@@ -198,5 +315,5 @@ func createMerkleTree(stateSyncEvents []*contractsapi.StateSyncedEvent) (*merkle
 		stateSyncData[i] = data
 	}
 
-	return merkle.NewMerkleTree(stateSyncData)
+	return merkle.NewMerkleTreeWithHashing(stateSyncData, hasher.newHash())
 }
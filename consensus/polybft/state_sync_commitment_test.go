@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
+	bls "github.com/0xPolygon/polygon-edge/consensus/polybft/signer"
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
 	"github.com/0xPolygon/polygon-edge/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -19,10 +21,10 @@ func TestCommitmentMessage_Hash(t *testing.T) {
 
 	stateSyncEvents := generateStateSyncEvents(t, eventsCount, 0)
 
-	trie1, err := createMerkleTree(stateSyncEvents)
+	trie1, err := createMerkleTree(stateSyncEvents, nil)
 	require.NoError(t, err)
 
-	trie2, err := createMerkleTree(stateSyncEvents[0 : len(stateSyncEvents)-1])
+	trie2, err := createMerkleTree(stateSyncEvents[0:len(stateSyncEvents)-1], nil)
 	require.NoError(t, err)
 
 	commitmentMessage1 := newTestCommitmentSigned(t, trie1.Hash(), 2, 8)
@@ -45,6 +47,66 @@ func TestCommitmentMessage_Hash(t *testing.T) {
 	require.NotEqual(t, hash3, hash4)
 }
 
+func TestComputeCommitmentHash(t *testing.T) {
+	t.Parallel()
+
+	stateSyncEvents := generateStateSyncEvents(t, 10, 5)
+
+	commitment, err := NewPendingCommitment(3, stateSyncEvents, maxCommitmentSize)
+	require.NoError(t, err)
+
+	expectedHash, err := commitment.Hash()
+	require.NoError(t, err)
+
+	hash, err := ComputeCommitmentHash(5, 14, commitment.Root)
+	require.NoError(t, err)
+	require.Equal(t, expectedHash, hash)
+
+	signedCommitment := &CommitmentMessageSigned{Message: commitment.StateSyncCommitment}
+	signedHash, err := signedCommitment.Hash()
+	require.NoError(t, err)
+	require.Equal(t, hash, signedHash)
+
+	// any change of the input changes the hash
+	otherHash, err := ComputeCommitmentHash(5, 13, commitment.Root)
+	require.NoError(t, err)
+	require.NotEqual(t, hash, otherHash)
+
+	otherHash, err = ComputeCommitmentHash(5, 14, types.StringToHash("0x1"))
+	require.NoError(t, err)
+	require.NotEqual(t, hash, otherHash)
+
+	_, err = ComputeCommitmentHash(14, 5, commitment.Root)
+	require.ErrorIs(t, err, errInvalidCommitmentRange)
+}
+
+func TestNewPendingCommitment_InvalidRange(t *testing.T) {
+	t.Parallel()
+
+	// range within the maximum commitment size
+	commitment, err := NewPendingCommitment(1, generateStateSyncEvents(t, 5, 3), 5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), commitment.StartID.Uint64())
+	require.Equal(t, uint64(7), commitment.EndID.Uint64())
+
+	// over-sized range
+	_, err = NewPendingCommitment(1, generateStateSyncEvents(t, 6, 3), 5)
+	require.ErrorIs(t, err, errInvalidCommitmentRange)
+	require.ErrorContains(t, err, "exceeds maximum commitment size 5")
+
+	// inverted range
+	events := generateStateSyncEvents(t, 3, 3)
+	events[0], events[2] = events[2], events[0]
+
+	_, err = NewPendingCommitment(1, events, 5)
+	require.ErrorIs(t, err, errInvalidCommitmentRange)
+	require.ErrorContains(t, err, "from index 5 is greater than to index 3")
+
+	// no state sync events
+	_, err = NewPendingCommitment(1, nil, 5)
+	require.ErrorIs(t, err, errInvalidCommitmentRange)
+}
+
 func TestCommitmentMessage_ToRegisterCommitmentInputData(t *testing.T) {
 	t.Parallel()
 
@@ -123,7 +185,7 @@ func TestCommitmentMessage_VerifyProof_StateSyncHashNotEqualToProof(t *testing.T
 	)
 
 	stateSyncs := generateStateSyncEvents(t, 5, 0)
-	tree, err := createMerkleTree(stateSyncs)
+	tree, err := createMerkleTree(stateSyncs, nil)
 	require.NoError(t, err)
 
 	leaf, err := stateSyncs[0].EncodeAbi()
@@ -162,7 +224,7 @@ func buildCommitmentAndStateSyncs(t *testing.T, stateSyncsCount int,
 	t.Helper()
 
 	stateSyncEvents := generateStateSyncEvents(t, stateSyncsCount, startIdx)
-	commitment, err := NewPendingCommitment(epoch, stateSyncEvents)
+	commitment, err := NewPendingCommitment(epoch, stateSyncEvents, uint64(stateSyncsCount))
 	require.NoError(t, err)
 
 	commitmentSigned := &CommitmentMessageSigned{
@@ -176,3 +238,54 @@ func buildCommitmentAndStateSyncs(t *testing.T, stateSyncsCount int,
 
 	return commitment, commitmentSigned, stateSyncEvents
 }
+
+func TestVerifyCommitmentSignature(t *testing.T) {
+	t.Parallel()
+
+	validators := validator.NewTestValidators(t, 5)
+	accounts := validators.GetPrivateIdentities()
+	validatorSet := validators.GetPublicIdentities()
+
+	pendingCommitment, err := NewPendingCommitment(1, generateStateSyncEvents(t, 10, 0), maxCommitmentSize)
+	require.NoError(t, err)
+
+	hash, err := pendingCommitment.Hash()
+	require.NoError(t, err)
+
+	newCommitment := func(signature *Signature) *CommitmentMessageSigned {
+		return &CommitmentMessageSigned{
+			Message:      pendingCommitment.StateSyncCommitment,
+			AggSignature: *signature,
+		}
+	}
+
+	t.Run("valid commitment", func(t *testing.T) {
+		t.Parallel()
+
+		commitment := newCommitment(createSignature(t, accounts[:4], hash, bls.DomainStateReceiver))
+		require.NoError(t, VerifyCommitmentSignature(commitment, validatorSet))
+	})
+
+	t.Run("quorum not reached", func(t *testing.T) {
+		t.Parallel()
+
+		commitment := newCommitment(createSignature(t, accounts[:3], hash, bls.DomainStateReceiver))
+		require.ErrorIs(t, VerifyCommitmentSignature(commitment, validatorSet), errQuorumNotReached)
+	})
+
+	t.Run("tampered signature", func(t *testing.T) {
+		t.Parallel()
+
+		// signature created for a different message
+		signature := createSignature(t, accounts[:4], types.StringToHash("0x1"), bls.DomainStateReceiver)
+		commitment := newCommitment(signature)
+		require.ErrorIs(t, VerifyCommitmentSignature(commitment, validatorSet), errInvalidCommitmentSignature)
+	})
+
+	t.Run("wrong signing domain", func(t *testing.T) {
+		t.Parallel()
+
+		commitment := newCommitment(createSignature(t, accounts, hash, bls.DomainCheckpointManager))
+		require.ErrorIs(t, VerifyCommitmentSignature(commitment, validatorSet), errInvalidCommitmentSignature)
+	})
+}
@@ -1,31 +1,142 @@
 package polybft
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"path"
+	"sort"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/bitmap"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
 	polybftProto "github.com/0xPolygon/polygon-edge/consensus/polybft/proto"
 	bls "github.com/0xPolygon/polygon-edge/consensus/polybft/signer"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/wallet"
+	"github.com/0xPolygon/polygon-edge/contracts"
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/0xPolygon/polygon-edge/merkle-tree"
 	"github.com/0xPolygon/polygon-edge/tracker"
 	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/armon/go-metrics"
 	"github.com/hashicorp/go-hclog"
+	lru "github.com/hashicorp/golang-lru"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/umbracle/ethgo"
 	"google.golang.org/protobuf/proto"
 )
 
+// merkleTreeCacheSize is the number of the most recently built commitment merkle trees kept in memory
+const merkleTreeCacheSize = 16
+
+const (
+	// StrictCommitmentGapPolicy is the name of the commitment gap policy,
+	// which refuses to start if stored commitments are behind the on-chain committed index
+	StrictCommitmentGapPolicy = "strict"
+	// RecoverCommitmentGapPolicy is the name of the commitment gap policy,
+	// which fetches the missing commitments from the contract if stored commitments are behind it
+	RecoverCommitmentGapPolicy = "recover"
+)
+
+// maxRecoveredCommitments bounds the number of commitments fetched from the contract,
+// when recovering a gap between the stored commitments and the on-chain committed index
+const maxRecoveredCommitments = 64
+
+// errCommitmentGap represents "stored commitments are behind the on-chain committed index" error message
+var errCommitmentGap = errors.New("stored commitments are behind the on-chain committed index")
+
+// errVoteEpochTooFarAhead represents "vote epoch is too far ahead of the current epoch" error message
+var errVoteEpochTooFarAhead = errors.New("vote epoch is too far ahead of the current epoch")
+
+// maxPrunedProofsChecks is the maximum number of state syncs checked for execution in a single proofs pruning
+const maxPrunedProofsChecks = 256
+
+const (
+	// maxFutureVotesPerHash is the maximum number of buffered votes for a single message hash,
+	// received for an epoch ahead of the current one
+	maxFutureVotesPerHash = 256
+	// maxFutureVotes is the maximum number of buffered votes received for the epochs ahead of the current one
+	maxFutureVotes = 1024
+)
+
+// commitmentTargetGasUsage is the percentage of the block gas limit,
+// which the commitment transaction is expected to use with the adaptive commitment size
+const commitmentTargetGasUsage = 50
+
+// StateSyncProofVersion is the version of the state sync proof encoding, which is bumped whenever it changes
+const StateSyncProofVersion uint8 = 1
+
+// errUnknownStateSyncProofVersion represents "unknown state sync proof version" error message
+var errUnknownStateSyncProofVersion = errors.New("unknown state sync proof version")
+
 type StateSyncProof struct {
 	Proof     []types.Hash
 	StateSync *contractsapi.StateSyncedEvent
+	// Version is the version of the proof encoding (zero for the proofs stored before versioning)
+	Version uint8 `json:",omitempty"`
+}
+
+// GetVersion returns the version of the proof encoding, proofs stored before versioning are of version 1
+func (p *StateSyncProof) GetVersion() uint8 {
+	if p.Version == 0 {
+		return 1
+	}
+
+	return p.Version
+}
+
+// validateVersion checks that the proof encoding is of a known version, so that it is not misinterpreted
+func (p *StateSyncProof) validateVersion() error {
+	if version := p.GetVersion(); version != StateSyncProofVersion {
+		return fmt.Errorf("%w: %d (supported version is %d)",
+			errUnknownStateSyncProofVersion, version, StateSyncProofVersion)
+	}
+
+	return nil
+}
+
+// Verify verifies the proof against the given commitment, using the given merkle hasher.
+// Proofs of an unknown version are rejected.
+func (p *StateSyncProof) Verify(commitment *CommitmentMessageSigned, hasher MerkleHasher) error {
+	if err := p.validateVersion(); err != nil {
+		return err
+	}
+
+	return commitment.VerifyStateSyncProofWithHasher(p.Proof, p.StateSync, hasher)
+}
+
+// EpochBridgeData holds the state syncs committed in an epoch, along with their proofs and the covering commitments,
+// so that they can be verified independently of the node
+type EpochBridgeData struct {
+	Epoch uint64
+	// MerkleHashScheme is the hashing scheme of the commitment merkle trees, needed to verify the proofs
+	MerkleHashScheme string
+	Commitments      []*CommitmentBridgeData
+}
+
+// CommitmentBridgeData holds a signed commitment, along with the proofs of all of its state syncs
+type CommitmentBridgeData struct {
+	Commitment *CommitmentMessageSigned
+	Proofs     []*StateSyncProof
+}
+
+// StateSyncStatus describes pending commitments and whether they can reach quorum
+type StateSyncStatus struct {
+	// PendingCommitments is the number of built commitments which are pending to be submitted
+	PendingCommitments int
+	// QuorumWait is the time the largest pending commitment has been awaiting quorum
+	QuorumWait time.Duration
+	// QuorumReachable indicates that there is no pending commitment awaiting quorum beyond the warning threshold
+	QuorumReachable bool
 }
 
 // StateSyncManager is an interface that defines functions for state sync workflow
@@ -36,6 +147,10 @@ type StateSyncManager interface {
 	GetStateSyncProof(stateSyncID uint64) (types.Proof, error)
 	PostBlock(req *PostBlockRequest) error
 	PostEpoch(req *PostEpochRequest) error
+	PostSprint() error
+	ReorgStarted(event *blockchain.ReorgEvent)
+	Status() *StateSyncStatus
+	VerifyCommitment(commitment *contractsapi.StateSyncCommitment) error
 }
 
 var _ StateSyncManager = (*dummyStateSyncManager)(nil)
@@ -48,6 +163,14 @@ func (n *dummyStateSyncManager) Close()                                        {
 func (n *dummyStateSyncManager) Commitment() (*CommitmentMessageSigned, error) { return nil, nil }
 func (n *dummyStateSyncManager) PostBlock(req *PostBlockRequest) error         { return nil }
 func (n *dummyStateSyncManager) PostEpoch(req *PostEpochRequest) error         { return nil }
+func (n *dummyStateSyncManager) PostSprint() error                             { return nil }
+func (n *dummyStateSyncManager) ReorgStarted(*blockchain.ReorgEvent)           {}
+func (n *dummyStateSyncManager) VerifyCommitment(*contractsapi.StateSyncCommitment) error {
+	return nil
+}
+func (n *dummyStateSyncManager) Status() *StateSyncStatus {
+	return &StateSyncStatus{QuorumReachable: true}
+}
 func (n *dummyStateSyncManager) GetStateSyncProof(stateSyncID uint64) (types.Proof, error) {
 	return types.Proof{}, nil
 }
@@ -61,7 +184,51 @@ type stateSyncConfig struct {
 	topic                 topic
 	key                   *wallet.Key
 	maxCommitmentSize     uint64
+	// minCommitmentSize is the minimum number of state sync events in a commitment
+	// (zero means that the default minimum commitment size is used)
+	minCommitmentSize     uint64
 	numBlockConfirmations uint64
+	rpcTimeout            time.Duration
+	rpcRetries            uint64
+	aggregateSigner       AggregateSigner
+	// merkleHasher is the hasher of commitment merkle trees (the default one if not provided)
+	merkleHasher MerkleHasher
+	// merkleHashScheme is the name of the hashing scheme of merkleHasher (the default one if not provided)
+	merkleHashScheme string
+	// forceSprintCommitments indicates whether a commitment build is attempted at the end of each sprint
+	forceSprintCommitments bool
+	// alignCommitments indicates whether commitments are aligned to sprints, i.e. built only at the end of each sprint
+	// rather than on arrival of new state sync events, so that each sprint produces at most one commitment
+	alignCommitments bool
+	// eventsBatchSize is the number of state sync events saved in a single db transaction
+	// while catching up with the rootchain (zero disables batching)
+	eventsBatchSize uint64
+	// eventsDecodeWorkers is the maximum number of goroutines decoding a batch of logs concurrently
+	// while catching up with the rootchain (zero or one means that logs are decoded serially)
+	eventsDecodeWorkers uint64
+	// resubmissionTimeout is the number of blocks after which a submitted, but not confirmed,
+	// commitment is submitted again (zero disables resubmission)
+	resubmissionTimeout uint64
+	// adaptiveCommitmentSize indicates whether the commitment size is adapted to the gas used
+	// by the recently submitted commitments (within minimum and max commitment size)
+	adaptiveCommitmentSize bool
+	// proofFinalityDepth is the number of blocks which need to be built on top of a block with a commitment,
+	// before proofs of its state syncs are built (zero means that proofs are built immediately)
+	proofFinalityDepth uint64
+	// trackerLogLevel is the log level of the event tracker (hclog.NoLevel keeps the inherited one)
+	trackerLogLevel hclog.Level
+	// quorumWaitWarnThreshold is the time the largest pending commitment can await quorum,
+	// before a warning is logged (zero disables the warning)
+	quorumWaitWarnThreshold time.Duration
+	// commitmentGapPolicy defines how a gap between the stored commitments and the on-chain committed index,
+	// found on startup, is handled (it is only logged if no policy is provided)
+	commitmentGapPolicy string
+	// maxVoteEpochLead is the maximum number of epochs a received vote can be ahead of the current epoch
+	// (zero means that the default maximum is used)
+	maxVoteEpochLead uint64
+	// proofPruningInterval is the number of epochs between prunings of the proofs of executed state syncs
+	// (zero disables pruning)
+	proofPruningInterval uint64
 }
 
 var _ StateSyncManager = (*stateSyncManager)(nil)
@@ -75,12 +242,78 @@ type stateSyncManager struct {
 	config  *stateSyncConfig
 	closeCh chan struct{}
 
+	// merkleTreeCache caches recently built commitment merkle trees (merkleTreeCacheKey -> *merkle.MerkleTree),
+	// caching is disabled if it is nil
+	merkleTreeCache *lru.Cache
+
 	// per epoch fields
 	lock               sync.RWMutex
 	pendingCommitments []*PendingCommitment
 	validatorSet       validator.ValidatorSet
 	epoch              uint64
 	nextCommittedIndex uint64
+	// generation is incremented on each epoch change,
+	// so that commitments built for the previous epoch get abandoned
+	generation uint64
+	// submittedCommitment is the last submitted commitment, which registration is not yet confirmed
+	submittedCommitment *submittedCommitment
+	// commitmentSize is the maximum number of state syncs in a commitment, derived from the gas used
+	// by the last submitted commitment (zero means that max commitment size is used)
+	commitmentSize uint64
+	// paused indicates that commitment building is paused (state sync events are still tracked and stored)
+	paused bool
+	// reorgInProgress indicates that the chain got reorganized and commitment building is deferred
+	// until the head of the new canonical chain (reorgHead) is processed
+	reorgInProgress bool
+	// reorgHead is the number and hash of the head of the new canonical chain of the reorg in progress
+	reorgHead blockRef
+	// lastProcessedBlock is the number and hash of the last block processed by PostBlock
+	lastProcessedBlock blockRef
+	// quorumNotReachedCount is the number of times a pending commitment did not reach quorum in the current epoch
+	quorumNotReachedCount atomic.Uint64
+
+	// pendingProofs are submitted commitments (block number -> commitment), whose proofs are not built
+	// until their blocks reach the proof finality depth. They are persisted, so that they survive a restart.
+	pendingProofs     map[uint64]*CommitmentMessageSigned
+	pendingProofsLock sync.Mutex
+
+	// eventStream delivers newly inserted state sync events to the subscribers
+	eventStream stateSyncEventStream
+
+	// proofStream delivers newly built state sync proofs to the subscribers
+	proofStream stateSyncProofStream
+
+	// lastVoteTimes holds the time of the last vote received from each validator of the current validator set
+	lastVoteTimes     map[types.Address]time.Time
+	lastVoteTimesLock sync.Mutex
+
+	// futureVotes are the votes received for an epoch ahead of the current one (i.e. before PostEpoch
+	// switched to it), which are replayed once PostEpoch sets the validator set of their epoch (in arrival order)
+	futureVotes     []*TransportMessage
+	futureVotesLock sync.Mutex
+
+	// quorumWait tracks how long the largest pending commitment has been awaiting quorum
+	quorumWait     quorumWait
+	quorumWaitLock sync.Mutex
+}
+
+// quorumWait holds the time since which a (largest pending) commitment has been awaiting quorum
+type quorumWait struct {
+	commitment *PendingCommitment
+	since      time.Time
+	// warned indicates that the waiting time exceeded the warning threshold, and it was already logged
+	warned bool
+	// hasQuorum indicates whether the commitment had quorum when it was last checked
+	hasQuorum bool
+}
+
+// submittedCommitment is a commitment which was included in a block,
+// but whose registration was not confirmed by the StateReceiver contract (NewCommitment event)
+type submittedCommitment struct {
+	commitment  *CommitmentMessageSigned
+	blockNumber uint64
+	// resubmit indicates that the confirmation timed out, so the commitment should be submitted again
+	resubmit bool
 }
 
 // topic is an interface for p2p message gossiping
@@ -90,17 +323,54 @@ type topic interface {
 }
 
 // newStateSyncManager creates a new instance of state sync manager
-func newStateSyncManager(logger hclog.Logger, state *State, config *stateSyncConfig) *stateSyncManager {
+func newStateSyncManager(logger hclog.Logger, state *State, cfg *stateSyncConfig) *stateSyncManager {
+	// defaults are applied to a copy, so that the configuration of the caller is not modified
+	config := *cfg
+
+	if config.aggregateSigner == nil {
+		config.aggregateSigner = &blsAggregateSigner{}
+	}
+
+	if config.merkleHasher == nil {
+		config.merkleHasher = defaultMerkleHasher
+	}
+
+	if config.minCommitmentSize < minCommitmentSize {
+		config.minCommitmentSize = minCommitmentSize
+	}
+
+	if config.maxVoteEpochLead == 0 {
+		config.maxVoteEpochLead = defaultMaxVoteEpochLead
+	}
+
+	// error is returned only for a non-positive cache size
+	merkleTreeCache, _ := lru.New(merkleTreeCacheSize)
+
 	return &stateSyncManager{
-		logger:  logger,
-		state:   state,
-		config:  config,
-		closeCh: make(chan struct{}),
+		logger:          logger,
+		state:           state,
+		config:          &config,
+		closeCh:         make(chan struct{}),
+		merkleTreeCache: merkleTreeCache,
+		pendingProofs:   make(map[uint64]*CommitmentMessageSigned),
+		lastVoteTimes:   make(map[types.Address]time.Time),
 	}
 }
 
 // Init subscribes to bridge topics (getting votes) and start the event tracker routine
 func (s *stateSyncManager) Init() error {
+	if err := s.loadPendingCommitments(); err != nil {
+		return fmt.Errorf("failed to load pending commitments. Error: %w", err)
+	}
+
+	if err := s.loadPendingProofs(); err != nil {
+		return fmt.Errorf("failed to load pending proofs. Error: %w", err)
+	}
+
+	if err := s.loadCommitmentSize(); err != nil {
+		return fmt.Errorf("failed to load commitment size. Error: %w", err)
+	}
+
 	if err := s.initTracker(); err != nil {
 		return fmt.Errorf("failed to init event tracker. Error: %w", err)
 	}
@@ -116,6 +386,102 @@ func (s *stateSyncManager) Close() {
 	close(s.closeCh)
 }
 
+// loadPendingCommitments reloads pending commitments persisted before the node restart,
+// so that votes already gathered for them are not lost. Loaded commitments are re-validated in PostEpoch.
+func (s *stateSyncManager) loadPendingCommitments() error {
+	commitments, err := s.state.StateSyncStore.getPendingCommitments()
+	if err != nil {
+		return err
+	}
+
+	pendingCommitments := make([]*PendingCommitment, 0, len(commitments))
+
+	for _, commitment := range commitments {
+		stateSyncEvents, err := s.state.StateSyncStore.getStateSyncEventsForCommitment(
+			commitment.StartID.Uint64(), commitment.EndID.Uint64())
+		if err != nil {
+			s.logger.Warn("could not reload pending commitment", "from", commitment.StartID,
+				"to", commitment.EndID, "err", err)
+
+			continue
+		}
+
+		rebuiltCommitment, err := NewPendingCommitmentWithHasher(commitment.Epoch, stateSyncEvents,
+			s.config.maxCommitmentSize, s.merkleHasher())
+		if err != nil {
+			if errors.Is(err, errInvalidCommitmentRange) {
+				s.logger.Warn("could not reload pending commitment", "from", commitment.StartID,
+					"to", commitment.EndID, "err", err)
+
+				continue
+			}
+
+			return err
+		}
+
+		if rebuiltCommitment.Root != commitment.Root {
+			s.logger.Warn("could not reload pending commitment, root mismatch", "from", commitment.StartID,
+				"to", commitment.EndID)
+
+			continue
+		}
+
+		pendingCommitments = append(pendingCommitments, rebuiltCommitment)
+	}
+
+	s.lock.Lock()
+	s.pendingCommitments = pendingCommitments
+	s.lock.Unlock()
+
+	s.logger.Debug("loaded pending commitments", "count", len(pendingCommitments))
+
+	return nil
+}
+
+// loadPendingProofs reloads submitted commitments persisted before the node restart,
+// whose proofs were not built yet, since their blocks did not reach the proof finality depth
+func (s *stateSyncManager) loadPendingProofs() error {
+	pendingProofs, err := s.state.StateSyncStore.getPendingProofs()
+	if err != nil {
+		return err
+	}
+
+	s.pendingProofsLock.Lock()
+	s.pendingProofs = pendingProofs
+	s.pendingProofsLock.Unlock()
+
+	s.logger.Debug("loaded pending proofs", "count", len(pendingProofs))
+
+	return nil
+}
+
+// retainValidPendingCommitments keeps only the pending commitments (e.g. the ones reloaded after restart)
+// which belong to the current epoch and start at the next committed index, and persists the result.
+// Must be called while holding the lock.
+func (s *stateSyncManager) retainValidPendingCommitments() error {
+	validCommitments := make([]*PendingCommitment, 0, len(s.pendingCommitments))
+
+	for _, commitment := range s.pendingCommitments {
+		if commitment.Epoch == s.epoch && commitment.StartID.Uint64() == s.nextCommittedIndex {
+			validCommitments = append(validCommitments, commitment)
+		}
+	}
+
+	s.pendingCommitments = validCommitments
+
+	if err := s.state.StateSyncStore.removePendingCommitments(); err != nil {
+		return fmt.Errorf("failed to remove pending commitments: %w", err)
+	}
+
+	for _, commitment := range validCommitments {
+		if err := s.state.StateSyncStore.insertPendingCommitment(commitment); err != nil {
+			return fmt.Errorf("failed to insert pending commitment: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // initTracker starts a new event tracker (to receive new state sync events)
 func (s *stateSyncManager) initTracker() error {
 	ctx, cancelFn := context.WithCancel(context.Background())
@@ -127,7 +493,10 @@ func (s *stateSyncManager) initTracker() error {
 		s,
 		s.config.numBlockConfirmations,
 		s.config.stateSenderStartBlock,
-		s.logger)
+		s.logger,
+		tracker.WithRPCTimeout(s.config.rpcTimeout),
+		tracker.WithRPCRetries(s.config.rpcRetries),
+		tracker.WithLogLevel(s.config.trackerLogLevel))
 
 	go func() {
 		<-s.closeCh
@@ -168,15 +537,38 @@ func (s *stateSyncManager) saveVote(msg *TransportMessage) error {
 	valSet := s.validatorSet
 	s.lock.RUnlock()
 
-	if valSet == nil || msg.EpochNumber != epoch {
-		// Epoch metadata is undefined or received a message for the irrelevant epoch
+	if msg.EpochNumber > epoch+s.config.maxVoteEpochLead {
+		// reject votes before they reach the store, so that a peer can not make us
+		// allocate buckets for arbitrary future epochs
+		return fmt.Errorf("%w: vote epoch %d, current epoch %d",
+			errVoteEpochTooFarAhead, msg.EpochNumber, epoch)
+	}
+
+	if msg.EpochNumber < epoch {
+		// received a message for the irrelevant epoch
 		return nil
 	}
 
-	if err := s.verifyVoteSignature(valSet, types.StringToAddress(msg.From), msg.Signature, msg.Hash); err != nil {
+	if valSet == nil || msg.EpochNumber > epoch {
+		// validator set of the message epoch is not known yet, so the vote is replayed once it is
+		buffered, err := s.bufferFutureVote(msg)
+		if err != nil || buffered {
+			return err
+		}
+
+		// epoch has changed meanwhile
+		return s.saveVote(msg)
+	}
+
+	signer := types.StringToAddress(msg.From)
+	if err := s.verifyVoteSignature(valSet, signer, msg.Signature, msg.Hash); err != nil {
 		return fmt.Errorf("error verifying vote signature: %w", err)
 	}
 
+	s.lastVoteTimesLock.Lock()
+	s.lastVoteTimes[signer] = time.Now().UTC()
+	s.lastVoteTimesLock.Unlock()
+
 	msgVote := &MessageSignature{
 		From:      msg.From,
 		Signature: msg.Signature,
@@ -197,6 +589,108 @@ func (s *stateSyncManager) saveVote(msg *TransportMessage) error {
 	return nil
 }
 
+// bufferFutureVote buffers the vote received for an epoch whose validator set is not known yet.
+// It returns false if the epoch has changed in the meantime, so that the vote can be saved right away.
+// Senders of the future votes can not be verified, so instead of rejecting new votes once the buffer is full,
+// the oldest buffered votes (of the same message hash, if it reached its quota) are evicted.
+// That way, a peer flooding the buffer with votes of forged senders can not keep the genuine votes out of it.
+// Votes of the current validators are verified before being buffered, so that they can not be impersonated.
+func (s *stateSyncManager) bufferFutureVote(msg *TransportMessage) (bool, error) {
+	s.futureVotesLock.Lock()
+	defer s.futureVotesLock.Unlock()
+
+	// epoch is checked again while holding the buffer lock, since PostEpoch replays the buffer after changing it
+	s.lock.RLock()
+	valSet := s.validatorSet
+	isFutureVote := valSet == nil || msg.EpochNumber > s.epoch
+	s.lock.RUnlock()
+
+	if !isFutureVote {
+		return false, nil
+	}
+
+	signer := types.StringToAddress(msg.From)
+
+	if valSet != nil && valSet.Includes(signer) {
+		if err := s.verifyVoteSignature(valSet, signer, msg.Signature, msg.Hash); err != nil {
+			return false, fmt.Errorf("error verifying vote signature: %w", err)
+		}
+	}
+
+	evictIdx := -1
+	hashVotes := 0
+
+	for i, vote := range s.futureVotes {
+		if vote.EpochNumber == msg.EpochNumber && bytes.Equal(vote.Hash, msg.Hash) {
+			if hashVotes == 0 {
+				evictIdx = i
+			}
+
+			hashVotes++
+		}
+	}
+
+	if hashVotes < maxFutureVotesPerHash {
+		evictIdx = -1
+
+		if len(s.futureVotes) >= maxFutureVotes {
+			evictIdx = 0
+		}
+	}
+
+	if evictIdx >= 0 {
+		s.futureVotes = append(s.futureVotes[:evictIdx], s.futureVotes[evictIdx+1:]...)
+
+		metrics.IncrCounter([]string{"bridge", "future_votes_evicted"}, 1)
+	}
+
+	s.futureVotes = append(s.futureVotes, msg)
+
+	return true, nil
+}
+
+// replayFutureVotes saves the buffered votes once the epoch has changed.
+// Votes of the epochs still ahead are buffered again, while the votes of the past epochs are discarded.
+func (s *stateSyncManager) replayFutureVotes() {
+	s.futureVotesLock.Lock()
+	votes := s.futureVotes
+	s.futureVotes = nil
+	s.futureVotesLock.Unlock()
+
+	for _, msg := range votes {
+		if err := s.saveVote(msg); err != nil {
+			s.logger.Warn("failed to deliver buffered vote", "epoch", msg.EpochNumber, "sender", msg.From,
+				"error", err)
+		}
+	}
+}
+
+// LastVoteTimes returns the time of the last vote received from each validator of the current validator set.
+// Validators which did not vote since they joined the validator set (or since the node started) are absent.
+func (s *stateSyncManager) LastVoteTimes() map[types.Address]time.Time {
+	s.lastVoteTimesLock.Lock()
+	defer s.lastVoteTimesLock.Unlock()
+
+	lastVoteTimes := make(map[types.Address]time.Time, len(s.lastVoteTimes))
+	for addr, lastVoteTime := range s.lastVoteTimes {
+		lastVoteTimes[addr] = lastVoteTime
+	}
+
+	return lastVoteTimes
+}
+
+// retainValidatorsLastVoteTimes removes last vote times of the validators which are not in the given validator set
+func (s *stateSyncManager) retainValidatorsLastVoteTimes(validatorSet validator.ValidatorSet) {
+	s.lastVoteTimesLock.Lock()
+	defer s.lastVoteTimesLock.Unlock()
+
+	for addr := range s.lastVoteTimes {
+		if !validatorSet.Includes(addr) {
+			delete(s.lastVoteTimes, addr)
+		}
+	}
+}
+
 // Verifies signature of the message against the public key of the signer and checks if the signer is a validator
 func (s *stateSyncManager) verifyVoteSignature(valSet validator.ValidatorSet, signer types.Address, signature []byte,
 	hash []byte) error {
@@ -205,43 +699,33 @@ func (s *stateSyncManager) verifyVoteSignature(valSet validator.ValidatorSet, si
 		return fmt.Errorf("unable to resolve validator %s", signer)
 	}
 
-	unmarshaledSignature, err := bls.UnmarshalSignature(signature)
-	if err != nil {
-		return fmt.Errorf("failed to unmarshal signature from signer %s, %w", signer.String(), err)
-	}
-
-	if !unmarshaledSignature.Verify(validator.BlsKey, hash, bls.DomainStateReceiver) {
-		return fmt.Errorf("incorrect signature from %s", signer)
-	}
-
-	return nil
+	return s.config.aggregateSigner.Verify(validator, signature, hash, bls.DomainStateReceiver)
 }
 
 // AddLog saves the received log from event tracker if it matches a state sync event ABI
 func (s *stateSyncManager) AddLog(eventLog *ethgo.Log) {
-	event := &contractsapi.StateSyncedEvent{}
-
-	doesMatch, err := event.ParseLog(eventLog)
-	if !doesMatch {
+	event := s.decodeStateSyncLog(eventLog)
+	if event == nil {
 		return
 	}
 
-	s.logger.Info(
-		"Add State sync event",
-		"block", eventLog.BlockNumber,
-		"hash", eventLog.TransactionHash,
-		"index", eventLog.LogIndex,
-	)
-
+	isInserted, err := s.state.StateSyncStore.insertStateSyncEvent(event)
 	if err != nil {
-		s.logger.Error("could not decode state sync event", "err", err)
+		s.logger.Error("could not save state sync event to boltDb", "err", err)
 
 		return
 	}
 
-	if err := s.state.StateSyncStore.insertStateSyncEvent(event); err != nil {
-		s.logger.Error("could not save state sync event to boltDb", "err", err)
+	if !isInserted {
+		s.logger.Debug("state sync event already saved, skipping it", "stateSyncID", event.ID)
+
+		return
+	}
+
+	s.eventStream.push(event)
 
+	if s.config.alignCommitments {
+		// commitment is built at the end of the sprint
 		return
 	}
 
@@ -250,116 +734,353 @@ func (s *stateSyncManager) AddLog(eventLog *ethgo.Log) {
 	}
 }
 
-// Commitment returns a commitment to be submitted if there is a pending commitment with quorum
-func (s *stateSyncManager) Commitment() (*CommitmentMessageSigned, error) {
-	s.lock.RLock()
-	defer s.lock.RUnlock()
+// InsertValidatedStateSyncEvent injects a state sync event emitted in the given rootchain block, which was missed
+// by the event tracker, and processes it the same way as the events received from the event tracker
+// (the event is pushed to the subscribers and a commitment is built, unless commitments are aligned to sprints).
+func (s *stateSyncManager) InsertValidatedStateSyncEvent(event *contractsapi.StateSyncedEvent,
+	rootchainBlock uint64) error {
+	isInserted, err := s.state.InsertValidatedStateSyncEvent(event, rootchainBlock)
+	if err != nil {
+		return err
+	}
 
-	var largestCommitment *CommitmentMessageSigned
+	if !isInserted {
+		s.logger.Debug("state sync event already saved, skipping it", "stateSyncID", event.ID)
 
-	// we start from the end, since last pending commitment is the largest one
-	for i := len(s.pendingCommitments) - 1; i >= 0; i-- {
-		commitment := s.pendingCommitments[i]
-		aggregatedSignature, publicKeys, err := s.getAggSignatureForCommitmentMessage(commitment)
+		return nil
+	}
 
-		if err != nil {
-			if errors.Is(err, errQuorumNotReached) {
-				// a valid case, commitment has no quorum, we should not return an error
-				s.logger.Debug("can not submit a commitment, quorum not reached",
-					"from", commitment.StartID.Uint64(),
-					"to", commitment.EndID.Uint64())
+	s.eventStream.push(event)
 
-				continue
-			}
+	if s.config.alignCommitments {
+		// commitment is built at the end of the sprint
+		return nil
+	}
 
-			return nil, err
-		}
+	return s.buildCommitment()
+}
 
-		largestCommitment = &CommitmentMessageSigned{
-			Message:      commitment.StateSyncCommitment,
-			AggSignature: aggregatedSignature,
-			PublicKeys:   publicKeys,
+// AddLogs saves multiple logs received at once from event tracker (while it catches up with the rootchain).
+// If batching is enabled, logs are decoded (concurrently, if configured) and matching state sync events are saved
+// in their original order in batches, using a single db transaction per batch, and a single commitment is built
+// at the end. Otherwise, logs are processed one by one.
+func (s *stateSyncManager) AddLogs(eventLogs []*ethgo.Log) {
+	if s.config.eventsBatchSize == 0 {
+		for _, eventLog := range eventLogs {
+			s.AddLog(eventLog)
 		}
 
-		break
+		return
 	}
 
-	return largestCommitment, nil
-}
+	events := s.decodeStateSyncLogs(eventLogs)
+	insertedCount := 0
 
-// getAggSignatureForCommitmentMessage checks if pending commitment has quorum,
-// and if it does, aggregates the signatures
-func (s *stateSyncManager) getAggSignatureForCommitmentMessage(
-	commitment *PendingCommitment) (Signature, [][]byte, error) {
-	validatorSet := s.validatorSet
+	for start := 0; start < len(events); start += int(s.config.eventsBatchSize) {
+		end := start + int(s.config.eventsBatchSize)
+		if end > len(events) {
+			end = len(events)
+		}
 
-	validatorAddrToIndex := make(map[string]int, validatorSet.Len())
-	validatorsMetadata := validatorSet.Accounts()
+		insertedEvents, err := s.state.StateSyncStore.insertNewStateSyncEvents(events[start:end])
+		if err != nil {
+			s.logger.Error("could not save state sync events to boltDb", "err", err)
 
-	for i, validator := range validatorsMetadata {
-		validatorAddrToIndex[validator.Address.String()] = i
+			break
+		}
+
+		s.eventStream.push(insertedEvents...)
+
+		insertedCount += len(insertedEvents)
 	}
 
-	commitmentHash, err := commitment.Hash()
-	if err != nil {
-		return Signature{}, nil, err
+	if insertedCount == 0 || s.config.alignCommitments {
+		return
 	}
 
-	// get all the votes from the database for this commitment
-	votes, err := s.state.StateSyncStore.getMessageVotes(commitment.Epoch, commitmentHash.Bytes())
-	if err != nil {
-		return Signature{}, nil, err
+	if err := s.buildCommitment(); err != nil {
+		s.logger.Error("could not build a commitment on arrival of new state syncs", "err", err,
+			"stateSyncs", insertedCount)
 	}
+}
 
-	var signatures bls.Signatures
+// SubscribeStateSyncEvents creates a subscription to state sync events, which delivers each newly inserted
+// state sync event. Subscription buffer is bounded and the oldest events are dropped if the subscriber is slow.
+func (s *stateSyncManager) SubscribeStateSyncEvents() StateSyncSubscription {
+	return s.eventStream.subscribe()
+}
 
-	publicKeys := make([][]byte, 0)
-	bmap := bitmap.Bitmap{}
-	signers := make(map[types.Address]struct{}, 0)
+// SubscribeStateSyncProofs creates a subscription to state sync proofs, which delivers each proof as soon as it is
+// built, in the state sync index order, so that the proofs can be relayed before the whole commitment is persisted.
+// Subscription buffer is bounded and the oldest proofs are dropped if the subscriber is slow.
+func (s *stateSyncManager) SubscribeStateSyncProofs() StateSyncProofSubscription {
+	return s.proofStream.subscribe()
+}
 
-	for _, vote := range votes {
-		index, exists := validatorAddrToIndex[vote.From]
-		if !exists {
-			continue // don't count this vote, because it does not belong to validator
-		}
+// decodeStateSyncLogs decodes given logs into state sync events, keeping the order of the logs.
+// Logs are split into contiguous chunks, decoded in parallel by up to eventsDecodeWorkers goroutines.
+func (s *stateSyncManager) decodeStateSyncLogs(eventLogs []*ethgo.Log) []*contractsapi.StateSyncedEvent {
+	decoded := make([]*contractsapi.StateSyncedEvent, len(eventLogs))
 
-		signature, err := bls.UnmarshalSignature(vote.Signature)
-		if err != nil {
-			return Signature{}, nil, err
+	workers := int(s.config.eventsDecodeWorkers)
+	if workers > len(eventLogs) {
+		workers = len(eventLogs)
+	}
+
+	if workers <= 1 {
+		for i, eventLog := range eventLogs {
+			decoded[i] = s.decodeStateSyncLog(eventLog)
 		}
+	} else {
+		chunkSize := (len(eventLogs) + workers - 1) / workers
 
-		bmap.Set(uint64(index))
+		var wg sync.WaitGroup
 
-		signatures = append(signatures, signature)
-		publicKeys = append(publicKeys, validatorsMetadata[index].BlsKey.Marshal())
-		signers[types.StringToAddress(vote.From)] = struct{}{}
-	}
+		for from := 0; from < len(eventLogs); from += chunkSize {
+			to := from + chunkSize
+			if to > len(eventLogs) {
+				to = len(eventLogs)
+			}
 
-	if !validatorSet.HasQuorum(signers) {
-		return Signature{}, nil, errQuorumNotReached
-	}
+			wg.Add(1)
 
-	aggregatedSignature, err := signatures.Aggregate().Marshal()
-	if err != nil {
-		return Signature{}, nil, err
+			go func(from, to int) {
+				defer wg.Done()
+
+				for i := from; i < to; i++ {
+					decoded[i] = s.decodeStateSyncLog(eventLogs[i])
+				}
+			}(from, to)
+		}
+
+		wg.Wait()
 	}
 
-	result := Signature{
-		AggregatedSignature: aggregatedSignature,
-		Bitmap:              bmap,
+	events := make([]*contractsapi.StateSyncedEvent, 0, len(decoded))
+
+	for _, event := range decoded {
+		if event != nil {
+			events = append(events, event)
+		}
 	}
 
-	return result, publicKeys, nil
+	return events
 }
 
-// PostEpoch notifies the state sync manager that an epoch has changed,
+// decodeStateSyncLog decodes given log into state sync event.
+// It returns nil if the log is not a state sync event or it can not be decoded.
+func (s *stateSyncManager) decodeStateSyncLog(eventLog *ethgo.Log) *contractsapi.StateSyncedEvent {
+	event := &contractsapi.StateSyncedEvent{}
+
+	// indexed event parameters (id, sender and receiver) are decoded from the log topics,
+	// while the non-indexed ones (data) are decoded from the log data
+	doesMatch, err := event.ParseLog(eventLog)
+	if !doesMatch {
+		return nil
+	}
+
+	if err != nil {
+		s.logger.Error("could not decode state sync event", "err", err,
+			"block", eventLog.BlockNumber, "hash", eventLog.TransactionHash, "index", eventLog.LogIndex)
+
+		return nil
+	}
+
+	s.logger.Info(
+		"Add State sync event",
+		"block", eventLog.BlockNumber,
+		"hash", eventLog.TransactionHash,
+		"index", eventLog.LogIndex,
+		"stateSyncID", event.ID,
+		"sender", event.Sender,
+		"receiver", event.Receiver,
+	)
+
+	return event
+}
+
+// Commitment returns a commitment to be submitted if there is a pending commitment with quorum
+func (s *stateSyncManager) Commitment() (*CommitmentMessageSigned, error) {
+	s.lock.RLock()
+
+	if s.submittedCommitment != nil && s.submittedCommitment.resubmit {
+		// previous submission was not confirmed in time, so it takes precedence over the pending commitments
+		resubmittedCommitment := s.submittedCommitment.commitment
+		s.lock.RUnlock()
+
+		return resubmittedCommitment, nil
+	}
+
+	// take a snapshot of the per epoch fields, so that votes are aggregated without holding the lock.
+	// Pending commitments are only appended to (or replaced as a whole), so the snapshot elements never change.
+	pendingCommitments := s.pendingCommitments
+	validatorSet := s.validatorSet
+	nextCommittedIndex := s.nextCommittedIndex
+	s.lock.RUnlock()
+
+	if len(pendingCommitments) == 0 || validatorSet == nil {
+		return nil, nil
+	}
+
+	var (
+		largestCommitment *CommitmentMessageSigned
+		hasStale          bool
+	)
+
+	// we start from the end, since last pending commitment is the largest one
+	for i := len(pendingCommitments) - 1; i >= 0; i-- {
+		commitment := pendingCommitments[i]
+		if commitment.StartID.Uint64() != nextCommittedIndex {
+			// registering such commitment would register an inconsistent range of state syncs
+			s.logger.Warn("skipping stale commitment", "err", fmt.Errorf("%w: commitment %d-%d "+
+				"does not start at the next committed index %d", errStaleCommitment,
+				commitment.StartID.Uint64(), commitment.EndID.Uint64(), nextCommittedIndex))
+
+			hasStale = true
+
+			continue
+		}
+
+		aggregatedSignature, publicKeys, err := s.getAggSignatureForCommitmentMessage(
+			commitment, validatorSet, i == len(pendingCommitments)-1)
+
+		if err != nil {
+			if errors.Is(err, errQuorumNotReached) {
+				// a valid case, commitment has no quorum, we should not return an error
+				continue
+			}
+
+			return nil, err
+		}
+
+		largestCommitment = &CommitmentMessageSigned{
+			Message:      commitment.StateSyncCommitment,
+			AggSignature: aggregatedSignature,
+			PublicKeys:   publicKeys,
+		}
+
+		break
+	}
+
+	if hasStale {
+		s.discardStaleCommitments()
+	}
+
+	return largestCommitment, nil
+}
+
+// discardStaleCommitments removes the pending commitments which do not start at the next committed index
+// (from memory and from the store), so that they are not considered for registration again
+func (s *stateSyncManager) discardStaleCommitments() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.retainValidPendingCommitments(); err != nil {
+		s.logger.Error("failed to discard stale commitments", "err", err)
+	}
+}
+
+// getAggSignatureForCommitmentMessage checks if pending commitment has quorum in the given validator set,
+// and if it does, aggregates the signatures. isLargest indicates that the commitment is the largest pending one.
+func (s *stateSyncManager) getAggSignatureForCommitmentMessage(commitment *PendingCommitment,
+	validatorSet validator.ValidatorSet, isLargest bool) (Signature, [][]byte, error) {
+	validatorAddrToIndex := make(map[string]int, validatorSet.Len())
+	validatorsMetadata := validatorSet.Accounts()
+
+	for i, validator := range validatorsMetadata {
+		validatorAddrToIndex[validator.Address.String()] = i
+	}
+
+	commitmentHash, err := commitment.Hash()
+	if err != nil {
+		return Signature{}, nil, err
+	}
+
+	// get all the votes from the database for this commitment
+	votes, err := s.state.StateSyncStore.getMessageVotes(commitment.Epoch, commitmentHash.Bytes())
+	if err != nil {
+		return Signature{}, nil, err
+	}
+
+	var signatures [][]byte
+
+	publicKeys := make([][]byte, 0)
+	bmap := bitmap.Bitmap{}
+	signers := make(map[types.Address]struct{}, 0)
+
+	for _, vote := range votes {
+		index, exists := validatorAddrToIndex[vote.From]
+		if !exists {
+			continue // don't count this vote, because it does not belong to validator
+		}
+
+		bmap.Set(uint64(index))
+
+		signatures = append(signatures, vote.Signature)
+		publicKeys = append(publicKeys, validatorsMetadata[index].BlsKey.Marshal())
+		signers[types.StringToAddress(vote.From)] = struct{}{}
+	}
+
+	hasQuorum := validatorSet.HasQuorum(signers)
+
+	if isLargest {
+		// number of signatures collected for the largest pending commitment
+		metrics.SetGauge([]string{"bridge", "commitment_signatures"}, float32(len(signers)))
+
+		wait, exceeded := s.updateQuorumWait(commitment, hasQuorum)
+		metrics.SetGauge([]string{"bridge", "commitment_quorum_wait"}, float32(wait.Seconds()))
+
+		if exceeded {
+			s.logger.Warn("commitment is awaiting quorum for too long",
+				"epoch", commitment.Epoch,
+				"from", commitment.StartID.Uint64(),
+				"to", commitment.EndID.Uint64(),
+				"wait", wait,
+				"threshold", s.config.quorumWaitWarnThreshold,
+				"signatures", len(signers),
+				"validators", validatorSet.Len())
+		}
+	}
+
+	if !hasQuorum {
+		quorumNotReachedCount := s.quorumNotReachedCount.Add(1)
+
+		metrics.IncrCounter([]string{"bridge", "commitment_quorum_not_reached"}, 1)
+		metrics.SetGauge([]string{"bridge", "epoch_commitment_quorum_not_reached"}, float32(quorumNotReachedCount))
+
+		s.logger.Debug("can not submit a commitment, quorum not reached",
+			"epoch", commitment.Epoch,
+			"from", commitment.StartID.Uint64(),
+			"to", commitment.EndID.Uint64(),
+			"signatures", len(signers),
+			"validators", validatorSet.Len(),
+			"epochQuorumNotReachedCount", quorumNotReachedCount)
+
+		return Signature{}, nil, errQuorumNotReached
+	}
+
+	aggregatedSignature, err := s.config.aggregateSigner.Aggregate(signatures)
+	if err != nil {
+		return Signature{}, nil, err
+	}
+
+	result := Signature{
+		AggregatedSignature: aggregatedSignature,
+		Bitmap:              bmap,
+	}
+
+	return result, publicKeys, nil
+}
+
+// PostEpoch notifies the state sync manager that an epoch has changed,
 // so that it can discard any previous epoch commitments, and build a new one (since validator set changed)
 func (s *stateSyncManager) PostEpoch(req *PostEpochRequest) error {
 	s.lock.Lock()
 
-	s.pendingCommitments = nil
 	s.validatorSet = req.ValidatorSet
 	s.epoch = req.NewEpochID
+	s.generation++
+	s.quorumNotReachedCount.Store(0)
 
 	// build a new commitment at the end of the epoch
 	nextCommittedIndex, err := req.SystemState.GetNextCommittedIndex()
@@ -369,44 +1090,613 @@ func (s *stateSyncManager) PostEpoch(req *PostEpochRequest) error {
 		return err
 	}
 
-	s.nextCommittedIndex = nextCommittedIndex
+	if err := s.reconcileNextCommittedIndex(nextCommittedIndex); err != nil {
+		s.lock.Unlock()
+
+		return err
+	}
+
+	// previous epoch submission can not be resubmitted, since it is signed by the previous validator set,
+	// and on-chain next committed index got reconciled anyway
+	s.submittedCommitment = nil
+
+	// discard previous epoch commitments, while keeping the current epoch ones (reloaded after restart)
+	if err := s.retainValidPendingCommitments(); err != nil {
+		s.lock.Unlock()
+
+		return err
+	}
+
+	s.lock.Unlock()
+
+	s.retainValidatorsLastVoteTimes(req.ValidatorSet)
+	s.replayFutureVotes()
+
+	if s.config.proofPruningInterval > 0 && req.NewEpochID%s.config.proofPruningInterval == 0 {
+		// proofs can be rebuilt from the retained events, so failing to prune them is not critical
+		if err := s.pruneExecutedProofs(req.SystemState, maxPrunedProofsChecks); err != nil {
+			s.logger.Warn("[PostEpoch] Failed to prune executed state sync proofs", "epoch", req.NewEpochID, "err", err)
+		}
+	}
+
+	if err := s.buildCommitment(); err != nil {
+		s.logger.Error("[PostEpoch] Failed to build commitment", "epoch", req.NewEpochID, "err", err)
+		metrics.IncrCounter([]string{"bridge", "epoch_commitment_build_failed"}, 1)
+
+		return err
+	}
+
+	s.lock.RLock()
+	noCommitment := len(s.pendingCommitments) == 0 && !s.paused
+	nextCommittedIndex = s.nextCommittedIndex
+	s.lock.RUnlock()
+
+	if noCommitment {
+		// not enough new state sync events, so the epoch passed without bridge activity
+		s.logger.Info("[PostEpoch] No commitment built, not enough new state sync events",
+			"epoch", req.NewEpochID, "nextCommittedIndex", nextCommittedIndex)
+		metrics.IncrCounter([]string{"bridge", "epoch_without_commitment"}, 1)
+	}
+
+	return nil
+}
+
+// pruneExecutedProofs removes stored proofs of the executed state syncs. Execution is checked from
+// the persisted pruned proofs index (or the lowest stored proof, if it is higher) up to the first state sync
+// which is not executed yet, checking at most maxChecks state syncs, so that the rest is pruned later.
+// Pruned proofs are rebuilt on demand by GetStateSyncProof, and pruned again without checking their execution.
+func (s *stateSyncManager) pruneExecutedProofs(systemState SystemState, maxChecks uint64) error {
+	firstID, exists, err := s.state.StateSyncStore.getFirstStateSyncProofID()
+	if err != nil || !exists {
+		return err
+	}
+
+	lastCommitment, err := s.state.StateSyncStore.getLastCommitmentMessage()
+	if err != nil || lastCommitment == nil {
+		return err
+	}
+
+	fromID, err := s.state.StateSyncStore.getPrunedProofsIndex()
+	if err != nil {
+		return err
+	}
+
+	fromID = common.Max(fromID, firstID)
+
+	// only committed state syncs can be executed
+	toID := common.Min(lastCommitment.Message.EndID.Uint64()+1, fromID+maxChecks)
+
+	beforeID := fromID
+	for ; beforeID < toID; beforeID++ {
+		isExecuted, err := systemState.IsStateSyncExecuted(beforeID)
+		if err != nil {
+			return fmt.Errorf("failed to check whether state sync %d is executed: %w", beforeID, err)
+		}
+
+		if !isExecuted {
+			break
+		}
+	}
+
+	prunedCount, err := s.state.StateSyncStore.pruneExecutedProofs(beforeID)
+	if err != nil {
+		return err
+	}
+
+	if prunedCount > 0 {
+		s.logger.Debug("[pruneExecutedProofs] Pruned proofs of executed state syncs",
+			"fromIndex", firstID, "toIndex", beforeID-1, "count", prunedCount)
+	}
+
+	return nil
+}
+
+// reconcileNextCommittedIndex aligns local next committed index with the one read from the contract.
+// Local index can get ahead of the on-chain one if a commitment transaction reverted,
+// in which case commitments which were not committed on-chain are discarded.
+// Must be called while holding the lock.
+func (s *stateSyncManager) reconcileNextCommittedIndex(onChainNextCommittedIndex uint64) error {
+	if s.nextCommittedIndex > onChainNextCommittedIndex {
+		removedCount, err := s.state.StateSyncStore.removeUncommittedCommitments(onChainNextCommittedIndex)
+		if err != nil {
+			return fmt.Errorf("failed to remove uncommitted commitments: %w", err)
+		}
+
+		s.logger.Warn("local next committed index is ahead of the on-chain one, correcting it",
+			"local", s.nextCommittedIndex,
+			"onChain", onChainNextCommittedIndex,
+			"removedCommitments", removedCount)
+	}
+
+	s.nextCommittedIndex = onChainNextCommittedIndex
+
+	return nil
+}
+
+// reconcileCommitmentGap checks on startup that the stored commitments, including the submitted ones
+// whose proofs are not built yet, reach the on-chain next committed index of the given system state.
+// They can be behind it if the db was restored from a backup, in which case the gap is logged,
+// and the node either refuses to start (strict policy) or fetches the missing commitments from the contract
+// (recover policy). A node without any stored commitment has nothing to reconcile.
+func (s *stateSyncManager) reconcileCommitmentGap(systemState SystemState) error {
+	onChainNextCommittedIndex, err := systemState.GetNextCommittedIndex()
+	if err != nil {
+		return fmt.Errorf("failed to get the on-chain next committed index: %w", err)
+	}
+
+	lastCommitment, err := s.state.StateSyncStore.getLastCommitmentMessage()
+	if err != nil {
+		return fmt.Errorf("failed to get the last stored commitment: %w", err)
+	}
+
+	var lastCommittedID *uint64
+	if lastCommitment != nil {
+		endID := lastCommitment.Message.EndID.Uint64()
+		lastCommittedID = &endID
+	}
+
+	s.pendingProofsLock.Lock()
+	for _, commitment := range s.pendingProofs {
+		if endID := commitment.Message.EndID.Uint64(); lastCommittedID == nil || endID > *lastCommittedID {
+			lastCommittedID = &endID
+		}
+	}
+	s.pendingProofsLock.Unlock()
+
+	if lastCommittedID == nil {
+		s.logger.Debug("no stored commitments, skipping the commitment gap check",
+			"onChainNextCommittedIndex", onChainNextCommittedIndex)
+
+		return nil
+	}
+
+	gapStart := *lastCommittedID + 1
+	if gapStart >= onChainNextCommittedIndex {
+		return nil
+	}
+
+	s.logger.Warn("stored commitments are behind the on-chain committed index",
+		"from", gapStart,
+		"to", onChainNextCommittedIndex-1,
+		"policy", s.config.commitmentGapPolicy)
+
+	switch s.config.commitmentGapPolicy {
+	case StrictCommitmentGapPolicy:
+		return fmt.Errorf("%w: state syncs from %d to %d are not covered by stored commitments",
+			errCommitmentGap, gapStart, onChainNextCommittedIndex-1)
+	case RecoverCommitmentGapPolicy:
+		return s.recoverCommitments(systemState, gapStart, onChainNextCommittedIndex)
+	default:
+		return nil
+	}
+}
+
+// recoverCommitments fetches commitments of the state syncs in range [fromIndex, nextCommittedIndex)
+// from the contract and stores them. Commitments are fetched from the most recent one backwards,
+// and at most maxRecoveredCommitments of them, so the oldest commitments of a larger gap are not recovered.
+// Proofs of their state syncs are built on demand.
+func (s *stateSyncManager) recoverCommitments(systemState SystemState, fromIndex, nextCommittedIndex uint64) error {
+	stateSyncID := nextCommittedIndex - 1
+
+	for i := 0; i < maxRecoveredCommitments; i++ {
+		commitment, err := systemState.GetCommitmentByStateSyncID(stateSyncID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch commitment of state sync %d: %w", stateSyncID, err)
+		}
+
+		if commitment.StartID.Uint64() > stateSyncID || commitment.EndID.Uint64() < stateSyncID {
+			return fmt.Errorf("%w: fetched commitment [%d, %d] does not contain state sync %d", errCommitmentGap,
+				commitment.StartID.Uint64(), commitment.EndID.Uint64(), stateSyncID)
+		}
+
+		// recovered commitment is not signed, since the signature is verified by the contract on its submission
+		recovered := &CommitmentMessageSigned{Message: commitment}
+		if err := s.state.StateSyncStore.insertCommitmentMessage(recovered); err != nil {
+			return fmt.Errorf("failed to insert recovered commitment: %w", err)
+		}
+
+		s.logger.Info("recovered commitment from the contract",
+			"from", commitment.StartID.Uint64(),
+			"to", commitment.EndID.Uint64())
+
+		if commitment.StartID.Uint64() <= fromIndex {
+			return nil
+		}
+
+		stateSyncID = commitment.StartID.Uint64() - 1
+	}
+
+	s.logger.Warn("commitment gap is only partially recovered, the oldest missing commitments are not fetched",
+		"from", fromIndex,
+		"to", stateSyncID,
+		"limit", maxRecoveredCommitments)
+
+	return nil
+}
+
+// PostSprint notifies state sync manager that a sprint ended, so that it can build a commitment
+// from uncommitted state sync events (if enabled), even if no new state sync event arrived.
+// This bounds the bridge latency by sprint length, under low state sync traffic.
+func (s *stateSyncManager) PostSprint() error {
+	if !s.config.forceSprintCommitments && !s.config.alignCommitments {
+		return nil
+	}
+
+	return s.buildCommitment()
+}
+
+// PostBlock notifies state sync manager that a block was finalized,
+// so that it can build state sync proofs if a block has a commitment submission transaction
+func (s *stateSyncManager) PostBlock(req *PostBlockRequest) error {
+	if err := s.postBlock(req); err != nil {
+		return err
+	}
+
+	// commitment deferred by a reorg is built once the new canonical chain is processed
+	if header := req.FullBlock.Block.Header; header != nil {
+		s.settleReorg(header)
+	}
+
+	return nil
+}
+
+// postBlock processes commitment submission and builds state sync proofs of the given finalized block
+func (s *stateSyncManager) postBlock(req *PostBlockRequest) error {
+	commitment, err := getCommitmentMessageSignedTx(req.FullBlock.Block.Transactions)
+	if err != nil {
+		return err
+	}
+
+	if commitment != nil {
+		// the block is finalized and its commitment was already verified against the validators of the block,
+		// so a mismatch can only come from a diverged local validator set, and the commitment is still processed
+		if err := s.verifyCommitmentSignature(commitment); err != nil {
+			s.logger.Warn("commitment signature does not match the local validator set",
+				"block", req.FullBlock.Block.Number(), "from", commitment.Message.StartID,
+				"to", commitment.Message.EndID, "err", err)
+			metrics.IncrCounter([]string{"bridge", "commitment_signature_mismatch"}, 1)
+		}
+
+		// signers of the commitment are looked up in the validator set of this epoch
+		commitment.Epoch = req.Epoch
+	}
+
+	if err := s.trackCommitmentSubmission(req, commitment); err != nil {
+		return err
+	}
+
+	if commitment != nil && s.config.adaptiveCommitmentSize {
+		if err := s.adaptCommitmentSize(req.FullBlock, commitment); err != nil {
+			return err
+		}
+	}
+
+	if s.config.proofFinalityDepth == 0 {
+		// no commitment message -> this is not end of epoch block
+		if commitment == nil {
+			return nil
+		}
+
+		if err := s.storeCommitmentProofs(commitment); err != nil {
+			return err
+		}
+
+		return s.commitmentSubmitted(commitment)
+	}
+
+	blockNumber := req.FullBlock.Block.Number()
+
+	if err := s.revertPendingProofs(blockNumber); err != nil {
+		return err
+	}
+
+	if commitment != nil {
+		// proofs are built once the commitment block is buried deep enough
+		if err := s.queuePendingProof(blockNumber, commitment); err != nil {
+			return err
+		}
+
+		if err := s.commitmentSubmitted(commitment); err != nil {
+			return err
+		}
+	}
+
+	return s.buildFinalizedProofs(blockNumber)
+}
+
+// queuePendingProof persists and queues the commitment submitted in the given block,
+// until the block reaches the proof finality depth
+func (s *stateSyncManager) queuePendingProof(blockNumber uint64, commitment *CommitmentMessageSigned) error {
+	s.pendingProofsLock.Lock()
+	defer s.pendingProofsLock.Unlock()
+
+	if err := s.state.StateSyncStore.insertPendingProof(blockNumber, commitment); err != nil {
+		return fmt.Errorf("insert pending proof error: %w", err)
+	}
+
+	s.pendingProofs[blockNumber] = commitment
+
+	return nil
+}
+
+// revertPendingProofs discards the queued commitments submitted in the given block or above it,
+// since processing the given block means that they were submitted on a fork which got reverted by a reorg.
+// The next committed index is moved back to the start of the earliest discarded commitment,
+// so that its state syncs get committed again.
+func (s *stateSyncManager) revertPendingProofs(blockNumber uint64) error {
+	s.pendingProofsLock.Lock()
+	defer s.pendingProofsLock.Unlock()
+
+	var (
+		revertedBlocks     []uint64
+		nextCommittedIndex uint64 = math.MaxUint64
+	)
+
+	for commitmentBlock, commitment := range s.pendingProofs {
+		if commitmentBlock >= blockNumber {
+			revertedBlocks = append(revertedBlocks, commitmentBlock)
+			nextCommittedIndex = common.Min(nextCommittedIndex, commitment.Message.StartID.Uint64())
+		}
+	}
+
+	if len(revertedBlocks) == 0 {
+		return nil
+	}
+
+	if err := s.state.StateSyncStore.removePendingProofs(revertedBlocks...); err != nil {
+		return fmt.Errorf("remove pending proofs error: %w", err)
+	}
+
+	for _, commitmentBlock := range revertedBlocks {
+		delete(s.pendingProofs, commitmentBlock)
+	}
+
+	s.logger.Info("discarded commitments submitted on a reverted fork",
+		"blocks", revertedBlocks, "next committed index", nextCommittedIndex)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.nextCommittedIndex = nextCommittedIndex
+	// pending commitments start after the reverted ones, so they are discarded and built again
+	s.pendingCommitments = nil
+
+	return s.state.StateSyncStore.removePendingCommitments()
+}
+
+// verifyCommitmentSignature verifies that the aggregated signature of the given commitment
+// is created by a quorum of the current validator set, as selected by the signature bitmap
+func (s *stateSyncManager) verifyCommitmentSignature(commitment *CommitmentMessageSigned) error {
+	s.lock.RLock()
+	validatorSet := s.validatorSet
+	s.lock.RUnlock()
+
+	if validatorSet == nil {
+		return errors.New("validator set is not known")
+	}
+
+	return VerifyCommitmentSignature(commitment, validatorSet.Accounts())
+}
+
+// commitmentSubmitted updates the next committed index and discards the pending commitments,
+// since the given commitment got submitted
+func (s *stateSyncManager) commitmentSubmitted(commitment *CommitmentMessageSigned) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	// update the nextCommittedIndex since a commitment was submitted
+	s.nextCommittedIndex = commitment.Message.EndID.Uint64() + 1
+	// commitment was submitted, so discard what we have in memory, so we can build a new one
+	s.pendingCommitments = nil
+
+	return s.state.StateSyncStore.removePendingCommitments()
+}
+
+// storeCommitmentProofs saves the given submitted commitment and builds proofs of its state syncs
+func (s *stateSyncManager) storeCommitmentProofs(commitment *CommitmentMessageSigned) error {
+	if err := s.state.StateSyncStore.insertSubmittedCommitmentMessage(commitment); err != nil {
+		return fmt.Errorf("insert commitment message error: %w", err)
+	}
+
+	if err := s.buildProofs(commitment.Message); err != nil {
+		return fmt.Errorf("build commitment proofs error: %w", err)
+	}
+
+	return nil
+}
+
+// buildFinalizedProofs builds proofs of the queued commitments,
+// whose blocks are buried by at least proof finality depth blocks, given the current block number
+func (s *stateSyncManager) buildFinalizedProofs(blockNumber uint64) error {
+	s.pendingProofsLock.Lock()
+	defer s.pendingProofsLock.Unlock()
+
+	finalizedBlocks := make([]uint64, 0, len(s.pendingProofs))
+
+	for commitmentBlock := range s.pendingProofs {
+		if commitmentBlock+s.config.proofFinalityDepth <= blockNumber {
+			finalizedBlocks = append(finalizedBlocks, commitmentBlock)
+		}
+	}
+
+	// commitments are stored in order of their submission
+	sort.Slice(finalizedBlocks, func(i, j int) bool { return finalizedBlocks[i] < finalizedBlocks[j] })
+
+	for _, commitmentBlock := range finalizedBlocks {
+		if err := s.storeCommitmentProofs(s.pendingProofs[commitmentBlock]); err != nil {
+			return err
+		}
+
+		if err := s.state.StateSyncStore.removePendingProofs(commitmentBlock); err != nil {
+			return fmt.Errorf("remove pending proofs error: %w", err)
+		}
+
+		delete(s.pendingProofs, commitmentBlock)
+	}
+
+	return nil
+}
+
+// trackCommitmentSubmission keeps track of the commitment submitted in the given block (if any)
+// until its registration is confirmed, and marks it for resubmission if confirmation does not arrive
+// within the configured number of blocks
+func (s *stateSyncManager) trackCommitmentSubmission(req *PostBlockRequest,
+	commitment *CommitmentMessageSigned) error {
+	if s.config.resubmissionTimeout == 0 {
+		return nil
+	}
+
+	confirmedEndID, err := getConfirmedCommitmentEndID(req.FullBlock.Receipts)
+	if err != nil {
+		return err
+	}
+
+	blockNumber := req.FullBlock.Block.Number()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if commitment != nil {
+		s.submittedCommitment = &submittedCommitment{commitment: commitment, blockNumber: blockNumber}
+	}
+
+	submitted := s.submittedCommitment
+	if submitted == nil {
+		return nil
+	}
+
+	if confirmedEndID != nil && confirmedEndID.Cmp(submitted.commitment.Message.EndID) >= 0 {
+		// a late confirmation also cancels a scheduled resubmission
+		s.submittedCommitment = nil
+
+		return nil
+	}
+
+	if !submitted.resubmit && blockNumber-submitted.blockNumber >= s.config.resubmissionTimeout {
+		s.logger.Warn("commitment registration not confirmed in time, resubmitting it",
+			"from", submitted.commitment.Message.StartID.Uint64(),
+			"to", submitted.commitment.Message.EndID.Uint64(),
+			"submittedAt", submitted.blockNumber)
 
-	s.lock.Unlock()
+		submitted.resubmit = true
+	}
 
-	return s.buildCommitment()
+	return nil
 }
 
-// PostBlock notifies state sync manager that a block was finalized,
-// so that it can build state sync proofs if a block has a commitment submission transaction
-func (s *stateSyncManager) PostBlock(req *PostBlockRequest) error {
-	commitment, err := getCommitmentMessageSignedTx(req.FullBlock.Block.Transactions)
+// loadCommitmentSize reloads the adaptive commitment size persisted before the node restart
+func (s *stateSyncManager) loadCommitmentSize() error {
+	size, err := s.state.StateSyncStore.getCommitmentSize()
 	if err != nil {
 		return err
 	}
 
-	// no commitment message -> this is not end of epoch block
-	if commitment == nil {
+	s.lock.Lock()
+	s.commitmentSize = size
+	s.lock.Unlock()
+
+	return nil
+}
+
+// adaptCommitmentSize derives the commitment size from the gas used by the given commitment
+// submitted in the given finalized block, so that every node ends up with the same commitment size
+func (s *stateSyncManager) adaptCommitmentSize(fullBlock *types.FullBlock,
+	commitment *CommitmentMessageSigned) error {
+	gasUsed, ok := getCommitmentGasUsed(fullBlock)
+	if !ok || fullBlock.Block.Header.GasLimit == 0 {
 		return nil
 	}
 
-	if err := s.state.StateSyncStore.insertCommitmentMessage(commitment); err != nil {
-		return fmt.Errorf("insert commitment message error: %w", err)
-	}
+	size := deriveCommitmentSize(commitment.Message.EndID.Uint64()-commitment.Message.StartID.Uint64()+1,
+		gasUsed, fullBlock.Block.Header.GasLimit, s.config.minCommitmentSize, s.config.maxCommitmentSize)
 
-	if err := s.buildProofs(commitment.Message); err != nil {
-		return fmt.Errorf("build commitment proofs error: %w", err)
+	if err := s.state.StateSyncStore.writeCommitmentSize(size); err != nil {
+		return fmt.Errorf("failed to persist commitment size. Error: %w", err)
 	}
 
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	// update the nextCommittedIndex since a commitment was submitted
-	s.nextCommittedIndex = commitment.Message.EndID.Uint64() + 1
-	// commitment was submitted, so discard what we have in memory, so we can build a new one
-	s.pendingCommitments = nil
+
+	if size != s.getCommitmentSize() {
+		s.logger.Debug("commitment size adapted", "size", size, "gasUsed", gasUsed,
+			"gasLimit", fullBlock.Block.Header.GasLimit)
+	}
+
+	s.commitmentSize = size
 
 	return nil
 }
 
+// deriveCommitmentSize returns the number of state syncs a commitment can hold, so that its transaction
+// uses commitmentTargetGasUsage percent of the block gas limit, given the gas used by a commitment
+// of committedSize state syncs. The result is bounded by the min and max commitment size.
+func deriveCommitmentSize(committedSize, gasUsed, gasLimit, minSize, maxSize uint64) uint64 {
+	if committedSize == 0 || gasUsed == 0 {
+		return maxSize
+	}
+
+	gasPerStateSync := common.Max(1, gasUsed/committedSize)
+	size := gasLimit * commitmentTargetGasUsage / 100 / gasPerStateSync
+
+	return common.Max(minSize, common.Min(size, maxSize))
+}
+
+// getCommitmentSize returns the maximum number of state syncs in a commitment (assumes lock is held)
+func (s *stateSyncManager) getCommitmentSize() uint64 {
+	if s.commitmentSize == 0 || s.commitmentSize > s.config.maxCommitmentSize {
+		return s.config.maxCommitmentSize
+	}
+
+	return s.commitmentSize
+}
+
+// getCommitmentGasUsed returns gas used by the commitment transaction of the given block
+func getCommitmentGasUsed(fullBlock *types.FullBlock) (uint64, bool) {
+	for i, tx := range fullBlock.Block.Transactions {
+		if !isCommitmentMessageSignedTx(tx) {
+			continue
+		}
+
+		if i >= len(fullBlock.Receipts) {
+			return 0, false
+		}
+
+		return fullBlock.Receipts[i].GasUsed, true
+	}
+
+	return 0, false
+}
+
+// getConfirmedCommitmentEndID returns the highest end id of commitments whose registration is confirmed
+// by the NewCommitment event of the StateReceiver contract in the given receipts (nil if there is none)
+func getConfirmedCommitmentEndID(receipts []*types.Receipt) (*big.Int, error) {
+	var endID *big.Int
+
+	for _, receipt := range receipts {
+		if receipt.Status == nil || *receipt.Status != types.ReceiptSuccess {
+			continue
+		}
+
+		for _, log := range receipt.Logs {
+			if log.Address != contracts.StateReceiverContract {
+				continue
+			}
+
+			var event contractsapi.NewCommitmentEvent
+
+			doesMatch, err := event.ParseLog(convertLog(log))
+			if err != nil {
+				return nil, err
+			}
+
+			if doesMatch && (endID == nil || event.EndID.Cmp(endID) > 0) {
+				endID = event.EndID
+			}
+		}
+	}
+
+	return endID, nil
+}
+
 // GetStateSyncProof returns the proof for the state sync
 func (s *stateSyncManager) GetStateSyncProof(stateSyncID uint64) (types.Proof, error) {
 	stateSyncProof, err := s.state.StateSyncStore.getStateSyncProof(stateSyncID)
@@ -433,15 +1723,85 @@ func (s *stateSyncManager) GetStateSyncProof(stateSyncID uint64) (types.Proof, e
 		}
 	}
 
+	if err := stateSyncProof.validateVersion(); err != nil {
+		return types.Proof{}, fmt.Errorf("cannot get state sync proof for StateSync id %d: %w", stateSyncID, err)
+	}
+
 	return types.Proof{
 		Data: stateSyncProof.Proof,
 		Metadata: map[string]interface{}{
 			"StateSync": stateSyncProof.StateSync,
+			"Version":   stateSyncProof.GetVersion(),
 		},
 	}, nil
 }
 
-// buildProofs builds state sync proofs for the submitted commitment and saves them in boltDb for later execution
+// ExportEpochBridgeData returns JSON encoded EpochBridgeData of the given epoch, i.e. all the commitments
+// submitted in the epoch, along with the proofs of their state syncs. Missing (e.g. pruned) proofs are rebuilt.
+func (s *stateSyncManager) ExportEpochBridgeData(epoch uint64) ([]byte, error) {
+	commitments, err := s.state.StateSyncStore.getEpochCommitmentMessages(epoch)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get commitments of epoch %d: %w", epoch, err)
+	}
+
+	data := &EpochBridgeData{
+		Epoch:            epoch,
+		MerkleHashScheme: s.config.merkleHashScheme,
+		Commitments:      make([]*CommitmentBridgeData, 0, len(commitments)),
+	}
+
+	if data.MerkleHashScheme == "" {
+		data.MerkleHashScheme = Keccak256MerkleHashScheme
+	}
+
+	for _, commitment := range commitments {
+		proofs, err := s.getCommitmentProofs(commitment.Message)
+		if err != nil {
+			return nil, err
+		}
+
+		data.Commitments = append(data.Commitments, &CommitmentBridgeData{
+			Commitment: commitment,
+			Proofs:     proofs,
+		})
+	}
+
+	return json.Marshal(data)
+}
+
+// getCommitmentProofs returns the proofs of all the state syncs of the given commitment.
+// If any of the proofs is missing (e.g. pruned), the proofs are generated once again,
+// without saving them or delivering them to the proof subscribers.
+func (s *stateSyncManager) getCommitmentProofs(
+	commitmentMsg *contractsapi.StateSyncCommitment) ([]*StateSyncProof, error) {
+	from := commitmentMsg.StartID.Uint64()
+	to := commitmentMsg.EndID.Uint64()
+
+	proofs := make([]*StateSyncProof, 0, to-from+1)
+
+	for stateSyncID := from; stateSyncID <= to; stateSyncID++ {
+		proof, err := s.state.StateSyncStore.getStateSyncProof(stateSyncID)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get state sync proof for StateSync id %d: %w", stateSyncID, err)
+		}
+
+		if proof == nil {
+			proofs, err = s.generateProofs(commitmentMsg)
+			if err != nil {
+				return nil, fmt.Errorf("cannot generate proofs for commitment for StateSync id %d: %w", stateSyncID, err)
+			}
+
+			return proofs, nil
+		}
+
+		proofs = append(proofs, proof)
+	}
+
+	return proofs, nil
+}
+
+// buildProofs builds state sync proofs for the submitted commitment and saves them in boltDb for later execution.
+// Proofs are delivered to the proof subscribers as soon as they are built, before they are saved.
 func (s *stateSyncManager) buildProofs(commitmentMsg *contractsapi.StateSyncCommitment) error {
 	from := commitmentMsg.StartID.Uint64()
 	to := commitmentMsg.EndID.Uint64()
@@ -452,14 +1812,33 @@ func (s *stateSyncManager) buildProofs(commitmentMsg *contractsapi.StateSyncComm
 		"toIndex", to,
 	)
 
-	events, err := s.state.StateSyncStore.getStateSyncEventsForCommitment(from, to)
+	stateSyncProofs, err := s.generateProofs(commitmentMsg)
+	if err != nil {
+		return err
+	}
+
+	s.proofStream.push(stateSyncProofs...)
+
+	s.logger.Debug(
+		"[buildProofs] Building proofs for commitment finished.",
+		"fromIndex", from,
+		"toIndex", to,
+	)
+
+	return s.state.StateSyncStore.insertStateSyncProofs(stateSyncProofs)
+}
+
+// generateProofs generates state sync proofs for the given commitment from the stored state sync events
+func (s *stateSyncManager) generateProofs(commitmentMsg *contractsapi.StateSyncCommitment) ([]*StateSyncProof, error) {
+	events, err := s.state.StateSyncStore.getStateSyncEventsForCommitment(
+		commitmentMsg.StartID.Uint64(), commitmentMsg.EndID.Uint64())
 	if err != nil {
-		return fmt.Errorf("failed to get state sync events for commitment to build proofs. Error: %w", err)
+		return nil, fmt.Errorf("failed to get state sync events for commitment to build proofs. Error: %w", err)
 	}
 
-	tree, err := createMerkleTree(events)
+	tree, err := s.getCommitmentMerkleTree(commitmentMsg, events)
 	if err != nil {
-		return fmt.Errorf("could not create merkle tree. error: %w", err)
+		return nil, fmt.Errorf("could not create merkle tree. error: %w", err)
 	}
 
 	stateSyncProofs := make([]*StateSyncProof, len(events))
@@ -467,52 +1846,233 @@ func (s *stateSyncManager) buildProofs(commitmentMsg *contractsapi.StateSyncComm
 	for i, event := range events {
 		leaf, err := event.EncodeAbi()
 		if err != nil {
-			return fmt.Errorf("could not encode state sync event. error: %w", err)
+			return nil, fmt.Errorf("could not encode state sync event. error: %w", err)
 		}
 
 		p, err := tree.GenerateProof(leaf)
 		if err != nil {
-			return fmt.Errorf("error generating proof for event: %v. error: %w", event.ID, err)
+			return nil, fmt.Errorf("error generating proof for event: %v. error: %w", event.ID, err)
 		}
 
 		stateSyncProofs[i] = &StateSyncProof{
 			Proof:     p,
 			StateSync: event,
+			Version:   StateSyncProofVersion,
 		}
 	}
 
-	s.logger.Debug(
-		"[buildProofs] Building proofs for commitment finished.",
-		"fromIndex", from,
-		"toIndex", to,
-	)
+	return stateSyncProofs, nil
+}
 
-	return s.state.StateSyncStore.insertStateSyncProofs(stateSyncProofs)
+// merkleTreeCacheKey identifies merkle tree of a commitment.
+// Commitment root is part of the key, so a cached tree is never used for a commitment built from different events.
+type merkleTreeCacheKey struct {
+	from uint64
+	to   uint64
+	root types.Hash
 }
 
-// buildCommitment builds a new commitment, signs it and gossips its vote for it
-func (s *stateSyncManager) buildCommitment() error {
+// VerifyCommitment rebuilds merkle tree of the given commitment from the locally stored state sync events
+// and checks that its root matches the commitment root. It fails if any of the committed state sync events
+// is not stored locally.
+func (s *stateSyncManager) VerifyCommitment(commitment *contractsapi.StateSyncCommitment) error {
+	from, to := commitment.StartID.Uint64(), commitment.EndID.Uint64()
+
+	if err := validateCommitmentRange(from, to, s.config.maxCommitmentSize); err != nil {
+		return err
+	}
+
+	events, err := s.state.StateSyncStore.getStateSyncEventsForCommitment(from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get state sync events %d-%d: %w", from, to, err)
+	}
+
+	tree, err := s.getCommitmentMerkleTree(commitment, events)
+	if err != nil {
+		return fmt.Errorf("failed to build merkle tree of state sync events %d-%d: %w", from, to, err)
+	}
+
+	if root := tree.Hash(); root != commitment.Root {
+		return fmt.Errorf("expected root '%s' of state sync events %d-%d, but got '%s'",
+			root, from, to, commitment.Root)
+	}
+
+	return nil
+}
+
+// merkleHasher returns the configured hasher of commitment merkle trees
+// (nil, meaning the default one, if state sync manager is not configured)
+func (s *stateSyncManager) merkleHasher() MerkleHasher {
+	if s.config == nil {
+		return nil
+	}
+
+	return s.config.merkleHasher
+}
+
+// getCommitmentMerkleTree returns merkle tree of the given commitment built from the given events,
+// reusing the cached one if it was recently built
+func (s *stateSyncManager) getCommitmentMerkleTree(commitment *contractsapi.StateSyncCommitment,
+	events []*contractsapi.StateSyncedEvent) (*merkle.MerkleTree, error) {
+	key := merkleTreeCacheKey{
+		from: commitment.StartID.Uint64(),
+		to:   commitment.EndID.Uint64(),
+		root: commitment.Root,
+	}
+
+	if s.merkleTreeCache == nil {
+		return createMerkleTree(events, s.merkleHasher())
+	}
+
+	if tree, ok := s.merkleTreeCache.Get(key); ok {
+		return tree.(*merkle.MerkleTree), nil //nolint:forcetypeassert
+	}
+
+	tree, err := createMerkleTree(events, s.merkleHasher())
+	if err != nil {
+		return nil, err
+	}
+
+	if tree.Hash() == commitment.Root {
+		// cache only the trees matching the commitment, so that the key identifies the tree
+		s.merkleTreeCache.Add(key, tree)
+	}
+
+	return tree, nil
+}
+
+// Pause pauses building of new commitments, until Resume is called.
+// State sync events are still tracked and stored while paused.
+func (s *stateSyncManager) Pause() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.paused = true
+
+	s.logger.Info("[State sync manager] commitment building paused")
+}
+
+// Resume resumes building of commitments and builds a commitment from the next committed index,
+// so that state sync events stored while paused get committed (at the end of the sprint, if commitments
+// are aligned to sprints)
+func (s *stateSyncManager) Resume() error {
+	s.lock.Lock()
+	s.paused = false
+	s.lock.Unlock()
+
+	s.logger.Info("[State sync manager] commitment building resumed")
+
+	if s.config.alignCommitments {
+		return nil
+	}
+
+	return s.buildCommitment()
+}
+
+// blockRef identifies a block by its number and hash
+type blockRef struct {
+	number uint64
+	hash   types.Hash
+}
+
+// isProcessedBy returns true if the given processed block is the referenced block, or a block above it
+func (b blockRef) isProcessedBy(processed blockRef) bool {
+	return processed.hash == b.hash || processed.number > b.number
+}
+
+// ReorgStarted is called by the reorg notification subscription. It defers building of new commitments,
+// since the committed index and pending commitments may be based on the orphaned blocks. Reorg settles
+// once the head of the new canonical chain is processed, and the rollback is done (see PostBlock).
+func (s *stateSyncManager) ReorgStarted(event *blockchain.ReorgEvent) {
+	if len(event.NewCanonical) == 0 {
+		return
+	}
+
+	head := blockRef{
+		number: event.CommonAncestor.Number + uint64(len(event.NewCanonical)),
+		hash:   event.NewCanonical[len(event.NewCanonical)-1],
+	}
+
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	stateSyncEvents, err := s.state.StateSyncStore.getStateSyncEventsForCommitment(s.nextCommittedIndex,
-		s.nextCommittedIndex+s.config.maxCommitmentSize-1)
+	if head.isProcessedBy(s.lastProcessedBlock) {
+		// notification arrived after the new canonical chain was already processed
+		return
+	}
+
+	s.reorgInProgress = true
+	s.reorgHead = head
+
+	s.logger.Info("[State sync manager] commitment building deferred until reorg settles",
+		"commonAncestor", event.CommonAncestor.Number, "newHead", head.number)
+}
+
+// settleReorg records the given block as processed. If it completes the reorg in progress,
+// it clears the reorg in progress flag and builds the deferred commitment
+// (at the end of the sprint, if commitments are aligned to sprints).
+func (s *stateSyncManager) settleReorg(header *types.Header) {
+	s.lock.Lock()
+	s.lastProcessedBlock = blockRef{number: header.Number, hash: header.Hash}
+
+	settled := s.reorgInProgress && s.reorgHead.isProcessedBy(s.lastProcessedBlock)
+	if settled {
+		s.reorgInProgress = false
+	}
+	s.lock.Unlock()
+
+	if !settled {
+		return
+	}
+
+	s.logger.Info("[State sync manager] reorg settled, commitment building resumed")
+
+	if s.config.alignCommitments {
+		return
+	}
+
+	if err := s.buildCommitment(); err != nil {
+		s.logger.Error("could not build a commitment after reorg settled", "err", err)
+	}
+}
+
+// buildCommitment builds a new commitment, signs it and gossips its vote for it
+func (s *stateSyncManager) buildCommitment() error {
+	s.lock.RLock()
+	if s.paused || s.reorgInProgress {
+		s.lock.RUnlock()
+
+		return nil
+	}
+
+	epoch, generation, nextCommittedIndex := s.epoch, s.generation, s.nextCommittedIndex
+	lastPendingCommitment := s.lastPendingCommitment()
+	commitmentSize := s.getCommitmentSize()
+	s.lock.RUnlock()
+
+	stateSyncEvents, err := s.state.StateSyncStore.getStateSyncEventsForCommitment(nextCommittedIndex,
+		nextCommittedIndex+commitmentSize-1)
 	if err != nil && !errors.Is(err, errNotEnoughStateSyncs) {
 		return fmt.Errorf("failed to get state sync events for commitment. Error: %w", err)
 	}
 
-	if len(stateSyncEvents) == 0 {
-		// there are no state sync events
+	if uint64(len(stateSyncEvents)) < s.config.minCommitmentSize {
+		// there are not enough state sync events
 		return nil
 	}
 
-	if len(s.pendingCommitments) > 0 &&
-		s.pendingCommitments[len(s.pendingCommitments)-1].StartID.Cmp(stateSyncEvents[len(stateSyncEvents)-1].ID) >= 0 {
+	if err := checkStateSyncsContiguity(stateSyncEvents, nextCommittedIndex); err != nil {
+		return fmt.Errorf("failed to build commitment. Error: %w", err)
+	}
+
+	lastEventID := stateSyncEvents[len(stateSyncEvents)-1].ID
+	if lastPendingCommitment != nil && lastPendingCommitment.EndID.Cmp(lastEventID) >= 0 {
 		// already built a commitment of this size which is pending to be submitted
 		return nil
 	}
 
-	commitment, err := NewPendingCommitment(s.epoch, stateSyncEvents)
+	// commitment is built and signed without holding the lock, so that epoch can change in the meantime
+	commitment, err := NewPendingCommitmentWithHasher(epoch, stateSyncEvents, commitmentSize, s.merkleHasher())
 	if err != nil {
 		return err
 	}
@@ -524,17 +2084,37 @@ func (s *stateSyncManager) buildCommitment() error {
 
 	hashBytes := hash.Bytes()
 
-	signature, err := s.config.key.SignWithDomain(hashBytes, bls.DomainStateReceiver)
+	signature, err := s.config.aggregateSigner.Sign(s.config.key, hashBytes, bls.DomainStateReceiver)
 	if err != nil {
 		return fmt.Errorf("failed to sign commitment message. Error: %w", err)
 	}
 
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	// epoch changed while building, so the commitment is signed for the previous validator set,
+	// or a commitment got submitted, so the built one does not start at the next committed index anymore
+	if s.generation != generation || s.nextCommittedIndex != nextCommittedIndex {
+		s.logger.Debug("[buildCommitment] Abandoned stale commitment",
+			"from", commitment.StartID.Uint64(),
+			"to", commitment.EndID.Uint64(),
+			"epoch", epoch)
+
+		return nil
+	}
+
+	if lastPendingCommitment := s.lastPendingCommitment(); lastPendingCommitment != nil &&
+		lastPendingCommitment.EndID.Cmp(lastEventID) >= 0 {
+		// a concurrent build already appended a commitment of this size
+		return nil
+	}
+
 	sig := &MessageSignature{
 		From:      s.config.key.String(),
 		Signature: signature,
 	}
 
-	if _, err = s.state.StateSyncStore.insertMessageVote(s.epoch, hashBytes, sig); err != nil {
+	if _, err = s.state.StateSyncStore.insertMessageVote(epoch, hashBytes, sig); err != nil {
 		return fmt.Errorf(
 			"failed to insert signature for hash=%v to the state. Error: %w",
 			hex.EncodeToString(hashBytes),
@@ -547,7 +2127,7 @@ func (s *stateSyncManager) buildCommitment() error {
 		Hash:        hashBytes,
 		Signature:   signature,
 		From:        s.config.key.String(),
-		EpochNumber: s.epoch,
+		EpochNumber: epoch,
 	})
 
 	s.logger.Debug(
@@ -556,11 +2136,90 @@ func (s *stateSyncManager) buildCommitment() error {
 		"to", commitment.EndID.Uint64(),
 	)
 
+	if err := s.state.StateSyncStore.insertPendingCommitment(commitment); err != nil {
+		return fmt.Errorf("failed to persist pending commitment. Error: %w", err)
+	}
+
+	if s.merkleTreeCache != nil {
+		// tree is reused for building proofs, once the commitment gets submitted
+		s.merkleTreeCache.Add(merkleTreeCacheKey{
+			from: commitment.StartID.Uint64(),
+			to:   commitment.EndID.Uint64(),
+			root: commitment.Root,
+		}, commitment.MerkleTree)
+	}
+
 	s.pendingCommitments = append(s.pendingCommitments, commitment)
 
+	// new commitment supersedes the previous ones, so its waiting for quorum starts now
+	s.startQuorumWait(commitment)
+
 	return nil
 }
 
+// startQuorumWait starts measuring the time the given (largest pending) commitment awaits quorum
+func (s *stateSyncManager) startQuorumWait(commitment *PendingCommitment) {
+	s.quorumWaitLock.Lock()
+	defer s.quorumWaitLock.Unlock()
+
+	s.quorumWait = quorumWait{commitment: commitment, since: time.Now()}
+}
+
+// updateQuorumWait returns the time the given largest pending commitment has been awaiting quorum,
+// and whether the quorum is not reached within the warning threshold (reported only once per commitment)
+func (s *stateSyncManager) updateQuorumWait(commitment *PendingCommitment, hasQuorum bool) (time.Duration, bool) {
+	s.quorumWaitLock.Lock()
+	defer s.quorumWaitLock.Unlock()
+
+	if s.quorumWait.commitment != commitment {
+		// commitment was not built by this instance (e.g. it was reloaded after restart)
+		s.quorumWait = quorumWait{commitment: commitment, since: time.Now()}
+	}
+
+	s.quorumWait.hasQuorum = hasQuorum
+
+	wait := time.Since(s.quorumWait.since)
+	threshold := s.config.quorumWaitWarnThreshold
+
+	if hasQuorum || threshold == 0 || wait < threshold || s.quorumWait.warned {
+		return wait, false
+	}
+
+	s.quorumWait.warned = true
+
+	return wait, true
+}
+
+// Status returns the number of pending commitments and whether the largest one can reach quorum
+func (s *stateSyncManager) Status() *StateSyncStatus {
+	s.lock.RLock()
+	pendingCommitments := len(s.pendingCommitments)
+	lastPendingCommitment := s.lastPendingCommitment()
+	s.lock.RUnlock()
+
+	status := &StateSyncStatus{PendingCommitments: pendingCommitments, QuorumReachable: true}
+
+	s.quorumWaitLock.Lock()
+	defer s.quorumWaitLock.Unlock()
+
+	if lastPendingCommitment != nil && s.quorumWait.commitment == lastPendingCommitment {
+		status.QuorumWait = time.Since(s.quorumWait.since)
+		status.QuorumReachable = s.quorumWait.hasQuorum || !s.quorumWait.warned
+	}
+
+	return status
+}
+
+// lastPendingCommitment returns the largest pending commitment (nil if there is none).
+// Must be called while holding the lock.
+func (s *stateSyncManager) lastPendingCommitment() *PendingCommitment {
+	if len(s.pendingCommitments) == 0 {
+		return nil
+	}
+
+	return s.pendingCommitments[len(s.pendingCommitments)-1]
+}
+
 // multicast publishes given message to the rest of the network
 func (s *stateSyncManager) multicast(msg interface{}) {
 	data, err := json.Marshal(msg)
@@ -575,3 +2234,15 @@ func (s *stateSyncManager) multicast(msg interface{}) {
 		s.logger.Warn("failed to gossip bridge message", "err", err)
 	}
 }
+
+// checkStateSyncsContiguity checks that given state sync events are sorted in ascending order of their IDs
+// and that they are contiguous, starting from the given index, since they are executed sequentially on-chain
+func checkStateSyncsContiguity(events []*contractsapi.StateSyncedEvent, fromIndex uint64) error {
+	for i, event := range events {
+		if expectedID := fromIndex + uint64(i); event.ID.Uint64() != expectedID {
+			return fmt.Errorf("%w: expected state sync %d, got %d", errStateSyncsNotContiguous, expectedID, event.ID)
+		}
+	}
+
+	return nil
+}
@@ -1,23 +1,33 @@
 package polybft
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
 	"math/big"
 	"math/rand"
 	"os"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/armon/go-metrics"
 	"github.com/hashicorp/go-hclog"
 	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"github.com/umbracle/ethgo"
 	"github.com/umbracle/ethgo/abi"
 	"github.com/umbracle/ethgo/testutil"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/0xPolygon/polygon-edge/blockchain"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
+	polybftProto "github.com/0xPolygon/polygon-edge/consensus/polybft/proto"
 	bls "github.com/0xPolygon/polygon-edge/consensus/polybft/signer"
 	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
+	"github.com/0xPolygon/polygon-edge/contracts"
 	"github.com/0xPolygon/polygon-edge/merkle-tree"
 	"github.com/0xPolygon/polygon-edge/types"
 )
@@ -29,7 +39,7 @@ func newTestStateSyncManager(t *testing.T, key *validator.TestValidator) *stateS
 	require.NoError(t, err)
 
 	state := newTestState(t)
-	require.NoError(t, state.EpochStore.insertEpoch(0))
+	require.NoError(t, state.EpochStore.insertEpoch(0, nil))
 
 	topic := &mockTopic{}
 
@@ -62,7 +72,7 @@ func TestStateSyncManager_PostEpoch_BuildCommitment(t *testing.T) {
 
 	// add 5 state syncs starting in index 0, it will generate one smaller commitment
 	for i := 0; i < 5; i++ {
-		require.NoError(t, s.state.StateSyncStore.insertStateSyncEvent(stateSyncs10[i]))
+		insertTestStateSyncEvents(t, s.state.StateSyncStore, stateSyncs10[i])
 	}
 
 	require.NoError(t, s.buildCommitment())
@@ -73,7 +83,7 @@ func TestStateSyncManager_PostEpoch_BuildCommitment(t *testing.T) {
 
 	// add the next 5 state syncs, at that point, so that it generates a larger commitment
 	for i := 5; i < 10; i++ {
-		require.NoError(t, s.state.StateSyncStore.insertStateSyncEvent(stateSyncs10[i]))
+		insertTestStateSyncEvents(t, s.state.StateSyncStore, stateSyncs10[i])
 	}
 
 	require.NoError(t, s.buildCommitment())
@@ -86,6 +96,444 @@ func TestStateSyncManager_PostEpoch_BuildCommitment(t *testing.T) {
 	require.NotNil(t, s.config.topic.(*mockTopic).consume()) //nolint
 }
 
+func TestStateSyncManager_PendingCommitments_Restart(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("GetNextCommittedIndex").Return(uint64(0), nil)
+
+	postEpochReq := &PostEpochRequest{
+		NewEpochID:   0,
+		SystemState:  systemStateMock,
+		ValidatorSet: vals.ToValidatorSet(),
+	}
+
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, generateStateSyncEvents(t, 5, 0)...)
+
+	// commitment is built and signed by validator 0
+	require.NoError(t, s.PostEpoch(postEpochReq))
+	require.Len(t, s.pendingCommitments, 1)
+
+	hash, err := s.pendingCommitments[0].Hash()
+	require.NoError(t, err)
+
+	msg := newMockMsg().WithHash(hash.Bytes())
+
+	// validators 1 and 2 vote as well, which is still not enough for quorum
+	for _, alias := range []string{"1", "2"} {
+		signedMsg, err := msg.sign(vals.GetValidator(alias), bls.DomainStateReceiver)
+		require.NoError(t, err)
+		require.NoError(t, s.saveVote(signedMsg))
+	}
+
+	commitment, err := s.Commitment()
+	require.NoError(t, err)
+	require.Nil(t, commitment)
+
+	// restart the manager on top of the same db
+	restarted := newStateSyncManager(hclog.NewNullLogger(), s.state, s.config)
+	require.NoError(t, restarted.loadPendingCommitments())
+	require.NoError(t, restarted.PostEpoch(postEpochReq))
+	require.Len(t, restarted.pendingCommitments, 1)
+
+	restartedHash, err := restarted.pendingCommitments[0].Hash()
+	require.NoError(t, err)
+	require.Equal(t, hash, restartedHash)
+
+	// one more vote is enough to reach the quorum, since previous votes are preserved
+	signedMsg, err := msg.sign(vals.GetValidator("3"), bls.DomainStateReceiver)
+	require.NoError(t, err)
+	require.NoError(t, restarted.saveVote(signedMsg))
+
+	commitment, err = restarted.Commitment()
+	require.NoError(t, err)
+	require.NotNil(t, commitment)
+	require.Equal(t, uint64(4), commitment.Message.EndID.Uint64())
+
+	// reloaded commitments of the previous epoch are discarded on epoch change
+	restarted = newStateSyncManager(hclog.NewNullLogger(), s.state, s.config)
+	require.NoError(t, restarted.loadPendingCommitments())
+	require.Len(t, restarted.pendingCommitments, 1)
+
+	require.NoError(t, s.state.EpochStore.insertEpoch(1, nil))
+	require.NoError(t, restarted.PostEpoch(&PostEpochRequest{
+		NewEpochID:   1,
+		SystemState:  systemStateMock,
+		ValidatorSet: vals.ToValidatorSet(),
+	}))
+	require.Len(t, restarted.pendingCommitments, 1)
+	require.Equal(t, uint64(1), restarted.pendingCommitments[0].Epoch)
+
+	persistedCommitments, err := s.state.StateSyncStore.getPendingCommitments()
+	require.NoError(t, err)
+	require.Len(t, persistedCommitments, 1)
+	require.Equal(t, uint64(1), persistedCommitments[0].Epoch)
+}
+
+func TestStateSyncManager_BuildCommitment_MaxCommitmentSize(t *testing.T) {
+	const commitmentSizeLimit = 3
+
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.config.maxCommitmentSize = commitmentSizeLimit
+
+	// there are more pending state syncs than the configured limit
+	for _, event := range generateStateSyncEvents(t, 2*commitmentSizeLimit, 0) {
+		insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
+	}
+
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 1)
+	require.Equal(t, uint64(0), s.pendingCommitments[0].StartID.Uint64())
+	require.Equal(t, uint64(commitmentSizeLimit-1), s.pendingCommitments[0].EndID.Uint64())
+}
+
+func TestStateSyncManager_NewStateSyncManager_Defaults(t *testing.T) {
+	t.Parallel()
+
+	config := &stateSyncConfig{}
+
+	s := newStateSyncManager(hclog.NewNullLogger(), nil, config)
+
+	require.Equal(t, uint64(minCommitmentSize), s.config.minCommitmentSize)
+	require.Equal(t, uint64(defaultMaxVoteEpochLead), s.config.maxVoteEpochLead)
+	require.NotNil(t, s.config.aggregateSigner)
+	require.NotNil(t, s.config.merkleHasher)
+
+	// defaults are not written into the configuration of the caller
+	require.Equal(t, &stateSyncConfig{}, config)
+}
+
+func TestStateSyncManager_BuildCommitment_MinCommitmentSize(t *testing.T) {
+	const commitmentSizeMinimum = 5
+
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.config.minCommitmentSize = commitmentSizeMinimum
+
+	events := generateStateSyncEvents(t, commitmentSizeMinimum, 0)
+
+	// there are less pending state syncs than the configured minimum
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, events[:commitmentSizeMinimum-1]...)
+
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 0)
+
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, events[commitmentSizeMinimum-1])
+
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 1)
+	require.Equal(t, uint64(0), s.pendingCommitments[0].StartID.Uint64())
+	require.Equal(t, uint64(commitmentSizeMinimum-1), s.pendingCommitments[0].EndID.Uint64())
+}
+
+func TestStateSyncManager_BuildCommitment_PauseResume(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	s.Pause()
+
+	// state sync events arriving while paused are stored, but not committed
+	for _, event := range generateStateSyncEvents(t, 5, 0) {
+		insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
+	}
+
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 0)
+
+	stateSyncEvents, err := s.state.StateSyncStore.getStateSyncEventsForCommitment(0, 4)
+	require.NoError(t, err)
+	require.Len(t, stateSyncEvents, 5)
+
+	// resuming builds a commitment from the next committed index
+	require.NoError(t, s.Resume())
+	require.Len(t, s.pendingCommitments, 1)
+	require.Equal(t, uint64(0), s.pendingCommitments[0].StartID.Uint64())
+	require.Equal(t, uint64(4), s.pendingCommitments[0].EndID.Uint64())
+}
+
+func TestStateSyncManager_BuildCommitment_DeferredDuringReorg(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	newHeaders := []*types.Header{
+		{Number: 3, Hash: types.StringToHash("0x3b")},
+		{Number: 4, Hash: types.StringToHash("0x4b")},
+	}
+
+	postBlock := func(header *types.Header) {
+		t.Helper()
+
+		require.NoError(t, s.PostBlock(&PostBlockRequest{
+			FullBlock: &types.FullBlock{Block: &types.Block{Header: header}},
+		}))
+	}
+
+	// block 2 is the common ancestor, and the new canonical chain ends with block 4
+	s.ReorgStarted(&blockchain.ReorgEvent{
+		CommonAncestor: &types.Header{Number: 2},
+		Orphaned:       []types.Hash{types.StringToHash("0x3a")},
+		NewCanonical:   []types.Hash{newHeaders[0].Hash, newHeaders[1].Hash},
+	})
+	require.True(t, s.reorgInProgress)
+
+	// state sync events arriving from the event tracker during reorg are stored, but not committed
+	var stateSyncedEvent contractsapi.StateSyncedEvent
+
+	data, err := abi.MustNewType("tuple(string a)").Encode([]string{"data"})
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		s.AddLog(&ethgo.Log{
+			Topics: []ethgo.Hash{
+				stateSyncedEvent.Sig(),
+				ethgo.BytesToHash([]byte{byte(i)}),
+				ethgo.ZeroHash,
+				ethgo.ZeroHash,
+			},
+			Data: data,
+		})
+	}
+
+	require.Len(t, s.pendingCommitments, 0)
+
+	stateSyncEvents, err := s.state.StateSyncStore.getStateSyncEventsForCommitment(0, 4)
+	require.NoError(t, err)
+	require.Len(t, stateSyncEvents, 5)
+
+	// reorg is still in progress until the head of the new canonical chain is processed
+	postBlock(newHeaders[0])
+	require.True(t, s.reorgInProgress)
+	require.Len(t, s.pendingCommitments, 0)
+
+	// reorg settles, and deferred commitment is built
+	postBlock(newHeaders[1])
+	require.False(t, s.reorgInProgress)
+	require.Len(t, s.pendingCommitments, 1)
+	require.Equal(t, uint64(0), s.pendingCommitments[0].StartID.Uint64())
+	require.Equal(t, uint64(4), s.pendingCommitments[0].EndID.Uint64())
+
+	// notification received after the new canonical chain was processed does not defer commitment building
+	s.ReorgStarted(&blockchain.ReorgEvent{
+		CommonAncestor: &types.Header{Number: 2},
+		NewCanonical:   []types.Hash{newHeaders[0].Hash, newHeaders[1].Hash},
+	})
+	require.False(t, s.reorgInProgress)
+}
+
+func TestStateSyncManager_BuildCommitment_InsertValidatedStateSyncEvent(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	// event 4 was missed, so the commitment can not cover the events after it
+	events := generateStateSyncEvents(t, 10, 0)
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, events[:4]...)
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, events[5:]...)
+
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 1)
+	require.Equal(t, uint64(3), s.pendingCommitments[0].EndID.Uint64())
+
+	sub := s.SubscribeStateSyncEvents()
+	defer sub.Close()
+
+	// once the missing event is injected, it is delivered to subscribers and commitment building proceeds
+	require.NoError(t, s.InsertValidatedStateSyncEvent(events[4], 100))
+	require.Equal(t, events[4], <-sub.GetEventCh())
+	require.Len(t, s.pendingCommitments, 2)
+	require.Equal(t, uint64(0), s.pendingCommitments[1].StartID.Uint64())
+	require.Equal(t, uint64(9), s.pendingCommitments[1].EndID.Uint64())
+}
+
+func TestStateSyncManager_BuildCommitment_EpochChangedWhileBuilding(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	for _, event := range generateStateSyncEvents(t, 10, 0) {
+		insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
+	}
+
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("GetNextCommittedIndex").Return(uint64(0), nil).Once()
+
+	var staleHash []byte
+
+	signerMock := new(aggregateSignerMock)
+	// epoch changes while the first commitment is being signed
+	signerMock.On("Sign", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		staleHash = args.Get(1).([]byte) //nolint:forcetypeassert
+
+		require.NoError(t, s.state.EpochStore.insertEpoch(1, nil))
+		require.NoError(t, s.PostEpoch(&PostEpochRequest{
+			NewEpochID:   1,
+			SystemState:  systemStateMock,
+			ValidatorSet: vals.ToValidatorSet(),
+		}))
+	}).Return([]byte{1}, nil).Once()
+	signerMock.On("Sign", mock.Anything, mock.Anything, mock.Anything).Return([]byte{2}, nil).Once()
+	s.config.aggregateSigner = signerMock
+
+	require.NoError(t, s.buildCommitment())
+
+	// only the commitment built by PostEpoch for the new epoch is kept
+	require.Len(t, s.pendingCommitments, 1)
+	require.Equal(t, uint64(1), s.pendingCommitments[0].Epoch)
+
+	pendingCommitments, err := s.state.StateSyncStore.getPendingCommitments()
+	require.NoError(t, err)
+	require.Len(t, pendingCommitments, 1)
+	require.Equal(t, uint64(1), pendingCommitments[0].Epoch)
+
+	// vote for the stale commitment is neither stored nor gossiped
+	votes, err := s.state.StateSyncStore.getMessageVotes(0, staleHash)
+	require.NoError(t, err)
+	require.Empty(t, votes)
+
+	published, ok := s.config.topic.(*mockTopic).consume().(*polybftProto.TransportMessage) //nolint:forcetypeassert
+	require.True(t, ok)
+
+	var msg TransportMessage
+
+	require.NoError(t, json.Unmarshal(published.Data, &msg))
+	require.Equal(t, uint64(1), msg.EpochNumber)
+	require.Equal(t, []byte{2}, msg.Signature)
+
+	signerMock.AssertExpectations(t)
+	systemStateMock.AssertExpectations(t)
+}
+
+func TestStateSyncManager_CheckStateSyncsContiguity(t *testing.T) {
+	t.Parallel()
+
+	events := generateStateSyncEvents(t, 5, 3)
+
+	require.NoError(t, checkStateSyncsContiguity(events, 3))
+	require.ErrorIs(t, checkStateSyncsContiguity(events, 2), errStateSyncsNotContiguous)
+
+	// gap between state syncs
+	require.ErrorIs(t, checkStateSyncsContiguity(append(events[:2:2], events[3:]...), 3), errStateSyncsNotContiguous)
+
+	// unordered state syncs
+	events[1], events[2] = events[2], events[1]
+	require.ErrorIs(t, checkStateSyncsContiguity(events, 3), errStateSyncsNotContiguous)
+}
+
+func TestStateSyncManager_PostSprint_AlignedCommitments(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.config.alignCommitments = true
+	s.validatorSet = vals.ToValidatorSet()
+
+	var stateSyncedEvent contractsapi.StateSyncedEvent
+
+	data, err := abi.MustNewType("tuple(string a)").Encode([]string{"data"})
+	require.NoError(t, err)
+
+	addLogs := func(from, to int64) {
+		t.Helper()
+
+		for i := from; i <= to; i++ {
+			s.AddLog(&ethgo.Log{
+				Topics: []ethgo.Hash{
+					stateSyncedEvent.Sig(),
+					ethgo.BytesToHash(big.NewInt(i).Bytes()),
+					ethgo.ZeroHash,
+					ethgo.ZeroHash,
+				},
+				Data: data,
+			})
+		}
+	}
+
+	// commitment of the sprint is built at the sprint end, rather than on arrival of each state sync
+	buildSprintCommitment := func(from, to uint64) {
+		t.Helper()
+
+		require.Empty(t, s.pendingCommitments)
+		require.NoError(t, s.PostSprint())
+		require.Len(t, s.pendingCommitments, 1)
+		require.Equal(t, from, s.pendingCommitments[0].StartID.Uint64())
+		require.Equal(t, to, s.pendingCommitments[0].EndID.Uint64())
+
+		// the commitment gets submitted
+		commitment := &CommitmentMessageSigned{
+			Message: &contractsapi.StateSyncCommitment{
+				StartID: s.pendingCommitments[0].StartID,
+				EndID:   s.pendingCommitments[0].EndID,
+				Root:    s.pendingCommitments[0].Root,
+			},
+		}
+		signTestCommitment(t, vals, commitment)
+
+		txData, err := commitment.EncodeAbi()
+		require.NoError(t, err)
+
+		require.NoError(t, s.PostBlock(&PostBlockRequest{
+			FullBlock: &types.FullBlock{
+				Block: &types.Block{
+					Transactions: []*types.Transaction{
+						createStateTransactionWithData(types.Address{}, txData),
+					},
+				},
+			},
+		}))
+		require.Equal(t, to+1, s.nextCommittedIndex)
+	}
+
+	// first sprint
+	addLogs(0, 3)
+	buildSprintCommitment(0, 3)
+
+	// second sprint
+	addLogs(4, 8)
+	buildSprintCommitment(4, 8)
+
+	// no commitment is built at the end of a sprint without new state syncs
+	require.NoError(t, s.PostSprint())
+	require.Empty(t, s.pendingCommitments)
+}
+
+func TestStateSyncManager_PostSprint_ForceCommitment(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+
+	// low state sync volume, which arrived after the previous commitment was submitted
+	// (state syncs are not added through AddLog, so no commitment is built on their arrival)
+	newManager := func(forceSprintCommitments bool) *stateSyncManager {
+		s := newTestStateSyncManager(t, vals.GetValidator("0"))
+		s.config.forceSprintCommitments = forceSprintCommitments
+		s.nextCommittedIndex = 5
+
+		for _, event := range generateStateSyncEvents(t, 2, 5) {
+			insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
+		}
+
+		return s
+	}
+
+	// forcing is disabled
+	s := newManager(false)
+	require.NoError(t, s.PostSprint())
+	require.Empty(t, s.pendingCommitments)
+
+	// forcing is enabled
+	s = newManager(true)
+	require.NoError(t, s.PostSprint())
+	require.Len(t, s.pendingCommitments, 1)
+	require.Equal(t, uint64(5), s.pendingCommitments[0].StartID.Uint64())
+	require.Equal(t, uint64(6), s.pendingCommitments[0].EndID.Uint64())
+
+	// commitment is not rebuilt in the next sprint if there are no new state syncs
+	require.NoError(t, s.PostSprint())
+	require.Len(t, s.pendingCommitments, 1)
+}
+
 func TestStateSyncManager_MessagePool_OldEpoch(t *testing.T) {
 	vals := validator.NewTestValidators(t, 5)
 
@@ -170,6 +618,137 @@ func TestStateSyncManager_MessagePool_InvalidEpoch(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestStateSyncManager_MessagePool_EpochTooFarAhead(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.validatorSet = vals.ToValidatorSet()
+	s.epoch = 3
+
+	msg, err := newMockMsg().sign(vals.GetValidator("1"), bls.DomainStateReceiver)
+	require.NoError(t, err)
+
+	// vote for an epoch far ahead of the current one is rejected before reaching the store
+	msg.EpochNumber = s.epoch + 100
+	require.ErrorIs(t, s.saveVote(msg), errVoteEpochTooFarAhead)
+
+	_, err = s.state.StateSyncStore.getMessageVotes(msg.EpochNumber, msg.Hash)
+	require.Error(t, err)
+
+	// vote for the next epoch is within the default lead
+	msg.EpochNumber = s.epoch + 1
+	require.NoError(t, s.saveVote(msg))
+
+	// lead is configurable
+	s.config.maxVoteEpochLead = 100
+	msg.EpochNumber = s.epoch + 100
+	require.NoError(t, s.saveVote(msg))
+}
+
+func TestStateSyncManager_MessagePool_VoteBeforeEpochChange(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 6)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.validatorSet = validator.NewValidatorSet(vals.GetPublicIdentities("0", "1", "2", "3", "4"),
+		hclog.NewNullLogger())
+
+	// validator which joins the validator set in the next epoch votes before the epoch changes locally
+	msg := newMockMsg()
+	msg.epoch = 1
+
+	signedMsg, err := msg.sign(vals.GetValidator("5"), bls.DomainStateReceiver)
+	require.NoError(t, err)
+	require.NoError(t, s.saveVote(signedMsg))
+
+	_, err = s.state.StateSyncStore.getMessageVotes(1, msg.hash)
+	require.Error(t, err)
+
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("GetNextCommittedIndex").Return(uint64(0), nil).Once()
+
+	require.NoError(t, s.state.EpochStore.insertEpoch(1, nil))
+	require.NoError(t, s.PostEpoch(&PostEpochRequest{
+		NewEpochID:   1,
+		SystemState:  systemStateMock,
+		ValidatorSet: validator.NewValidatorSet(vals.GetPublicIdentities("1", "2", "3", "4", "5"), hclog.NewNullLogger()),
+	}))
+
+	// buffered vote is counted once the validator set of its epoch is known
+	votes, err := s.state.StateSyncStore.getMessageVotes(1, msg.hash)
+	require.NoError(t, err)
+	require.Len(t, votes, 1)
+	require.Equal(t, vals.GetValidator("5").Address().String(), votes[0].From)
+	require.Empty(t, s.futureVotes)
+}
+
+func TestStateSyncManager_MessagePool_FutureVotesLimit(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 6)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.validatorSet = validator.NewValidatorSet(vals.GetPublicIdentities("0", "1", "2", "3"), hclog.NewNullLogger())
+
+	newFutureVote := func(msg *mockMsg, alias string) *TransportMessage {
+		t.Helper()
+
+		msg.epoch = s.epoch + 1
+
+		signedMsg, err := msg.sign(vals.GetValidator(alias), bls.DomainStateReceiver)
+		require.NoError(t, err)
+
+		return signedMsg
+	}
+
+	realMsg := newMockMsg()
+
+	// peer forges senders of the votes for the genuine message hash, until its quota is reached
+	for i := 0; i < maxFutureVotesPerHash; i++ {
+		forgedVote := newFutureVote(realMsg, "4")
+		forgedVote.From = types.Address{0xff, byte(i)}.String()
+		require.NoError(t, s.saveVote(forgedVote))
+	}
+
+	// and fills the rest of the buffer with votes of forged senders for arbitrary hashes
+	for i := maxFutureVotesPerHash; i < maxFutureVotes; i++ {
+		forgedVote := newFutureVote(newMockMsg(), "4")
+		forgedVote.From = types.Address{0xff, byte(i), byte(i >> 8)}.String()
+		require.NoError(t, s.saveVote(forgedVote))
+	}
+
+	require.Len(t, s.futureVotes, maxFutureVotes)
+
+	// vote impersonating a current validator is not buffered
+	spoofedMsg := newFutureVote(newMockMsg(), "4")
+	spoofedMsg.From = vals.GetValidator("2").Address().String()
+	require.ErrorContains(t, s.saveVote(spoofedMsg), "error verifying vote signature")
+
+	// genuine vote of the validator joining the validator set in the next epoch is still buffered
+	require.NoError(t, s.saveVote(newFutureVote(realMsg, "5")))
+	require.Len(t, s.futureVotes, maxFutureVotes)
+
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("GetNextCommittedIndex").Return(uint64(0), nil).Once()
+
+	require.NoError(t, s.state.EpochStore.insertEpoch(1, nil))
+	require.NoError(t, s.PostEpoch(&PostEpochRequest{
+		NewEpochID:   1,
+		SystemState:  systemStateMock,
+		ValidatorSet: validator.NewValidatorSet(vals.GetPublicIdentities("1", "2", "3", "4", "5"), hclog.NewNullLogger()),
+	}))
+
+	// forged votes are discarded, while the genuine one is counted
+	votes, err := s.state.StateSyncStore.getMessageVotes(1, realMsg.hash)
+	require.NoError(t, err)
+	require.Len(t, votes, 1)
+	require.Equal(t, vals.GetValidator("5").Address().String(), votes[0].From)
+	require.Empty(t, s.futureVotes)
+}
+
 func TestStateSyncManager_MessagePool_SenderAndSignatureMissmatch(t *testing.T) {
 	t.Parallel()
 
@@ -287,46 +866,1075 @@ func TestStateSyncManager_BuildCommitment(t *testing.T) {
 	require.NotNil(t, commitment)
 }
 
-func TestStateSyncerManager_BuildProofs(t *testing.T) {
-	vals := validator.NewTestValidators(t, 5)
+func TestStateSyncManager_Commitment_QuorumWait(t *testing.T) {
+	// metrics sink is global, so the test does not run in parallel
+	sink := metrics.NewInmemSink(time.Minute, time.Minute)
+	metricsConfig := metrics.DefaultConfig("")
+	metricsConfig.EnableHostname = false
+	metricsConfig.EnableRuntimeMetrics = false
 
-	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	_, err := metrics.NewGlobal(metricsConfig, sink)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, _ = metrics.NewGlobal(metrics.DefaultConfig(""), &metrics.BlackholeSink{})
+	})
+
+	quorumWaitGauge := func() float32 {
+		t.Helper()
+
+		intervals := sink.Data()
+		require.NotEmpty(t, intervals)
+
+		gauge, ok := intervals[len(intervals)-1].Gauges["bridge.commitment_quorum_wait"]
+		require.True(t, ok)
+
+		return gauge.Value
+	}
+
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.validatorSet = vals.ToValidatorSet()
+	s.config.quorumWaitWarnThreshold = time.Minute
+
+	events := generateStateSyncEvents(t, 10, 0)
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, events[:5]...)
+
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 1)
+
+	// commitment has been awaiting quorum for a while, but not beyond the warning threshold
+	s.quorumWait.since = time.Now().Add(-30 * time.Second)
+
+	commitment, err := s.Commitment()
+	require.NoError(t, err)
+	require.Nil(t, commitment)
+	require.GreaterOrEqual(t, quorumWaitGauge(), float32(30))
+	require.Less(t, quorumWaitGauge(), float32(60))
+	require.False(t, s.quorumWait.warned)
+
+	s.quorumWait.since = time.Now().Add(-2 * time.Minute)
+
+	commitment, err = s.Commitment()
+	require.NoError(t, err)
+	require.Nil(t, commitment)
+	require.GreaterOrEqual(t, quorumWaitGauge(), float32(120))
+	require.True(t, s.quorumWait.warned)
+
+	// a new commitment supersedes the old one, so the waiting time is reset
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, events[5:]...)
+
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 2)
+
+	commitment, err = s.Commitment()
+	require.NoError(t, err)
+	require.Nil(t, commitment)
+	require.Less(t, quorumWaitGauge(), float32(30))
+	require.False(t, s.quorumWait.warned)
+}
+
+func TestStateSyncManager_Commitment_ConcurrentBuild(t *testing.T) {
+	t.Parallel()
+
+	const eventsCount = 10
+
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.validatorSet = vals.ToValidatorSet()
+
+	var (
+		wg      sync.WaitGroup
+		done    = make(chan struct{})
+		errorCh = make(chan error, 4)
+	)
+
+	// commitments are built while state sync events arrive one by one
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer close(done)
+
+		for _, event := range generateStateSyncEvents(t, eventsCount, 0) {
+			if _, err := s.state.StateSyncStore.insertStateSyncEvent(event); err != nil {
+				errorCh <- err
+
+				return
+			}
+
+			if err := s.buildCommitment(); err != nil {
+				errorCh <- err
+
+				return
+			}
+		}
+	}()
+
+	// and they are queried concurrently
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if _, err := s.Commitment(); err != nil {
+					errorCh <- err
+
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errorCh)
+
+	for err := range errorCh {
+		require.NoError(t, err)
+	}
+
+	require.Len(t, s.pendingCommitments, eventsCount)
+
+	// the largest commitment gets a quorum of votes
+	hash, err := s.pendingCommitments[eventsCount-1].Hash()
+	require.NoError(t, err)
+
+	msg := newMockMsg().WithHash(hash.Bytes())
+
+	for _, alias := range []string{"1", "2", "3"} {
+		signedMsg, err := msg.sign(vals.GetValidator(alias), bls.DomainStateReceiver)
+		require.NoError(t, err)
+		require.NoError(t, s.saveVote(signedMsg))
+	}
+
+	commitment, err := s.Commitment()
+	require.NoError(t, err)
+	require.NotNil(t, commitment)
+	require.Equal(t, uint64(eventsCount-1), commitment.Message.EndID.Uint64())
+}
+
+func TestStateSyncManager_Commitment_QuorumNotReachedCount(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.validatorSet = vals.ToValidatorSet()
+
+	commitment, err := NewPendingCommitment(0, generateStateSyncEvents(t, 5, 0), maxCommitmentSize)
+	require.NoError(t, err)
+
+	s.pendingCommitments = []*PendingCommitment{commitment}
+
+	hash, err := commitment.Hash()
+	require.NoError(t, err)
+
+	msg := newMockMsg().WithHash(hash.Bytes())
+
+	// only validators 0 and 1 vote for the commitment, so it is under quorum
+	for _, alias := range []string{"0", "1"} {
+		signedMsg, err := msg.sign(vals.GetValidator(alias), bls.DomainStateReceiver)
+		require.NoError(t, err)
+		require.NoError(t, s.saveVote(signedMsg))
+	}
+
+	for i := uint64(1); i <= 3; i++ {
+		commitmentToRegister, err := s.Commitment()
+		require.NoError(t, err)
+		require.Nil(t, commitmentToRegister)
+		require.Equal(t, i, s.quorumNotReachedCount.Load())
+	}
+
+	// counter is per epoch
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("GetNextCommittedIndex").Return(uint64(0), nil).Once()
+
+	require.NoError(t, s.PostEpoch(&PostEpochRequest{
+		NewEpochID:   1,
+		SystemState:  systemStateMock,
+		ValidatorSet: vals.ToValidatorSet(),
+	}))
+	require.Equal(t, uint64(0), s.quorumNotReachedCount.Load())
+}
+
+func TestStateSyncManager_LastVoteTimes(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.validatorSet = vals.ToValidatorSet()
+
+	msg := newMockMsg()
+
+	vote := func(alias string) {
+		t.Helper()
+
+		signedMsg, err := msg.sign(vals.GetValidator(alias), bls.DomainStateReceiver)
+		require.NoError(t, err)
+		require.NoError(t, s.saveVote(signedMsg))
+	}
+
+	before := time.Now().UTC()
+
+	vote("0")
+	vote("1")
+
+	lastVoteTimes := s.LastVoteTimes()
+	require.Len(t, lastVoteTimes, 2)
+
+	firstVoteTime := lastVoteTimes[vals.GetValidator("0").Address()]
+	require.False(t, firstVoteTime.Before(before))
+	require.False(t, lastVoteTimes[vals.GetValidator("1").Address()].Before(before))
+
+	// non-voters are absent
+	_, ok := lastVoteTimes[vals.GetValidator("2").Address()]
+	require.False(t, ok)
+
+	// voting again updates the timestamp
+	time.Sleep(time.Millisecond)
+	vote("0")
+	require.True(t, s.LastVoteTimes()[vals.GetValidator("0").Address()].After(firstVoteTime))
+
+	// validators which left the validator set are removed
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("GetNextCommittedIndex").Return(uint64(0), nil).Once()
+
+	require.NoError(t, s.state.EpochStore.insertEpoch(1, nil))
+	require.NoError(t, s.PostEpoch(&PostEpochRequest{
+		NewEpochID:   1,
+		SystemState:  systemStateMock,
+		ValidatorSet: validator.NewValidatorSet(vals.GetPublicIdentities("0", "2", "3", "4"), hclog.NewNullLogger()),
+	}))
+
+	lastVoteTimes = s.LastVoteTimes()
+	require.Len(t, lastVoteTimes, 1)
+	require.Contains(t, lastVoteTimes, vals.GetValidator("0").Address())
+}
+
+func TestStateSyncManager_Commitment_StaleCommitment(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.validatorSet = vals.ToValidatorSet()
+
+	commitment, err := NewPendingCommitment(0, generateStateSyncEvents(t, 5, 0), maxCommitmentSize)
+	require.NoError(t, err)
+
+	s.pendingCommitments = []*PendingCommitment{commitment}
+	require.NoError(t, s.state.StateSyncStore.insertPendingCommitment(commitment))
+
+	hash, err := commitment.Hash()
+	require.NoError(t, err)
+
+	msg := newMockMsg().WithHash(hash.Bytes())
+
+	// commitment reaches the quorum
+	for _, alias := range []string{"0", "1", "2", "3"} {
+		signedMsg, err := msg.sign(vals.GetValidator(alias), bls.DomainStateReceiver)
+		require.NoError(t, err)
+		require.NoError(t, s.saveVote(signedMsg))
+	}
+
+	commitmentToRegister, err := s.Commitment()
+	require.NoError(t, err)
+	require.NotNil(t, commitmentToRegister)
+
+	// state syncs from the commitment are already committed,
+	// so it no longer starts at the next committed index
+	s.nextCommittedIndex = 2
+
+	// stale commitment is skipped and discarded, instead of failing the block building
+	commitmentToRegister, err = s.Commitment()
+	require.NoError(t, err)
+	require.Nil(t, commitmentToRegister)
+	require.Empty(t, s.pendingCommitments)
+
+	storedCommitments, err := s.state.StateSyncStore.getPendingCommitments()
+	require.NoError(t, err)
+	require.Empty(t, storedCommitments)
+}
+
+func TestStateSyncerManager_BuildProofs(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	for _, evnt := range generateStateSyncEvents(t, 20, 0) {
+		insertTestStateSyncEvents(t, s.state.StateSyncStore, evnt)
+	}
+
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 1)
+
+	s.validatorSet = vals.ToValidatorSet()
+
+	mockMsg := &CommitmentMessageSigned{
+		Message: &contractsapi.StateSyncCommitment{
+			StartID: s.pendingCommitments[0].StartID,
+			EndID:   s.pendingCommitments[0].EndID,
+		},
+	}
+	signTestCommitment(t, vals, mockMsg)
+
+	txData, err := mockMsg.EncodeAbi()
+	require.NoError(t, err)
+
+	tx := createStateTransactionWithData(types.Address{}, txData)
+
+	req := &PostBlockRequest{
+		FullBlock: &types.FullBlock{
+			Block: &types.Block{
+				Transactions: []*types.Transaction{tx},
+			},
+		},
+	}
+
+	require.NoError(t, s.PostBlock(req))
+	require.Equal(t, mockMsg.Message.EndID.Uint64()+1, s.nextCommittedIndex)
+
+	for i := uint64(0); i < 10; i++ {
+		proof, err := s.state.StateSyncStore.getStateSyncProof(i)
+		require.NoError(t, err)
+		require.NotNil(t, proof)
+	}
+}
+
+func TestStateSyncerManager_BuildProofs_FinalityDepth(t *testing.T) {
+	t.Parallel()
+
+	const (
+		finalityDepth    = 3
+		commitmentBlock  = 10
+		commitmentEvents = 10
+	)
+
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.config.proofFinalityDepth = finalityDepth
+	s.validatorSet = vals.ToValidatorSet()
+
+	for _, event := range generateStateSyncEvents(t, commitmentEvents, 0) {
+		insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
+	}
+
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 1)
+
+	commitment := &CommitmentMessageSigned{Message: s.pendingCommitments[0].StateSyncCommitment}
+	signTestCommitment(t, vals, commitment)
+
+	txData, err := commitment.EncodeAbi()
+	require.NoError(t, err)
+
+	postBlock := func(number uint64, txs ...*types.Transaction) {
+		t.Helper()
+
+		require.NoError(t, s.PostBlock(&PostBlockRequest{
+			FullBlock: &types.FullBlock{
+				Block: &types.Block{Header: &types.Header{Number: number}, Transactions: txs},
+			},
+		}))
+	}
+
+	postBlock(commitmentBlock, createStateTransactionWithData(types.Address{}, txData))
+	// next committed index is updated right away, so that a new commitment can be built
+	require.Equal(t, commitment.Message.EndID.Uint64()+1, s.nextCommittedIndex)
+	require.Len(t, s.pendingCommitments, 0)
+
+	// queued commitment survives the restart
+	s.pendingProofs = nil
+	require.NoError(t, s.loadPendingProofs())
+	require.Len(t, s.pendingProofs, 1)
+
+	for i := uint64(commitmentBlock + 1); i < commitmentBlock+finalityDepth; i++ {
+		postBlock(i)
+
+		proof, err := s.state.StateSyncStore.getStateSyncProof(0)
+		require.NoError(t, err)
+		require.Nil(t, proof)
+	}
+
+	postBlock(commitmentBlock + finalityDepth)
+	require.Len(t, s.pendingProofs, 0)
+
+	storedPendingProofs, err := s.state.StateSyncStore.getPendingProofs()
+	require.NoError(t, err)
+	require.Len(t, storedPendingProofs, 0)
+
+	for i := uint64(0); i < commitmentEvents; i++ {
+		proof, err := s.state.StateSyncStore.getStateSyncProof(i)
+		require.NoError(t, err)
+		require.NotNil(t, proof)
+	}
+}
+
+func TestStateSyncerManager_BuildProofs_FinalityDepth_Reorg(t *testing.T) {
+	t.Parallel()
+
+	const (
+		finalityDepth    = 3
+		commitmentBlock  = 10
+		commitmentEvents = 10
+	)
+
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.config.proofFinalityDepth = finalityDepth
+	s.validatorSet = vals.ToValidatorSet()
+
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, generateStateSyncEvents(t, commitmentEvents, 0)...)
+
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 1)
+
+	commitment := &CommitmentMessageSigned{Message: s.pendingCommitments[0].StateSyncCommitment}
+	signTestCommitment(t, vals, commitment)
+
+	txData, err := commitment.EncodeAbi()
+	require.NoError(t, err)
+
+	commitmentTx := createStateTransactionWithData(types.Address{}, txData)
+
+	postBlock := func(number uint64, txs ...*types.Transaction) {
+		t.Helper()
+
+		require.NoError(t, s.PostBlock(&PostBlockRequest{
+			FullBlock: &types.FullBlock{
+				Block: &types.Block{Header: &types.Header{Number: number}, Transactions: txs},
+			},
+		}))
+	}
+
+	postBlock(commitmentBlock, commitmentTx)
+	postBlock(commitmentBlock + 1)
+	require.Equal(t, commitment.Message.EndID.Uint64()+1, s.nextCommittedIndex)
+
+	// commitment block is replaced by a block of the new canonical fork, which does not contain the commitment
+	postBlock(commitmentBlock)
+	require.Len(t, s.pendingProofs, 0)
+	require.Equal(t, uint64(0), s.nextCommittedIndex)
+
+	storedPendingProofs, err := s.state.StateSyncStore.getPendingProofs()
+	require.NoError(t, err)
+	require.Len(t, storedPendingProofs, 0)
+
+	// commitment is submitted again on the new fork, and its proofs are built once it is buried deep enough
+	postBlock(commitmentBlock+1, commitmentTx)
+
+	for i := uint64(commitmentBlock + 2); i < commitmentBlock+1+finalityDepth; i++ {
+		postBlock(i)
+
+		proof, err := s.state.StateSyncStore.getStateSyncProof(0)
+		require.NoError(t, err)
+		require.Nil(t, proof)
+	}
+
+	postBlock(commitmentBlock + 1 + finalityDepth)
+	require.Len(t, s.pendingProofs, 0)
+
+	proof, err := s.state.StateSyncStore.getStateSyncProof(0)
+	require.NoError(t, err)
+	require.NotNil(t, proof)
+}
+
+func TestStateSyncManager_Status(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.validatorSet = vals.ToValidatorSet()
+	s.config.quorumWaitWarnThreshold = time.Minute
+
+	require.Equal(t, &StateSyncStatus{QuorumReachable: true}, s.Status())
+
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, generateStateSyncEvents(t, 5, 0)...)
+	require.NoError(t, s.buildCommitment())
+
+	status := s.Status()
+	require.Equal(t, 1, status.PendingCommitments)
+	require.True(t, status.QuorumReachable)
+
+	// commitment awaits quorum beyond the warning threshold
+	s.quorumWait.since = time.Now().Add(-2 * time.Minute)
+
+	commitment, err := s.Commitment()
+	require.NoError(t, err)
+	require.Nil(t, commitment)
+
+	status = s.Status()
+	require.Equal(t, 1, status.PendingCommitments)
+	require.False(t, status.QuorumReachable)
+	require.GreaterOrEqual(t, status.QuorumWait, 2*time.Minute)
+
+	// quorum gets reached eventually
+	hash, err := s.pendingCommitments[0].Hash()
+	require.NoError(t, err)
+
+	for _, alias := range []string{"1", "2", "3"} {
+		signedMsg, err := newMockMsg().WithHash(hash.Bytes()).sign(vals.GetValidator(alias), bls.DomainStateReceiver)
+		require.NoError(t, err)
+		require.NoError(t, s.saveVote(signedMsg))
+	}
+
+	commitment, err = s.Commitment()
+	require.NoError(t, err)
+	require.NotNil(t, commitment)
+	require.True(t, s.Status().QuorumReachable)
+}
+
+func TestStateSyncManager_PostEpoch_NoCommitment(t *testing.T) {
+	// metrics sink is global, so the test does not run in parallel
+	sink := metrics.NewInmemSink(time.Minute, time.Minute)
+	metricsConfig := metrics.DefaultConfig("")
+	metricsConfig.EnableHostname = false
+	metricsConfig.EnableRuntimeMetrics = false
+
+	_, err := metrics.NewGlobal(metricsConfig, sink)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_, _ = metrics.NewGlobal(metrics.DefaultConfig(""), &metrics.BlackholeSink{})
+	})
+
+	counter := func(name string) int {
+		t.Helper()
+
+		intervals := sink.Data()
+		require.NotEmpty(t, intervals)
+
+		value, ok := intervals[len(intervals)-1].Counters[name]
+		if !ok {
+			return 0
+		}
+
+		return value.Count
+	}
+
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("GetNextCommittedIndex").Return(uint64(0), nil)
+
+	postEpoch := func(epoch uint64) {
+		require.NoError(t, s.state.EpochStore.insertEpoch(epoch, nil))
+		require.NoError(t, s.PostEpoch(&PostEpochRequest{
+			NewEpochID:   epoch,
+			SystemState:  systemStateMock,
+			ValidatorSet: vals.ToValidatorSet(),
+		}))
+	}
+
+	// epoch without any new state sync events
+	postEpoch(1)
+	require.Empty(t, s.pendingCommitments)
+	require.Equal(t, 1, counter("bridge.epoch_without_commitment"))
+	require.Equal(t, 0, counter("bridge.epoch_commitment_build_failed"))
+
+	// epoch with new state sync events
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, generateStateSyncEvents(t, 5, 0)...)
+
+	postEpoch(2)
+	require.Len(t, s.pendingCommitments, 1)
+	require.Equal(t, 1, counter("bridge.epoch_without_commitment"))
+	require.Equal(t, 0, counter("bridge.epoch_commitment_build_failed"))
+}
+
+func TestStateSyncManager_ReconcileCommitmentGap(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+
+	// restored db contains only the first commitment, while the contract already committed three of them
+	onChainCommitments := []*contractsapi.StateSyncCommitment{
+		{StartID: big.NewInt(1), EndID: big.NewInt(5), Root: types.StringToHash("0x1")},
+		{StartID: big.NewInt(6), EndID: big.NewInt(10), Root: types.StringToHash("0x2")},
+		{StartID: big.NewInt(11), EndID: big.NewInt(15), Root: types.StringToHash("0x3")},
+	}
+
+	setup := func(t *testing.T, policy string, nextCommittedIndex uint64) (*stateSyncManager, *systemStateMock) {
+		t.Helper()
+
+		s := newTestStateSyncManager(t, vals.GetValidator("0"))
+		s.config.commitmentGapPolicy = policy
+
+		require.NoError(t, s.state.StateSyncStore.insertCommitmentMessage(
+			&CommitmentMessageSigned{Message: onChainCommitments[0]}))
+
+		systemStateMock := new(systemStateMock)
+		systemStateMock.On("GetNextCommittedIndex").Return(nextCommittedIndex, nil)
+
+		return s, systemStateMock
+	}
+
+	t.Run("gap is only logged by default", func(t *testing.T) {
+		t.Parallel()
+
+		s, systemStateMock := setup(t, "", 16)
+		require.NoError(t, s.reconcileCommitmentGap(systemStateMock))
+
+		commitments, err := s.state.StateSyncStore.getCommitmentMessages(1, 15)
+		require.NoError(t, err)
+		require.Len(t, commitments, 1)
+
+		systemStateMock.AssertExpectations(t)
+	})
+
+	t.Run("strict policy refuses to start", func(t *testing.T) {
+		t.Parallel()
+
+		s, systemStateMock := setup(t, StrictCommitmentGapPolicy, 16)
+
+		err := s.reconcileCommitmentGap(systemStateMock)
+		require.ErrorIs(t, err, errCommitmentGap)
+		require.ErrorContains(t, err, "from 6 to 15")
+
+		systemStateMock.AssertExpectations(t)
+	})
+
+	t.Run("strict policy accepts commitments waiting for the proof finality depth", func(t *testing.T) {
+		t.Parallel()
+
+		s, systemStateMock := setup(t, StrictCommitmentGapPolicy, 16)
+		require.NoError(t, s.state.StateSyncStore.insertPendingProof(20,
+			&CommitmentMessageSigned{Message: onChainCommitments[2]}))
+		require.NoError(t, s.loadPendingProofs())
+
+		require.NoError(t, s.reconcileCommitmentGap(systemStateMock))
+	})
+
+	t.Run("fresh node has nothing to reconcile", func(t *testing.T) {
+		t.Parallel()
+
+		s := newTestStateSyncManager(t, vals.GetValidator("0"))
+		s.config.commitmentGapPolicy = StrictCommitmentGapPolicy
+
+		systemStateMock := new(systemStateMock)
+		systemStateMock.On("GetNextCommittedIndex").Return(uint64(16), nil)
+
+		require.NoError(t, s.reconcileCommitmentGap(systemStateMock))
+	})
+
+	t.Run("recover policy fetches missing commitments", func(t *testing.T) {
+		t.Parallel()
+
+		s, systemStateMock := setup(t, RecoverCommitmentGapPolicy, 16)
+		systemStateMock.On("GetCommitmentByStateSyncID", uint64(15)).Return(onChainCommitments[2], nil).Once()
+		systemStateMock.On("GetCommitmentByStateSyncID", uint64(10)).Return(onChainCommitments[1], nil).Once()
+
+		require.NoError(t, s.reconcileCommitmentGap(systemStateMock))
+
+		commitments, err := s.state.StateSyncStore.getCommitmentMessages(1, 15)
+		require.NoError(t, err)
+		require.Len(t, commitments, 3)
+
+		for i, commitment := range commitments {
+			require.Equal(t, onChainCommitments[i].Root, commitment.Message.Root)
+		}
+
+		systemStateMock.AssertExpectations(t)
+	})
+
+	t.Run("recover policy fetches a bounded number of the most recent commitments", func(t *testing.T) {
+		t.Parallel()
+
+		const nextCommittedIndex = 6 + 2*maxRecoveredCommitments
+
+		s, systemStateMock := setup(t, RecoverCommitmentGapPolicy, nextCommittedIndex)
+
+		// each state sync of the gap is committed by its own commitment
+		for id := uint64(nextCommittedIndex - maxRecoveredCommitments); id < nextCommittedIndex; id++ {
+			systemStateMock.On("GetCommitmentByStateSyncID", id).Return(&contractsapi.StateSyncCommitment{
+				StartID: new(big.Int).SetUint64(id),
+				EndID:   new(big.Int).SetUint64(id),
+			}, nil).Once()
+		}
+
+		require.NoError(t, s.reconcileCommitmentGap(systemStateMock))
+
+		commitments, err := s.state.StateSyncStore.getCommitmentMessages(6, nextCommittedIndex-1)
+		require.NoError(t, err)
+		require.Len(t, commitments, maxRecoveredCommitments)
+
+		systemStateMock.AssertExpectations(t)
+		systemStateMock.AssertNumberOfCalls(t, "GetCommitmentByStateSyncID", maxRecoveredCommitments)
+	})
+
+	t.Run("recover policy rejects unexpected commitment", func(t *testing.T) {
+		t.Parallel()
+
+		s, systemStateMock := setup(t, RecoverCommitmentGapPolicy, 16)
+		systemStateMock.On("GetCommitmentByStateSyncID", uint64(15)).Return(onChainCommitments[1], nil).Once()
+
+		require.ErrorIs(t, s.reconcileCommitmentGap(systemStateMock), errCommitmentGap)
+
+		systemStateMock.AssertExpectations(t)
+	})
+}
+
+func TestStateSyncManager_PostEpoch_ReconcileNextCommittedIndex(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	for _, event := range generateStateSyncEvents(t, 15, 1) {
+		insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
+	}
+
+	systemStateMock := new(systemStateMock)
+	systemStateMock.On("GetNextCommittedIndex").Return(uint64(1), nil).Twice()
+
+	postEpoch := func(epoch uint64) {
+		require.NoError(t, s.state.EpochStore.insertEpoch(epoch, nil))
+		require.NoError(t, s.PostEpoch(&PostEpochRequest{
+			NewEpochID:   epoch,
+			SystemState:  systemStateMock,
+			ValidatorSet: vals.ToValidatorSet(),
+		}))
+	}
+
+	postEpoch(1)
+	require.Equal(t, uint64(1), s.nextCommittedIndex)
+	require.Len(t, s.pendingCommitments, 1)
+
+	// commitment gets into a block, however its transaction reverts on-chain
+	commitment := &CommitmentMessageSigned{Message: s.pendingCommitments[0].StateSyncCommitment}
+	signTestCommitment(t, vals, commitment)
+
+	txData, err := commitment.EncodeAbi()
+	require.NoError(t, err)
+
+	require.NoError(t, s.PostBlock(&PostBlockRequest{
+		FullBlock: &types.FullBlock{
+			Block: &types.Block{
+				Transactions: []*types.Transaction{createStateTransactionWithData(types.Address{}, txData)},
+			},
+		},
+	}))
+
+	// local next committed index is ahead of the on-chain one now
+	require.Equal(t, commitment.Message.EndID.Uint64()+1, s.nextCommittedIndex)
+
+	proof, err := s.state.StateSyncStore.getStateSyncProof(1)
+	require.NoError(t, err)
+	require.NotNil(t, proof)
+
+	postEpoch(2)
+
+	// local index is corrected and commitments are rebuilt from the on-chain index
+	require.Equal(t, uint64(1), s.nextCommittedIndex)
+	require.Len(t, s.pendingCommitments, 1)
+	require.Equal(t, uint64(1), s.pendingCommitments[0].StartID.Uint64())
+
+	storedCommitment, err := s.state.StateSyncStore.getCommitmentMessage(commitment.Message.EndID.Uint64())
+	require.NoError(t, err)
+	require.Nil(t, storedCommitment)
+
+	proof, err = s.state.StateSyncStore.getStateSyncProof(1)
+	require.NoError(t, err)
+	require.Nil(t, proof)
+
+	systemStateMock.AssertExpectations(t)
+}
+
+func TestStateSyncManager_PostBlock_VerifyCommitmentSignature(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+
+	setup := func(t *testing.T) (*stateSyncManager, *CommitmentMessageSigned) {
+		t.Helper()
+
+		s := newTestStateSyncManager(t, vals.GetValidator("0"))
+		s.validatorSet = vals.ToValidatorSet()
+
+		for _, event := range generateStateSyncEvents(t, 10, 0) {
+			insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
+		}
+
+		require.NoError(t, s.buildCommitment())
+		require.Len(t, s.pendingCommitments, 1)
+
+		return s, &CommitmentMessageSigned{Message: s.pendingCommitments[0].StateSyncCommitment}
+	}
+
+	postBlock := func(s *stateSyncManager, commitment *CommitmentMessageSigned) error {
+		txData, err := commitment.EncodeAbi()
+		require.NoError(t, err)
+
+		return s.PostBlock(&PostBlockRequest{
+			FullBlock: &types.FullBlock{
+				Block: &types.Block{
+					Header:       &types.Header{Number: 1},
+					Transactions: []*types.Transaction{createStateTransactionWithData(types.Address{}, txData)},
+				},
+			},
+		})
+	}
+
+	t.Run("valid aggregated signature", func(t *testing.T) {
+		t.Parallel()
+
+		s, commitment := setup(t)
+		signTestCommitment(t, vals, commitment)
+
+		require.NoError(t, postBlock(s, commitment))
+		require.Equal(t, uint64(10), s.nextCommittedIndex)
+
+		stored, err := s.state.StateSyncStore.getCommitmentMessage(9)
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+	})
+
+	t.Run("tampered aggregated signature", func(t *testing.T) {
+		t.Parallel()
+
+		s, commitment := setup(t)
+
+		var logs bytes.Buffer
+		s.logger = hclog.New(&hclog.LoggerOptions{Output: &logs})
+
+		// signature is created for a different commitment
+		signature := createSignature(t, vals.GetPrivateIdentities(), types.StringToHash("0x1"), bls.DomainStateReceiver)
+		commitment.AggSignature = *signature
+
+		// the block is finalized, so its commitment is processed and the mismatch is only reported
+		require.NoError(t, postBlock(s, commitment))
+		require.Contains(t, logs.String(), "commitment signature does not match the local validator set")
+		require.Equal(t, uint64(10), s.nextCommittedIndex)
+		require.Empty(t, s.pendingCommitments)
+
+		stored, err := s.state.StateSyncStore.getCommitmentMessage(9)
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+	})
+}
+
+func TestStateSyncManager_PostBlock_CommitmentResubmission(t *testing.T) {
+	t.Parallel()
+
+	const resubmissionTimeout = 3
+
+	vals := validator.NewTestValidators(t, 5)
+
+	setup := func(t *testing.T) (*stateSyncManager, *CommitmentMessageSigned, *types.Transaction) {
+		t.Helper()
+
+		s := newTestStateSyncManager(t, vals.GetValidator("0"))
+		s.config.resubmissionTimeout = resubmissionTimeout
+		s.validatorSet = vals.ToValidatorSet()
+
+		for _, event := range generateStateSyncEvents(t, 10, 0) {
+			insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
+		}
+
+		require.NoError(t, s.buildCommitment())
+		require.Len(t, s.pendingCommitments, 1)
+
+		commitment := &CommitmentMessageSigned{Message: s.pendingCommitments[0].StateSyncCommitment}
+		signTestCommitment(t, vals, commitment)
+
+		txData, err := commitment.EncodeAbi()
+		require.NoError(t, err)
+
+		return s, commitment, createStateTransactionWithData(types.Address{}, txData)
+	}
+
+	postBlock := func(t *testing.T, s *stateSyncManager, number uint64, receipts []*types.Receipt,
+		txs ...*types.Transaction) {
+		t.Helper()
+
+		require.NoError(t, s.PostBlock(&PostBlockRequest{
+			FullBlock: &types.FullBlock{
+				Block:    &types.Block{Header: &types.Header{Number: number}, Transactions: txs},
+				Receipts: receipts,
+			},
+		}))
+	}
+
+	t.Run("dropped submission is resubmitted", func(t *testing.T) {
+		t.Parallel()
+
+		s, commitment, tx := setup(t)
+
+		// commitment is included, but its registration is never confirmed
+		postBlock(t, s, 5, nil, tx)
+
+		for i := uint64(6); i < 5+resubmissionTimeout; i++ {
+			postBlock(t, s, i, nil)
+
+			resubmitted, err := s.Commitment()
+			require.NoError(t, err)
+			require.Nil(t, resubmitted)
+		}
+
+		postBlock(t, s, 5+resubmissionTimeout, nil)
+
+		resubmitted, err := s.Commitment()
+		require.NoError(t, err)
+		require.NotNil(t, resubmitted)
+		require.Equal(t, commitment.Message.StartID.Uint64(), resubmitted.Message.StartID.Uint64())
+		require.Equal(t, commitment.Message.EndID.Uint64(), resubmitted.Message.EndID.Uint64())
+		require.Equal(t, commitment.Message.Root, resubmitted.Message.Root)
+
+		// resubmission gets included and confirmed, so there is nothing to resubmit anymore
+		postBlock(t, s, 6+resubmissionTimeout,
+			[]*types.Receipt{createNewCommitmentReceipt(t, commitment.Message)}, tx)
+		require.Nil(t, s.submittedCommitment)
+
+		resubmitted, err = s.Commitment()
+		require.NoError(t, err)
+		require.Nil(t, resubmitted)
+	})
+
+	t.Run("confirmed submission", func(t *testing.T) {
+		t.Parallel()
+
+		s, commitment, tx := setup(t)
+
+		postBlock(t, s, 5, []*types.Receipt{createNewCommitmentReceipt(t, commitment.Message)}, tx)
+		require.Nil(t, s.submittedCommitment)
+	})
+
+	t.Run("late confirmation prevents double submission", func(t *testing.T) {
+		t.Parallel()
+
+		s, commitment, tx := setup(t)
+
+		postBlock(t, s, 5, nil, tx)
+		postBlock(t, s, 5+resubmissionTimeout, nil)
+		require.True(t, s.submittedCommitment.resubmit)
+
+		postBlock(t, s, 6+resubmissionTimeout, []*types.Receipt{createNewCommitmentReceipt(t, commitment.Message)})
+
+		resubmitted, err := s.Commitment()
+		require.NoError(t, err)
+		require.Nil(t, resubmitted)
+	})
+}
+
+// signTestCommitment sets the aggregated signature of all the given validators on the commitment
+func signTestCommitment(t *testing.T, vals *validator.TestValidators, commitment *CommitmentMessageSigned) {
+	t.Helper()
+
+	hash, err := commitment.Hash()
+	require.NoError(t, err)
+
+	commitment.AggSignature = *createSignature(t, vals.GetPrivateIdentities(), hash, bls.DomainStateReceiver)
+}
+
+// createNewCommitmentReceipt creates a successful receipt containing NewCommitment event for the given commitment
+func createNewCommitmentReceipt(t *testing.T, commitment *contractsapi.StateSyncCommitment) *types.Receipt {
+	t.Helper()
+
+	var event contractsapi.NewCommitmentEvent
+
+	receipt := &types.Receipt{
+		Logs: []*types.Log{
+			{
+				Address: contracts.StateReceiverContract,
+				Topics: []types.Hash{
+					types.Hash(event.Sig()),
+					types.BytesToHash(commitment.StartID.Bytes()),
+					types.BytesToHash(commitment.EndID.Bytes()),
+				},
+				Data: commitment.Root.Bytes(),
+			},
+		},
+	}
+	receipt.SetStatus(types.ReceiptSuccess)
+
+	return receipt
+}
+
+func TestStateSyncManager_PostBlock_AdaptiveCommitmentSize(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit = 1_000_000
+
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.config.adaptiveCommitmentSize = true
+	s.validatorSet = vals.ToValidatorSet()
 
-	for _, evnt := range generateStateSyncEvents(t, 20, 0) {
-		require.NoError(t, s.state.StateSyncStore.insertStateSyncEvent(evnt))
+	for _, event := range generateStateSyncEvents(t, 40, 0) {
+		insertTestStateSyncEvents(t, s.state.StateSyncStore, event)
 	}
 
-	require.NoError(t, s.buildCommitment())
-	require.Len(t, s.pendingCommitments, 1)
+	// submits the last pending commitment in a block, where commitment transaction used the given gas
+	submitCommitment := func(blockNumber, gasUsed uint64) (*types.FullBlock, *CommitmentMessageSigned) {
+		t.Helper()
 
-	mockMsg := &CommitmentMessageSigned{
-		Message: &contractsapi.StateSyncCommitment{
-			StartID: s.pendingCommitments[0].StartID,
-			EndID:   s.pendingCommitments[0].EndID,
-		},
-	}
+		require.NoError(t, s.buildCommitment())
+		require.NotEmpty(t, s.pendingCommitments)
 
-	txData, err := mockMsg.EncodeAbi()
-	require.NoError(t, err)
+		commitment := &CommitmentMessageSigned{
+			Message: s.pendingCommitments[len(s.pendingCommitments)-1].StateSyncCommitment,
+		}
+		signTestCommitment(t, vals, commitment)
 
-	tx := createStateTransactionWithData(types.Address{}, txData)
+		txData, err := commitment.EncodeAbi()
+		require.NoError(t, err)
 
-	req := &PostBlockRequest{
-		FullBlock: &types.FullBlock{
+		fullBlock := &types.FullBlock{
 			Block: &types.Block{
-				Transactions: []*types.Transaction{tx},
+				Header: &types.Header{Number: blockNumber, GasLimit: gasLimit},
+				Transactions: []*types.Transaction{
+					createStateTransactionWithData(types.Address{}, txData),
+				},
 			},
-		},
+			Receipts: []*types.Receipt{{GasUsed: gasUsed}},
+		}
+
+		require.NoError(t, s.PostBlock(&PostBlockRequest{FullBlock: fullBlock}))
+
+		return fullBlock, commitment
 	}
 
-	require.NoError(t, s.PostBlock(req))
-	require.Equal(t, mockMsg.Message.EndID.Uint64()+1, s.nextCommittedIndex)
+	// commitment of max size used most of the block gas
+	fullBlock, commitment := submitCommitment(1, gasLimit*9/10)
+	require.Equal(t, uint64(maxCommitmentSize/2), s.getCommitmentSize())
 
-	for i := uint64(0); i < 10; i++ {
-		proof, err := s.state.StateSyncStore.getStateSyncProof(i)
-		require.NoError(t, err)
-		require.NotNil(t, proof)
+	// another node derives the same commitment size from the same block, regardless of its own history
+	other := newTestStateSyncManager(t, vals.GetValidator("1"))
+	other.config.adaptiveCommitmentSize = true
+	other.commitmentSize = minCommitmentSize
+	require.NoError(t, other.adaptCommitmentSize(fullBlock, commitment))
+	require.Equal(t, s.getCommitmentSize(), other.getCommitmentSize())
+
+	// commitment size survives the node restart
+	s.commitmentSize = 0
+	require.NoError(t, s.loadCommitmentSize())
+	require.Equal(t, uint64(maxCommitmentSize/2), s.getCommitmentSize())
+
+	// next commitment is shrunk
+	require.NoError(t, s.buildCommitment())
+	require.Len(t, s.pendingCommitments, 1)
+	require.Equal(t, uint64(maxCommitmentSize), s.pendingCommitments[0].StartID.Uint64())
+	require.Equal(t, uint64(maxCommitmentSize+maxCommitmentSize/2-1), s.pendingCommitments[0].EndID.Uint64())
+
+	// cheap commitment makes the commitment size grow back to max
+	submitCommitment(2, gasLimit/5)
+	require.Equal(t, uint64(maxCommitmentSize), s.getCommitmentSize())
+
+	// commitment size never goes below minimum commitment size
+	for i := uint64(3); i < 8; i++ {
+		submitCommitment(i, gasLimit)
 	}
+
+	require.Equal(t, uint64(minCommitmentSize), s.getCommitmentSize())
 }
 
 func TestStateSyncerManager_AddLog_BuildCommitments(t *testing.T) {
@@ -398,6 +2006,211 @@ func TestStateSyncerManager_AddLog_BuildCommitments(t *testing.T) {
 	require.Equal(t, uint64(3), s.pendingCommitments[3].EndID.Uint64())
 }
 
+func TestStateSyncManager_DecodeStateSyncLog_IndexedParams(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	var stateSyncedEvent contractsapi.StateSyncedEvent
+
+	sender := types.StringToAddress("0x1234")
+	receiver := types.StringToAddress("0x5678")
+	payload := []byte{0x1, 0x2, 0x3}
+
+	// only data is not indexed, so it is the only parameter encoded in the log data
+	data, err := abi.MustNewType("tuple(bytes data)").Encode(map[string]interface{}{"data": payload})
+	require.NoError(t, err)
+
+	log := &ethgo.Log{
+		Topics: []ethgo.Hash{
+			stateSyncedEvent.Sig(),
+			ethgo.BytesToHash(big.NewInt(7).Bytes()),
+			ethgo.BytesToHash(sender.Bytes()),
+			ethgo.BytesToHash(receiver.Bytes()),
+		},
+		Data: data,
+	}
+
+	event := s.decodeStateSyncLog(log)
+	require.NotNil(t, event)
+	require.Equal(t, uint64(7), event.ID.Uint64())
+	require.Equal(t, sender, event.Sender)
+	require.Equal(t, receiver, event.Receiver)
+	require.Equal(t, payload, event.Data)
+
+	// indexed parameters are not read from the log data
+	require.Nil(t, s.decodeStateSyncLog(&ethgo.Log{Topics: log.Topics[:2], Data: data}))
+}
+
+func TestStateSyncerManager_AddLog_DuplicateEvent(t *testing.T) {
+	vals := validator.NewTestValidators(t, 5)
+
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	signerMock := new(aggregateSignerMock)
+	signerMock.On("Sign", mock.Anything, mock.Anything, mock.Anything).Return([]byte{1}, nil).Once()
+	s.config.aggregateSigner = signerMock
+
+	var stateSyncedEvent contractsapi.StateSyncedEvent
+
+	data, err := abi.MustNewType("tuple(string a)").Encode([]string{"data"})
+	require.NoError(t, err)
+
+	log := &ethgo.Log{
+		Topics: []ethgo.Hash{
+			stateSyncedEvent.Sig(),
+			ethgo.BytesToHash([]byte{0x0}), // state sync index 0
+			ethgo.ZeroHash,
+			ethgo.ZeroHash,
+		},
+		Data: data,
+	}
+
+	// deliver the same log twice (e.g. after event tracker restart)
+	s.AddLog(log)
+	s.AddLog(log.Copy())
+
+	stateSyncs, err := s.state.StateSyncStore.list()
+	require.NoError(t, err)
+	require.Len(t, stateSyncs, 1)
+	require.Len(t, s.pendingCommitments, 1)
+
+	// commitment is built (and signed) only once
+	signerMock.AssertExpectations(t)
+}
+
+func TestStateSyncerManager_AddLogs_Batching(t *testing.T) {
+	const eventsCount = 1000
+
+	vals := validator.NewTestValidators(t, 5)
+
+	var stateSyncedEvent contractsapi.StateSyncedEvent
+
+	data, err := abi.MustNewType("tuple(string a)").Encode([]string{"data"})
+	require.NoError(t, err)
+
+	logs := make([]*ethgo.Log, eventsCount)
+	for i := range logs {
+		logs[i] = &ethgo.Log{
+			Topics: []ethgo.Hash{
+				stateSyncedEvent.Sig(),
+				ethgo.BytesToHash(big.NewInt(int64(i)).Bytes()), // state sync index i
+				ethgo.ZeroHash,
+				ethgo.ZeroHash,
+			},
+			Data: data,
+		}
+	}
+
+	dbWrites := func(s *stateSyncManager) int64 {
+		stats := s.state.db.Stats()
+
+		return stats.TxStats.GetWrite()
+	}
+
+	// catch up with the rootchain and return number of db writes
+	catchUp := func(s *stateSyncManager) int64 {
+		writesBefore := dbWrites(s)
+
+		s.AddLogs(logs)
+
+		stateSyncs, err := s.state.StateSyncStore.list()
+		require.NoError(t, err)
+		require.Len(t, stateSyncs, eventsCount)
+
+		return dbWrites(s) - writesBefore
+	}
+
+	// events are saved one by one
+	unbatched := newTestStateSyncManager(t, vals.GetValidator("0"))
+	unbatchedWrites := catchUp(unbatched)
+
+	// events are saved in batches
+	batched := newTestStateSyncManager(t, vals.GetValidator("0"))
+	batched.config.eventsBatchSize = 100
+
+	signerMock := new(aggregateSignerMock)
+	signerMock.On("Sign", mock.Anything, mock.Anything, mock.Anything).Return([]byte{1}, nil).Once()
+	batched.config.aggregateSigner = signerMock
+
+	batchedWrites := catchUp(batched)
+
+	require.Less(t, batchedWrites*10, unbatchedWrites)
+
+	// a single commitment is built (and signed) at the end
+	require.Len(t, batched.pendingCommitments, 1)
+	require.Equal(t, batched.config.maxCommitmentSize-1, batched.pendingCommitments[0].EndID.Uint64())
+	signerMock.AssertExpectations(t)
+
+	// already saved events are skipped
+	batched.AddLogs(logs[:10])
+	require.Len(t, batched.pendingCommitments, 1)
+}
+
+func TestStateSyncerManager_AddLogs_ConcurrentDecoding(t *testing.T) {
+	t.Parallel()
+
+	const eventsCount = 500
+
+	vals := validator.NewTestValidators(t, 5)
+
+	var stateSyncedEvent contractsapi.StateSyncedEvent
+
+	logs := make([]*ethgo.Log, 0, eventsCount+eventsCount/10)
+
+	for i := 0; i < eventsCount; i++ {
+		data, err := abi.MustNewType("tuple(string a)").Encode([]string{fmt.Sprintf("data-%d", i)})
+		require.NoError(t, err)
+
+		logs = append(logs, &ethgo.Log{
+			Topics: []ethgo.Hash{
+				stateSyncedEvent.Sig(),
+				ethgo.BytesToHash(big.NewInt(int64(i)).Bytes()), // state sync index i
+				ethgo.ZeroHash,
+				ethgo.ZeroHash,
+			},
+			Data: data,
+		})
+
+		// logs which are not state syncs are skipped
+		if i%10 == 0 {
+			logs = append(logs, &ethgo.Log{Topics: []ethgo.Hash{ethgo.ZeroHash}})
+		}
+	}
+
+	catchUp := func(decodeWorkers uint64) []*contractsapi.StateSyncedEvent {
+		s := newTestStateSyncManager(t, vals.GetValidator("0"))
+		s.config.eventsBatchSize = 100
+		s.config.eventsDecodeWorkers = decodeWorkers
+		s.config.alignCommitments = true
+
+		s.AddLogs(logs)
+
+		stateSyncs, err := s.state.StateSyncStore.list()
+		require.NoError(t, err)
+		require.Len(t, stateSyncs, eventsCount)
+
+		return stateSyncs
+	}
+
+	serial := catchUp(0)
+
+	for _, workers := range []uint64{2, 8, 1000} {
+		require.Equal(t, serial, catchUp(workers), "workers: %d", workers)
+	}
+
+	// decoded events keep the order of the logs
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.config.eventsDecodeWorkers = 8
+
+	events := s.decodeStateSyncLogs(logs)
+	require.Len(t, events, eventsCount)
+
+	for i, event := range events {
+		require.Equal(t, uint64(i), event.ID.Uint64())
+		require.Equal(t, []byte(fmt.Sprintf("data-%d", i)), event.Data)
+	}
+}
+
 func TestStateSyncerManager_EventTracker_Sync(t *testing.T) {
 	t.Parallel()
 
@@ -469,6 +2282,55 @@ func TestStateSyncManager_GetProofs(t *testing.T) {
 	require.NotEmpty(t, proof.Data)
 }
 
+func TestStateSyncManager_ProofVersion(t *testing.T) {
+	t.Parallel()
+
+	const stateSyncsCount = 5
+
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	stateSyncs := generateStateSyncEvents(t, stateSyncsCount, 1)
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, stateSyncs...)
+
+	tree, err := createMerkleTree(stateSyncs, nil)
+	require.NoError(t, err)
+
+	commitment := &CommitmentMessageSigned{
+		Message: &contractsapi.StateSyncCommitment{
+			StartID: big.NewInt(1),
+			EndID:   big.NewInt(stateSyncsCount),
+			Root:    tree.Hash(),
+		},
+	}
+
+	require.NoError(t, s.buildProofs(commitment.Message))
+
+	// built proofs are tagged with the current version
+	proof, err := s.state.StateSyncStore.getStateSyncProof(1)
+	require.NoError(t, err)
+	require.Equal(t, StateSyncProofVersion, proof.Version)
+	require.NoError(t, proof.Verify(commitment, defaultMerkleHasher))
+
+	rpcProof, err := s.GetStateSyncProof(1)
+	require.NoError(t, err)
+	require.Equal(t, StateSyncProofVersion, rpcProof.Metadata["Version"])
+
+	// proofs stored before versioning are of version 1
+	proof.Version = 0
+	require.Equal(t, uint8(1), proof.GetVersion())
+	require.NoError(t, proof.Verify(commitment, defaultMerkleHasher))
+
+	// proof of an unknown version is rejected, rather than misinterpreted
+	proof.Version = StateSyncProofVersion + 1
+	require.ErrorIs(t, proof.Verify(commitment, defaultMerkleHasher), errUnknownStateSyncProofVersion)
+
+	require.NoError(t, s.state.StateSyncStore.insertStateSyncProofs([]*StateSyncProof{proof}))
+
+	_, err = s.GetStateSyncProof(1)
+	require.ErrorIs(t, err, errUnknownStateSyncProofVersion)
+}
+
 func TestStateSyncManager_GetProofs_NoProof_NoCommitment(t *testing.T) {
 	t.Parallel()
 
@@ -509,7 +2371,7 @@ func TestStateSyncManager_GetProofs_NoProof_BuildProofs(t *testing.T) {
 	state := newTestState(t)
 	stateSyncs := generateStateSyncEvents(t, maxCommitmentSize, fromIndex)
 
-	tree, err := createMerkleTree(stateSyncs)
+	tree, err := createMerkleTree(stateSyncs, nil)
 	require.NoError(t, err)
 
 	commitment := &CommitmentMessageSigned{
@@ -521,7 +2383,7 @@ func TestStateSyncManager_GetProofs_NoProof_BuildProofs(t *testing.T) {
 	}
 
 	for _, sse := range stateSyncs {
-		require.NoError(t, state.StateSyncStore.insertStateSyncEvent(sse))
+		insertTestStateSyncEvents(t, state.StateSyncStore, sse)
 	}
 
 	require.NoError(t, state.StateSyncStore.insertCommitmentMessage(commitment))
@@ -539,6 +2401,248 @@ func TestStateSyncManager_GetProofs_NoProof_BuildProofs(t *testing.T) {
 	require.NoError(t, commitment.VerifyStateSyncProof(proof.Data, stateSync))
 }
 
+func TestStateSyncManager_ExportEpochBridgeData(t *testing.T) {
+	t.Parallel()
+
+	const (
+		epoch          = 2
+		commitmentSize = 5
+	)
+
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+	s.config.merkleHasher = sha256.New
+	s.config.merkleHashScheme = SHA256MerkleHashScheme
+
+	// commitments are submitted in epochs 0, 2, 2 and 3, while the last one is recovered (epoch is unknown)
+	commitmentEpochs := []uint64{0, epoch, epoch, epoch + 1, 0}
+
+	stateSyncs := generateStateSyncEvents(t, len(commitmentEpochs)*commitmentSize, 1)
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, stateSyncs...)
+
+	for i, commitmentEpoch := range commitmentEpochs {
+		events := stateSyncs[i*commitmentSize : (i+1)*commitmentSize]
+
+		tree, err := createMerkleTree(events, s.config.merkleHasher)
+		require.NoError(t, err)
+
+		commitment := &CommitmentMessageSigned{
+			Message: &contractsapi.StateSyncCommitment{
+				StartID: events[0].ID,
+				EndID:   events[len(events)-1].ID,
+				Root:    tree.Hash(),
+			},
+			Epoch: commitmentEpoch,
+		}
+
+		if i == len(commitmentEpochs)-1 {
+			require.NoError(t, s.state.StateSyncStore.insertCommitmentMessage(commitment))
+		} else {
+			require.NoError(t, s.state.StateSyncStore.insertSubmittedCommitmentMessage(commitment))
+		}
+
+		// proofs of the other commitments are missing, so they get generated
+		if i == 1 {
+			require.NoError(t, s.buildProofs(commitment.Message))
+		}
+	}
+
+	// exported proofs are not delivered to the proof subscribers
+	sub := s.SubscribeStateSyncProofs()
+	defer sub.Close()
+
+	export := func(epoch uint64) *EpochBridgeData {
+		t.Helper()
+
+		raw, err := s.ExportEpochBridgeData(epoch)
+		require.NoError(t, err)
+
+		var data *EpochBridgeData
+		require.NoError(t, json.Unmarshal(raw, &data))
+		require.Equal(t, epoch, data.Epoch)
+		require.Equal(t, SHA256MerkleHashScheme, data.MerkleHashScheme)
+
+		return data
+	}
+
+	// exported proofs verify against the exported commitment roots, using the exported hash scheme
+	verify := func(data *EpochBridgeData, stateSyncID uint64) {
+		t.Helper()
+
+		hasher, err := newMerkleHasher(data.MerkleHashScheme)
+		require.NoError(t, err)
+
+		for _, commitmentData := range data.Commitments {
+			require.Equal(t, data.Epoch, commitmentData.Commitment.Epoch)
+			require.Len(t, commitmentData.Proofs, commitmentSize)
+
+			for _, proof := range commitmentData.Proofs {
+				require.Equal(t, stateSyncID, proof.StateSync.ID.Uint64())
+				require.NoError(t, commitmentData.Commitment.VerifyStateSyncProofWithHasher(
+					proof.Proof, proof.StateSync, hasher))
+
+				stateSyncID++
+			}
+		}
+	}
+
+	data := export(epoch)
+	require.Len(t, data.Commitments, 2)
+	verify(data, commitmentSize+1)
+
+	// recovered commitment is not exported with the commitments of epoch 0
+	data = export(0)
+	require.Len(t, data.Commitments, 1)
+	verify(data, 1)
+
+	// epoch without commitments
+	require.Empty(t, export(epoch-1).Commitments)
+
+	require.Empty(t, sub.GetEventCh())
+
+	// generated proofs are not saved
+	proof, err := s.state.StateSyncStore.getStateSyncProof(1)
+	require.NoError(t, err)
+	require.Nil(t, proof)
+}
+
+func TestStateSyncManager_PruneExecutedProofs(t *testing.T) {
+	t.Parallel()
+
+	const lastExecutedID = 6
+
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	stateSyncs := generateStateSyncEvents(t, maxCommitmentSize, 1)
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, stateSyncs...)
+
+	tree, err := createMerkleTree(stateSyncs, nil)
+	require.NoError(t, err)
+
+	commitment := &CommitmentMessageSigned{
+		Message: &contractsapi.StateSyncCommitment{
+			StartID: big.NewInt(1),
+			EndID:   big.NewInt(maxCommitmentSize),
+			Root:    tree.Hash(),
+		},
+	}
+
+	require.NoError(t, s.state.StateSyncStore.insertCommitmentMessage(commitment))
+	require.NoError(t, s.buildProofs(commitment.Message))
+
+	systemStateMock := new(systemStateMock)
+	for id := uint64(1); id <= lastExecutedID+1; id++ {
+		systemStateMock.On("IsStateSyncExecuted", id).Return(id <= lastExecutedID, nil).Once()
+	}
+
+	require.NoError(t, s.pruneExecutedProofs(systemStateMock, maxPrunedProofsChecks))
+	systemStateMock.AssertExpectations(t)
+
+	for id := uint64(1); id <= maxCommitmentSize; id++ {
+		proof, err := s.state.StateSyncStore.getStateSyncProof(id)
+		require.NoError(t, err)
+		require.Equal(t, id <= lastExecutedID, proof == nil, "state sync %d", id)
+	}
+
+	// pruned proof is lazily rebuilt from the retained events
+	proof, err := s.GetStateSyncProof(3)
+	require.NoError(t, err)
+
+	stateSync, ok := (proof.Metadata["StateSync"]).(*contractsapi.StateSyncedEvent)
+	require.True(t, ok)
+	require.Equal(t, uint64(3), stateSync.ID.Uint64())
+	require.NoError(t, commitment.VerifyStateSyncProof(proof.Data, stateSync))
+
+	// rebuilt proofs are pruned again, and execution is checked from the persisted index, with the checks capped
+	for id := uint64(lastExecutedID + 1); id <= lastExecutedID+2; id++ {
+		systemStateMock.On("IsStateSyncExecuted", id).Return(true, nil).Once()
+	}
+
+	require.NoError(t, s.pruneExecutedProofs(systemStateMock, 2))
+	systemStateMock.AssertExpectations(t)
+
+	for id := uint64(1); id <= maxCommitmentSize; id++ {
+		proof, err := s.state.StateSyncStore.getStateSyncProof(id)
+		require.NoError(t, err)
+		require.Equal(t, id <= lastExecutedID+2, proof == nil, "state sync %d", id)
+	}
+}
+
+func TestStateSyncManager_GetCommitmentMerkleTree_Cache(t *testing.T) {
+	t.Parallel()
+
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	events := generateStateSyncEvents(t, 10, 0)
+
+	commitment, err := NewPendingCommitment(1, events, maxCommitmentSize)
+	require.NoError(t, err)
+
+	tree, err := s.getCommitmentMerkleTree(commitment.StateSyncCommitment, events)
+	require.NoError(t, err)
+	require.Equal(t, commitment.Root, tree.Hash())
+
+	// tree is reused for the same commitment
+	cachedTree, err := s.getCommitmentMerkleTree(commitment.StateSyncCommitment, events)
+	require.NoError(t, err)
+	require.Same(t, tree, cachedTree)
+
+	// tree not matching the commitment root is not cached
+	invalidCommitment := &contractsapi.StateSyncCommitment{
+		StartID: commitment.StartID,
+		EndID:   commitment.EndID,
+		Root:    types.StringToHash("0x1"),
+	}
+
+	tree, err = s.getCommitmentMerkleTree(invalidCommitment, events)
+	require.NoError(t, err)
+	require.Equal(t, commitment.Root, tree.Hash())
+
+	otherTree, err := s.getCommitmentMerkleTree(invalidCommitment, events)
+	require.NoError(t, err)
+	require.NotSame(t, tree, otherTree)
+}
+
+func BenchmarkStateSyncManager_GetCommitmentMerkleTree(b *testing.B) {
+	events := make([]*contractsapi.StateSyncedEvent, 100)
+	for i := range events {
+		events[i] = &contractsapi.StateSyncedEvent{
+			ID:     big.NewInt(int64(i)),
+			Sender: types.BytesToAddress(big.NewInt(int64(i)).Bytes()),
+			Data:   []byte{1, 2, 3},
+		}
+	}
+
+	commitment, err := NewPendingCommitment(1, events, maxCommitmentSize)
+	require.NoError(b, err)
+
+	for _, c := range []struct {
+		name     string
+		useCache bool
+	}{
+		{"no cache", false},
+		{"cache", true},
+	} {
+		b.Run(c.name, func(b *testing.B) {
+			s := newStateSyncManager(hclog.NewNullLogger(), nil, &stateSyncConfig{})
+			if !c.useCache {
+				s.merkleTreeCache = nil
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				if _, err := s.getCommitmentMerkleTree(commitment.StateSyncCommitment, events); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
 type mockTopic struct {
 	published proto.Message
 }
@@ -0,0 +1,119 @@
+package polybft
+
+import (
+	"sync"
+
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
+)
+
+// stateSyncSubscriptionBufferSize is the number of state sync events (or proofs) buffered per subscription,
+// the oldest buffered item is dropped when a new one arrives to the full buffer
+const stateSyncSubscriptionBufferSize = 128
+
+// StateSyncSubscription is the subscription to newly inserted state sync events
+type StateSyncSubscription interface {
+	// GetEventCh returns the state sync event channel, which is closed when the subscription is closed
+	GetEventCh() <-chan *contractsapi.StateSyncedEvent
+	// Close closes the subscription, and stops delivering state sync events to it
+	Close()
+}
+
+// StateSyncProofSubscription is the subscription to newly built state sync proofs
+type StateSyncProofSubscription interface {
+	// GetEventCh returns the state sync proof channel, which is closed when the subscription is closed
+	GetEventCh() <-chan *StateSyncProof
+	// Close closes the subscription, and stops delivering state sync proofs to it
+	Close()
+}
+
+var (
+	_ StateSyncSubscription      = (*streamSubscription[*contractsapi.StateSyncedEvent])(nil)
+	_ StateSyncProofSubscription = (*streamSubscription[*StateSyncProof])(nil)
+)
+
+// stateSyncEventStream delivers newly inserted state sync events to its subscribers
+type stateSyncEventStream = subscriptionStream[*contractsapi.StateSyncedEvent]
+
+// stateSyncProofStream delivers newly built state sync proofs to its subscribers
+type stateSyncProofStream = subscriptionStream[*StateSyncProof]
+
+// streamSubscription is the subscription object of a subscription stream
+type streamSubscription[T any] struct {
+	stream    *subscriptionStream[T]
+	eventCh   chan T
+	closeOnce sync.Once
+}
+
+// GetEventCh returns the channel of the subscription
+func (s *streamSubscription[T]) GetEventCh() <-chan T {
+	return s.eventCh
+}
+
+// Close closes the subscription, and stops delivering items to it
+func (s *streamSubscription[T]) Close() {
+	s.closeOnce.Do(func() {
+		s.stream.unsubscribe(s)
+	})
+}
+
+// subscriptionStream delivers items to its subscribers without blocking the publisher.
+// Zero value of the stream is ready to use.
+type subscriptionStream[T any] struct {
+	lock          sync.Mutex
+	subscriptions map[*streamSubscription[T]]struct{}
+}
+
+// subscribe creates a new subscription to the stream
+func (e *subscriptionStream[T]) subscribe() *streamSubscription[T] {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	sub := &streamSubscription[T]{
+		stream:  e,
+		eventCh: make(chan T, stateSyncSubscriptionBufferSize),
+	}
+
+	if e.subscriptions == nil {
+		e.subscriptions = make(map[*streamSubscription[T]]struct{})
+	}
+
+	e.subscriptions[sub] = struct{}{}
+
+	return sub
+}
+
+// unsubscribe removes the given subscription from the stream and closes its channel
+func (e *subscriptionStream[T]) unsubscribe(sub *streamSubscription[T]) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	delete(e.subscriptions, sub)
+	close(sub.eventCh)
+}
+
+// push delivers the given items to all subscribers.
+// If the buffer of a (slow) subscriber is full, its oldest buffered item is dropped.
+func (e *subscriptionStream[T]) push(items ...T) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for sub := range e.subscriptions {
+		for _, item := range items {
+			for {
+				select {
+				case sub.eventCh <- item:
+				default:
+					// drop the oldest item, unless the subscriber has just consumed it
+					select {
+					case <-sub.eventCh:
+					default:
+					}
+
+					continue
+				}
+
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package polybft
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/validator"
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/abi"
+)
+
+func TestStateSyncManager_SubscribeStateSyncEvents_SlowConsumer(t *testing.T) {
+	const eventsCount = stateSyncSubscriptionBufferSize + 10
+
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	sub := s.SubscribeStateSyncEvents()
+	defer sub.Close()
+
+	var stateSyncedEvent contractsapi.StateSyncedEvent
+
+	data, err := abi.MustNewType("tuple(string a)").Encode([]string{"data"})
+	require.NoError(t, err)
+
+	doneCh := make(chan struct{})
+
+	// nobody reads from the subscription while logs are added
+	go func() {
+		defer close(doneCh)
+
+		for i := 0; i < eventsCount; i++ {
+			s.AddLog(&ethgo.Log{
+				Topics: []ethgo.Hash{
+					stateSyncedEvent.Sig(),
+					ethgo.BytesToHash(big.NewInt(int64(i)).Bytes()),
+					ethgo.ZeroHash,
+					ethgo.ZeroHash,
+				},
+				Data: data,
+			})
+		}
+	}()
+
+	select {
+	case <-doneCh:
+	case <-time.After(30 * time.Second):
+		t.Fatal("AddLog is blocked by the slow subscriber")
+	}
+
+	// all events are stored, while the oldest ones are dropped from the subscription buffer
+	stateSyncs, err := s.state.StateSyncStore.list()
+	require.NoError(t, err)
+	require.Len(t, stateSyncs, eventsCount)
+
+	eventCh := sub.GetEventCh()
+	require.Len(t, eventCh, stateSyncSubscriptionBufferSize)
+
+	for i := eventsCount - stateSyncSubscriptionBufferSize; i < eventsCount; i++ {
+		event := <-eventCh
+		require.Equal(t, uint64(i), event.ID.Uint64())
+	}
+
+	// duplicate events are not delivered
+	s.AddLog(&ethgo.Log{
+		Topics: []ethgo.Hash{stateSyncedEvent.Sig(), ethgo.ZeroHash, ethgo.ZeroHash, ethgo.ZeroHash},
+		Data:   data,
+	})
+	require.Len(t, eventCh, 0)
+}
+
+func TestStateSyncSubscription_Close(t *testing.T) {
+	t.Parallel()
+
+	var stream stateSyncEventStream
+
+	sub := stream.subscribe()
+	other := stream.subscribe()
+
+	defer other.Close()
+
+	stream.push(&contractsapi.StateSyncedEvent{ID: big.NewInt(1)})
+
+	sub.Close()
+	sub.Close()
+
+	// buffered events can be consumed after close, and the channel gets closed afterwards
+	event, ok := <-sub.GetEventCh()
+	require.True(t, ok)
+	require.Equal(t, uint64(1), event.ID.Uint64())
+
+	_, ok = <-sub.GetEventCh()
+	require.False(t, ok)
+
+	// closed subscription does not receive events anymore
+	stream.push(&contractsapi.StateSyncedEvent{ID: big.NewInt(2)})
+	require.Len(t, other.GetEventCh(), 2)
+}
+
+func TestStateSyncManager_SubscribeStateSyncProofs(t *testing.T) {
+	t.Parallel()
+
+	const stateSyncsCount = 10
+
+	vals := validator.NewTestValidators(t, 5)
+	s := newTestStateSyncManager(t, vals.GetValidator("0"))
+
+	stateSyncs := generateStateSyncEvents(t, stateSyncsCount, 1)
+	insertTestStateSyncEvents(t, s.state.StateSyncStore, stateSyncs...)
+
+	tree, err := createMerkleTree(stateSyncs, nil)
+	require.NoError(t, err)
+
+	commitment := &CommitmentMessageSigned{
+		Message: &contractsapi.StateSyncCommitment{
+			StartID: big.NewInt(1),
+			EndID:   big.NewInt(stateSyncsCount),
+			Root:    tree.Hash(),
+		},
+	}
+
+	require.NoError(t, s.state.StateSyncStore.insertCommitmentMessage(commitment))
+
+	sub := s.SubscribeStateSyncProofs()
+	defer sub.Close()
+
+	require.NoError(t, s.buildProofs(commitment.Message))
+
+	// proofs are delivered in the state sync index order, and are saved as well
+	proofCh := sub.GetEventCh()
+	require.Len(t, proofCh, stateSyncsCount)
+
+	for id := uint64(1); id <= stateSyncsCount; id++ {
+		proof := <-proofCh
+		require.Equal(t, id, proof.StateSync.ID.Uint64())
+		require.NoError(t, commitment.VerifyStateSyncProof(proof.Proof, proof.StateSync))
+
+		storedProof, err := s.state.StateSyncStore.getStateSyncProof(id)
+		require.NoError(t, err)
+		require.Equal(t, proof.Proof, storedProof.Proof)
+	}
+}
@@ -1,6 +1,7 @@
 package polybft
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -10,6 +11,9 @@ import (
 	"github.com/umbracle/ethgo/contract"
 )
 
+// errStateSyncNotCommitted is returned when execution status is queried for a state sync which is not committed
+var errStateSyncNotCommitted = errors.New("state sync is not committed")
+
 // ValidatorInfo is data transfer object which holds validator information,
 // provided by smart contract
 type ValidatorInfo struct {
@@ -26,6 +30,10 @@ type SystemState interface {
 	GetEpoch() (uint64, error)
 	// GetNextCommittedIndex retrieves next committed bridge state sync index
 	GetNextCommittedIndex() (uint64, error)
+	// IsStateSyncExecuted checks whether given committed bridge state sync is executed
+	IsStateSyncExecuted(stateSyncID uint64) (bool, error)
+	// GetCommitmentByStateSyncID retrieves the commitment which contains given committed bridge state sync
+	GetCommitmentByStateSyncID(stateSyncID uint64) (*contractsapi.StateSyncCommitment, error)
 }
 
 var _ SystemState = &SystemStateImpl{}
@@ -81,3 +89,59 @@ func (s *SystemStateImpl) GetNextCommittedIndex() (uint64, error) {
 
 	return nextCommittedIndex.Uint64() + 1, nil
 }
+
+// IsStateSyncExecuted checks whether given committed bridge state sync is executed.
+// It returns an error if the given state sync is not committed yet, since it can not be executed in that case.
+func (s *SystemStateImpl) IsStateSyncExecuted(stateSyncID uint64) (bool, error) {
+	nextCommittedIndex, err := s.GetNextCommittedIndex()
+	if err != nil {
+		return false, err
+	}
+
+	// state sync ids start from 1
+	if stateSyncID == 0 || stateSyncID >= nextCommittedIndex {
+		return false, fmt.Errorf("%w: state sync id=%d, last committed id=%d",
+			errStateSyncNotCommitted, stateSyncID, nextCommittedIndex-1)
+	}
+
+	rawResult, err := s.sidechainBridgeContract.Call("processedStateSyncs", ethgo.Latest,
+		new(big.Int).SetUint64(stateSyncID))
+	if err != nil {
+		return false, err
+	}
+
+	isExecuted, isOk := rawResult["0"].(bool)
+	if !isOk {
+		return false, fmt.Errorf("failed to decode processed state sync")
+	}
+
+	return isExecuted, nil
+}
+
+// GetCommitmentByStateSyncID retrieves the commitment which contains given committed bridge state sync
+func (s *SystemStateImpl) GetCommitmentByStateSyncID(stateSyncID uint64) (*contractsapi.StateSyncCommitment, error) {
+	rawResult, err := s.sidechainBridgeContract.Call("getCommitmentByStateSyncId", ethgo.Latest,
+		new(big.Int).SetUint64(stateSyncID))
+	if err != nil {
+		return nil, err
+	}
+
+	rawCommitment, isOk := rawResult["0"].(map[string]interface{})
+	if !isOk {
+		return nil, fmt.Errorf("failed to decode commitment")
+	}
+
+	startID, isStartIDOk := rawCommitment["startId"].(*big.Int)
+	endID, isEndIDOk := rawCommitment["endId"].(*big.Int)
+	root, isRootOk := rawCommitment["root"].([32]byte)
+
+	if !isStartIDOk || !isEndIDOk || !isRootOk {
+		return nil, fmt.Errorf("failed to decode commitment fields")
+	}
+
+	return &contractsapi.StateSyncCommitment{
+		StartID: startID,
+		EndID:   endID,
+		Root:    types.Hash(root),
+	}, nil
+}
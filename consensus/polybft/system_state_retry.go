@@ -0,0 +1,58 @@
+package polybft
+
+import (
+	"errors"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/helper/common"
+)
+
+const (
+	// systemStateRetryBackoff is the delay before the first retry of a failed system state read,
+	// which is doubled before each subsequent retry
+	systemStateRetryBackoff = 50 * time.Millisecond
+
+	// systemStateMaxRetryBackoff caps the delay between retries of a failed system state read
+	systemStateMaxRetryBackoff = 500 * time.Millisecond
+)
+
+var _ SystemState = (*retryingSystemState)(nil)
+
+// retryingSystemState wraps system state, retrying reads of the epoch and the next committed index,
+// which are required to move to the next epoch and can fail due to transient errors.
+// Other reads fail deterministically (e.g. for a state sync which is not committed), so they are not retried.
+type retryingSystemState struct {
+	SystemState
+	config common.RetryConfig
+}
+
+// newRetryingSystemState creates a new instance of retryingSystemState.
+// Zero retries means a single attempt, and zero timeout means that reads are not bounded in time.
+func newRetryingSystemState(systemState SystemState, retries uint64, timeout time.Duration) *retryingSystemState {
+	return &retryingSystemState{
+		SystemState: systemState,
+		config: common.RetryConfig{
+			Retries:     retries,
+			Timeout:     timeout,
+			Backoff:     systemStateRetryBackoff,
+			MaxBackoff:  systemStateMaxRetryBackoff,
+			IsRetryable: isTransientSystemStateError,
+		},
+	}
+}
+
+// GetEpoch is an implementation of SystemState interface
+func (r *retryingSystemState) GetEpoch() (uint64, error) {
+	return common.CallWithRetry(r.config, "get epoch", r.SystemState.GetEpoch)
+}
+
+// GetNextCommittedIndex is an implementation of SystemState interface
+func (r *retryingSystemState) GetNextCommittedIndex() (uint64, error) {
+	return common.CallWithRetry(r.config, "get next committed index", r.SystemState.GetNextCommittedIndex)
+}
+
+// isTransientSystemStateError reports whether a failed system state read may succeed if it is repeated.
+// Reads of the local state are deterministic, unless they did not complete in time.
+func isTransientSystemStateError(err error) bool {
+	return errors.Is(err, common.ErrCallTimeout)
+}
@@ -1,11 +1,14 @@
 package polybft
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
 	"math/big"
 	"testing"
 
 	"github.com/0xPolygon/polygon-edge/chain"
+	"github.com/0xPolygon/polygon-edge/consensus/polybft/contractsapi"
 	"github.com/0xPolygon/polygon-edge/contracts"
 	"github.com/0xPolygon/polygon-edge/state"
 	itrie "github.com/0xPolygon/polygon-edge/state/immutable-trie"
@@ -156,3 +159,116 @@ func newTestTransition(t *testing.T, alloc map[types.Address]*chain.GenesisAccou
 
 	return transition
 }
+
+func TestSystemState_IsStateSyncExecuted(t *testing.T) {
+	t.Parallel()
+
+	const lastCommittedID = 10
+
+	lastCommittedIDMethod := contractsapi.StateReceiver.Abi.GetMethod("lastCommittedId")
+	processedStateSyncsMethod := contractsapi.StateReceiver.Abi.GetMethod("processedStateSyncs")
+	executedStateSyncs := map[uint64]bool{1: true, 2: true, 5: true}
+
+	provider := &stateProviderStub{
+		callFn: func(input []byte) ([]byte, error) {
+			switch {
+			case bytes.Equal(input[:4], lastCommittedIDMethod.ID()):
+				return lastCommittedIDMethod.Outputs.Encode([]interface{}{big.NewInt(lastCommittedID)})
+			case bytes.Equal(input[:4], processedStateSyncsMethod.ID()):
+				args, err := processedStateSyncsMethod.Inputs.Decode(input[4:])
+				require.NoError(t, err)
+
+				id := args.(map[string]interface{})["0"].(*big.Int) //nolint:forcetypeassert
+
+				return processedStateSyncsMethod.Outputs.Encode([]interface{}{executedStateSyncs[id.Uint64()]})
+			}
+
+			return nil, errors.New("unexpected call")
+		},
+	}
+
+	systemState := NewSystemState(contracts.ValidatorSetContract, contracts.StateReceiverContract, provider)
+
+	t.Run("executed state sync", func(t *testing.T) {
+		t.Parallel()
+
+		isExecuted, err := systemState.IsStateSyncExecuted(5)
+		require.NoError(t, err)
+		require.True(t, isExecuted)
+	})
+
+	t.Run("pending state sync", func(t *testing.T) {
+		t.Parallel()
+
+		isExecuted, err := systemState.IsStateSyncExecuted(lastCommittedID)
+		require.NoError(t, err)
+		require.False(t, isExecuted)
+	})
+
+	t.Run("out of range state sync", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := systemState.IsStateSyncExecuted(lastCommittedID + 1)
+		require.ErrorIs(t, err, errStateSyncNotCommitted)
+
+		_, err = systemState.IsStateSyncExecuted(0)
+		require.ErrorIs(t, err, errStateSyncNotCommitted)
+	})
+}
+
+func TestSystemState_GetCommitmentByStateSyncID(t *testing.T) {
+	t.Parallel()
+
+	getCommitmentMethod := contractsapi.StateReceiver.Abi.GetMethod("getCommitmentByStateSyncId")
+	root := types.StringToHash("0xABCD")
+
+	provider := &stateProviderStub{
+		callFn: func(input []byte) ([]byte, error) {
+			if !bytes.Equal(input[:4], getCommitmentMethod.ID()) {
+				return nil, errors.New("unexpected call")
+			}
+
+			args, err := getCommitmentMethod.Inputs.Decode(input[4:])
+			require.NoError(t, err)
+
+			id := args.(map[string]interface{})["id"].(*big.Int) //nolint:forcetypeassert
+			if id.Uint64() > 10 {
+				return nil, errors.New("state sync not committed")
+			}
+
+			return getCommitmentMethod.Outputs.Encode([]interface{}{
+				map[string]interface{}{
+					"startId": big.NewInt(6),
+					"endId":   big.NewInt(10),
+					"root":    root,
+				},
+			})
+		},
+	}
+
+	systemState := NewSystemState(contracts.ValidatorSetContract, contracts.StateReceiverContract, provider)
+
+	commitment, err := systemState.GetCommitmentByStateSyncID(8)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), commitment.StartID.Uint64())
+	require.Equal(t, uint64(10), commitment.EndID.Uint64())
+	require.Equal(t, root, commitment.Root)
+
+	_, err = systemState.GetCommitmentByStateSyncID(11)
+	require.Error(t, err)
+}
+
+var _ contract.Provider = (*stateProviderStub)(nil)
+
+// stateProviderStub is a contract provider which answers calls using the provided callback
+type stateProviderStub struct {
+	callFn func(input []byte) ([]byte, error)
+}
+
+func (s *stateProviderStub) Call(_ ethgo.Address, input []byte, _ *contract.CallOpts) ([]byte, error) {
+	return s.callFn(input)
+}
+
+func (s *stateProviderStub) Txn(ethgo.Address, ethgo.Key, []byte) (contract.Txn, error) {
+	return nil, errSendTxnUnsupported
+}
@@ -0,0 +1,55 @@
+package polybft
+
+import (
+	"encoding/json"
+)
+
+// TransportMessage represents the payload which is gossiped across the network
+type TransportMessage struct {
+	// Hash is encoded data
+	Hash []byte
+	// Message signature
+	Signature []byte
+	// From is the address of the message signer
+	From string
+	// Number of epoch
+	EpochNumber uint64
+}
+
+// transportMessageJSON is the canonical JSON representation of TransportMessage, with fields in a fixed order.
+// Field names and base64 encoded byte slices match the encoding gossiped by the nodes
+// which do not use the canonical encoding, so that both are able to decode each other's messages.
+type transportMessageJSON struct {
+	Hash        []byte `json:"Hash"`
+	Signature   []byte `json:"Signature"`
+	From        string `json:"From"`
+	EpochNumber uint64 `json:"EpochNumber"`
+}
+
+// MarshalJSON encodes the message in its canonical JSON representation,
+// so that messages with the same content are always encoded to the same bytes
+func (t *TransportMessage) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&transportMessageJSON{
+		Hash:        t.Hash,
+		Signature:   t.Signature,
+		From:        t.From,
+		EpochNumber: t.EpochNumber,
+	})
+}
+
+// UnmarshalJSON decodes the message from its canonical JSON representation
+func (t *TransportMessage) UnmarshalJSON(data []byte) error {
+	var raw transportMessageJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*t = TransportMessage{
+		Hash:        raw.Hash,
+		Signature:   raw.Signature,
+		From:        raw.From,
+		EpochNumber: raw.EpochNumber,
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package polybft
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportMessage_JSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	msg := &TransportMessage{
+		Hash:        []byte{0xAB, 0xCD, 0x01},
+		Signature:   []byte{0xFF, 0x00, 0x10},
+		From:        "0x9Ea64bAA6a1c4c5a8e6E5a5a8d2B7e5b1f4d8C2E",
+		EpochNumber: 12,
+	}
+
+	raw, err := json.Marshal(msg)
+	require.NoError(t, err)
+	require.JSONEq(t,
+		`{"Hash":"q80B","Signature":"/wAQ","From":"0x9Ea64bAA6a1c4c5a8e6E5a5a8d2B7e5b1f4d8C2E","EpochNumber":12}`,
+		string(raw))
+
+	var decoded TransportMessage
+
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	require.Equal(t, msg, &decoded)
+}
+
+func TestTransportMessage_CanonicalEncoding(t *testing.T) {
+	t.Parallel()
+
+	hash := []byte{1, 2, 3}
+
+	first := &TransportMessage{Hash: hash, Signature: []byte{4, 5}, From: "0x1", EpochNumber: 3}
+
+	second := &TransportMessage{EpochNumber: 3, From: "0x1"}
+	second.Signature = append(make([]byte, 0, 16), 4, 5)
+	second.Hash = append([]byte(nil), hash...)
+
+	firstRaw, err := json.Marshal(first)
+	require.NoError(t, err)
+
+	secondRaw, err := json.Marshal(second)
+	require.NoError(t, err)
+
+	require.Equal(t, firstRaw, secondRaw)
+	require.Equal(t, `{"Hash":"AQID","Signature":"BAU=","From":"0x1","EpochNumber":3}`, string(firstRaw))
+}
+
+func TestTransportMessage_LegacyEncoding(t *testing.T) {
+	t.Parallel()
+
+	// encoding of the message by the nodes which do not use the canonical JSON encoding
+	legacy := struct {
+		Hash        []byte
+		Signature   []byte
+		From        string
+		EpochNumber uint64
+	}{
+		Hash:        []byte{0xAB, 0xCD, 0x01, 0x02},
+		Signature:   []byte{0x30, 0x78, 0x11},
+		From:        "0x1",
+		EpochNumber: 7,
+	}
+
+	raw, err := json.Marshal(legacy)
+	require.NoError(t, err)
+
+	var decoded TransportMessage
+
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	require.Equal(t, legacy.Hash, decoded.Hash)
+	require.Equal(t, legacy.Signature, decoded.Signature)
+	require.Equal(t, legacy.From, decoded.From)
+	require.Equal(t, legacy.EpochNumber, decoded.EpochNumber)
+
+	// canonical encoding is the same as the legacy one, so that it is decoded by the nodes which do not use it
+	canonicalRaw, err := json.Marshal(&decoded)
+	require.NoError(t, err)
+	require.Equal(t, raw, canonicalRaw)
+
+	require.Error(t, json.Unmarshal([]byte(`{"Hash":"0x!!"}`), &decoded))
+}
@@ -0,0 +1,73 @@
+package polybft
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/0xPolygon/polygon-edge/types"
+)
+
+// errUptimeCounterFull represents "uptime counter exceeded the maximum number of validators" error message
+var errUptimeCounterFull = errors.New("uptime counter exceeded the maximum number of validators")
+
+// UptimeCounter counts the number of blocks signed by each validator
+type UptimeCounter struct {
+	signedBlocks  map[types.Address]uint64
+	maxValidators uint64
+}
+
+// NewUptimeCounter creates an empty uptime counter, which counts at most the given number
+// of distinct validators (zero means no limit)
+func NewUptimeCounter(maxValidators uint64) *UptimeCounter {
+	return &UptimeCounter{signedBlocks: map[types.Address]uint64{}, maxValidators: maxValidators}
+}
+
+// AddSigners increments the number of signed blocks of each of the given block signers.
+// More distinct signers than the maximum number of validators indicate corrupt data, so an error is returned
+// (and none of the given signers is counted) instead of growing the counter.
+func (u *UptimeCounter) AddSigners(signers []types.Address) error {
+	if u.maxValidators > 0 {
+		newSigners := make(map[types.Address]struct{})
+
+		for _, signer := range signers {
+			if _, exists := u.signedBlocks[signer]; !exists {
+				newSigners[signer] = struct{}{}
+			}
+		}
+
+		if uint64(len(u.signedBlocks)+len(newSigners)) > u.maxValidators {
+			return fmt.Errorf("%w: %d counted, %d new signers, at most %d allowed",
+				errUptimeCounterFull, len(u.signedBlocks), len(newSigners), u.maxValidators)
+		}
+	}
+
+	for _, signer := range signers {
+		u.signedBlocks[signer]++
+	}
+
+	return nil
+}
+
+// SignedBlocks returns the number of blocks signed by the given validator
+func (u *UptimeCounter) SignedBlocks(validator types.Address) uint64 {
+	return u.signedBlocks[validator]
+}
+
+// Fraction returns the fraction of the given total number of signable blocks signed by each counted validator.
+// Fractions are zero if there are no signable blocks, and they never exceed 1.
+func (u *UptimeCounter) Fraction(totalBlocks uint64) map[types.Address]float64 {
+	fractions := make(map[types.Address]float64, len(u.signedBlocks))
+
+	for validator, signedBlocks := range u.signedBlocks {
+		switch {
+		case totalBlocks == 0:
+			fractions[validator] = 0
+		case signedBlocks >= totalBlocks:
+			fractions[validator] = 1
+		default:
+			fractions[validator] = float64(signedBlocks) / float64(totalBlocks)
+		}
+	}
+
+	return fractions
+}
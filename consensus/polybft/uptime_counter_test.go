@@ -0,0 +1,78 @@
+package polybft
+
+import (
+	"testing"
+
+	"github.com/0xPolygon/polygon-edge/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUptimeCounter_Fraction(t *testing.T) {
+	t.Parallel()
+
+	var (
+		allSigner  = types.StringToAddress("0x1")
+		someSigner = types.StringToAddress("0x2")
+		noneSigner = types.StringToAddress("0x3")
+	)
+
+	counter := NewUptimeCounter(0)
+
+	// 4 blocks, first validator signs all of them, second one signs a half of them and third one none
+	require.NoError(t, counter.AddSigners([]types.Address{allSigner, someSigner}))
+	require.NoError(t, counter.AddSigners([]types.Address{allSigner}))
+	require.NoError(t, counter.AddSigners([]types.Address{allSigner, someSigner}))
+	require.NoError(t, counter.AddSigners([]types.Address{allSigner}))
+
+	require.Equal(t, uint64(4), counter.SignedBlocks(allSigner))
+	require.Equal(t, uint64(2), counter.SignedBlocks(someSigner))
+	require.Equal(t, uint64(0), counter.SignedBlocks(noneSigner))
+
+	fractions := counter.Fraction(4)
+	require.Len(t, fractions, 2)
+	require.Equal(t, 1.0, fractions[allSigner])
+	require.Equal(t, 0.5, fractions[someSigner])
+
+	_, ok := fractions[noneSigner]
+	require.False(t, ok)
+
+	// zero total blocks
+	fractions = counter.Fraction(0)
+	require.Len(t, fractions, 2)
+	require.Equal(t, 0.0, fractions[allSigner])
+	require.Equal(t, 0.0, fractions[someSigner])
+
+	// fraction does not exceed 1 if there are less signable blocks than signed ones
+	fractions = counter.Fraction(2)
+	require.Equal(t, 1.0, fractions[allSigner])
+	require.Equal(t, 1.0, fractions[someSigner])
+
+	// empty counter
+	require.Empty(t, NewUptimeCounter(0).Fraction(10))
+}
+
+func TestUptimeCounter_MaxValidators(t *testing.T) {
+	t.Parallel()
+
+	var (
+		first  = types.StringToAddress("0x1")
+		second = types.StringToAddress("0x2")
+		third  = types.StringToAddress("0x3")
+	)
+
+	counter := NewUptimeCounter(2)
+
+	// already counted and repeated signers are not new ones
+	require.NoError(t, counter.AddSigners([]types.Address{first, first}))
+	require.NoError(t, counter.AddSigners([]types.Address{first, second}))
+	require.NoError(t, counter.AddSigners([]types.Address{second}))
+
+	// more distinct signers than the maximum is an error rather than a silent growth
+	require.ErrorIs(t, counter.AddSigners([]types.Address{first, third}), errUptimeCounterFull)
+
+	// rejected signers are not counted at all
+	require.Equal(t, uint64(3), counter.SignedBlocks(first))
+	require.Equal(t, uint64(2), counter.SignedBlocks(second))
+	require.Zero(t, counter.SignedBlocks(third))
+	require.Len(t, counter.Fraction(3), 2)
+}
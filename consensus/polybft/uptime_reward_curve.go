@@ -0,0 +1,90 @@
+package polybft
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	// LinearUptimeRewardCurve is the name of the default uptime reward curve,
+	// which rewards validators proportionally to their uptime
+	LinearUptimeRewardCurve = "linear"
+	// ThresholdUptimeRewardCurve is the name of the uptime reward curve,
+	// which does not reward validators whose uptime is below the configured threshold
+	ThresholdUptimeRewardCurve = "threshold"
+)
+
+// UptimeRewardCurve maps validator uptime to its reward
+type UptimeRewardCurve interface {
+	// Multiplier returns reward multiplier (in range [0, 1]) for the given uptime fraction (in range [0, 1])
+	Multiplier(uptime float64) float64
+}
+
+// UptimeRewardCurveConfig is the configuration of the uptime reward curve
+type UptimeRewardCurveConfig struct {
+	// Type is the name of the uptime reward curve (linear by default)
+	Type string `json:"type"`
+	// Threshold is the minimal uptime fraction of a rewarded validator (used by threshold curve)
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+// newUptimeRewardCurve creates an uptime reward curve for the given configuration.
+// Linear uptime reward curve is used if no configuration is provided.
+func newUptimeRewardCurve(config *UptimeRewardCurveConfig) (UptimeRewardCurve, error) {
+	if config == nil {
+		return &linearUptimeRewardCurve{}, nil
+	}
+
+	switch config.Type {
+	case "", LinearUptimeRewardCurve:
+		return &linearUptimeRewardCurve{}, nil
+	case ThresholdUptimeRewardCurve:
+		if config.Threshold < 0 || config.Threshold > 1 {
+			return nil, fmt.Errorf("uptime reward curve threshold must be in range [0, 1], got %v", config.Threshold)
+		}
+
+		return &thresholdUptimeRewardCurve{threshold: config.Threshold}, nil
+	default:
+		return nil, fmt.Errorf("unsupported uptime reward curve: %s", config.Type)
+	}
+}
+
+// applyUptimeRewardCurve returns number of signed blocks reported for the validator reward distribution,
+// so that the reward of the validator is scaled by the multiplier of the given curve
+func applyUptimeRewardCurve(curve UptimeRewardCurve, signedBlocks, totalBlocks int64) int64 {
+	if totalBlocks == 0 {
+		return signedBlocks
+	}
+
+	multiplier := curve.Multiplier(float64(signedBlocks) / float64(totalBlocks))
+	multiplier = math.Max(0, math.Min(1, multiplier))
+
+	return int64(math.Round(multiplier * float64(totalBlocks)))
+}
+
+var _ UptimeRewardCurve = (*linearUptimeRewardCurve)(nil)
+
+// linearUptimeRewardCurve rewards validators proportionally to their uptime
+type linearUptimeRewardCurve struct{}
+
+// Multiplier is an implementation of UptimeRewardCurve interface
+func (l *linearUptimeRewardCurve) Multiplier(uptime float64) float64 {
+	return uptime
+}
+
+var _ UptimeRewardCurve = (*thresholdUptimeRewardCurve)(nil)
+
+// thresholdUptimeRewardCurve rewards validators proportionally to their uptime,
+// unless their uptime is below the threshold, in which case they are not rewarded at all
+type thresholdUptimeRewardCurve struct {
+	threshold float64
+}
+
+// Multiplier is an implementation of UptimeRewardCurve interface
+func (t *thresholdUptimeRewardCurve) Multiplier(uptime float64) float64 {
+	if uptime < t.threshold {
+		return 0
+	}
+
+	return uptime
+}
@@ -0,0 +1,96 @@
+package polybft
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUptimeRewardCurve_Multiplier(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		config   *UptimeRewardCurveConfig
+		expected map[float64]float64
+	}{
+		{
+			name:     "default",
+			config:   nil,
+			expected: map[float64]float64{0: 0, 0.5: 0.5, 1: 1},
+		},
+		{
+			name:     "linear",
+			config:   &UptimeRewardCurveConfig{Type: LinearUptimeRewardCurve},
+			expected: map[float64]float64{0: 0, 0.5: 0.5, 1: 1},
+		},
+		{
+			name:     "threshold above half",
+			config:   &UptimeRewardCurveConfig{Type: ThresholdUptimeRewardCurve, Threshold: 0.66},
+			expected: map[float64]float64{0: 0, 0.5: 0, 1: 1},
+		},
+		{
+			name:     "threshold at half",
+			config:   &UptimeRewardCurveConfig{Type: ThresholdUptimeRewardCurve, Threshold: 0.5},
+			expected: map[float64]float64{0: 0, 0.5: 0.5, 1: 1},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			curve, err := newUptimeRewardCurve(c.config)
+			require.NoError(t, err)
+
+			for uptime, multiplier := range c.expected {
+				require.Equal(t, multiplier, curve.Multiplier(uptime), "uptime %v", uptime)
+			}
+		})
+	}
+}
+
+func TestUptimeRewardCurve_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := newUptimeRewardCurve(&UptimeRewardCurveConfig{Type: "stepped"})
+	require.ErrorContains(t, err, "unsupported uptime reward curve: stepped")
+
+	_, err = newUptimeRewardCurve(&UptimeRewardCurveConfig{Type: ThresholdUptimeRewardCurve, Threshold: 1.5})
+	require.ErrorContains(t, err, "must be in range [0, 1]")
+}
+
+func TestUptimeRewardCurve_Apply(t *testing.T) {
+	t.Parallel()
+
+	linear := &linearUptimeRewardCurve{}
+	threshold := &thresholdUptimeRewardCurve{threshold: 0.66}
+
+	// linear curve keeps the signed blocks count as is
+	for signedBlocks := int64(0); signedBlocks <= 7; signedBlocks++ {
+		require.Equal(t, signedBlocks, applyUptimeRewardCurve(linear, signedBlocks, 7))
+	}
+
+	require.Equal(t, int64(0), applyUptimeRewardCurve(threshold, 4, 7))
+	require.Equal(t, int64(5), applyUptimeRewardCurve(threshold, 5, 7))
+	require.Equal(t, int64(0), applyUptimeRewardCurve(threshold, 0, 0))
+}
+
+func TestRewardsConfig_UptimeRewardCurveJSON(t *testing.T) {
+	t.Parallel()
+
+	config := &RewardsConfig{
+		WalletAmount:      big.NewInt(1000),
+		UptimeRewardCurve: &UptimeRewardCurveConfig{Type: ThresholdUptimeRewardCurve, Threshold: 0.5},
+	}
+
+	raw, err := json.Marshal(config)
+	require.NoError(t, err)
+
+	var decoded RewardsConfig
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	require.Equal(t, config.UptimeRewardCurve, decoded.UptimeRewardCurve)
+}
@@ -19,7 +19,11 @@ import (
 	"github.com/umbracle/fastrlp"
 )
 
-var accountSetABIType = abi.MustNewType(`tuple(tuple(address _address, uint256[4] blsKey, uint256 votingPower)[])`)
+var (
+	accountSetABIType = abi.MustNewType(`tuple(tuple(address _address, uint256[4] blsKey, uint256 votingPower)[])`)
+
+	errDuplicateValidator = errors.New("duplicate validator in account set")
+)
 
 // ValidatorMetadata represents a validator metadata (its public identity)
 type ValidatorMetadata struct {
@@ -235,12 +239,18 @@ func (as AccountSet) Copy() AccountSet {
 
 // Hash returns hash value of the AccountSet
 func (as AccountSet) Hash() (types.Hash, error) {
-	abiEncoded, err := accountSetABIType.Encode([]interface{}{as.ToAPIBinding()})
+	encoded, err := as.CanonicalEncoding()
 	if err != nil {
 		return types.ZeroHash, err
 	}
 
-	return types.BytesToHash(crypto.Keccak256(abiEncoded)), nil
+	return types.BytesToHash(crypto.Keccak256(encoded)), nil
+}
+
+// CanonicalEncoding returns stable ABI encoding of the AccountSet (addresses, BLS keys and voting powers),
+// which is used for calculating validator set checksum
+func (as AccountSet) CanonicalEncoding() ([]byte, error) {
+	return accountSetABIType.Encode([]interface{}{as.ToAPIBinding()})
 }
 
 // ToAPIBinding converts AccountSet to slice of contract api stubs to be encoded
@@ -294,6 +304,21 @@ func (as AccountSet) GetFilteredValidators(bitmap bitmap.Bitmap) (AccountSet, er
 	return filteredValidators, nil
 }
 
+// AggregatePublicKeys returns the marshaled aggregated BLS public key of the validators whose index
+// corresponds to the position in bitmap which has value set to 1 (e.g. the signers of an aggregated signature)
+func AggregatePublicKeys(validators AccountSet, bm bitmap.Bitmap) ([]byte, error) {
+	signers, err := validators.GetFilteredValidators(bm)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(signers) == 0 {
+		return nil, errors.New("no validator is set in the bitmap")
+	}
+
+	return bls.PublicKeys(signers.GetBlsKeys()).Aggregate().Marshal(), nil
+}
+
 // ApplyDelta receives ValidatorSetDelta and applies it to the values from the current AccountSet
 // (removes the ones marked for deletion and adds the one which are being added by delta)
 // Function returns new AccountSet with old and new data merged. AccountSet is immutable!
@@ -339,6 +364,38 @@ func (as AccountSet) ApplyDelta(validatorsDelta *ValidatorSetDelta) (AccountSet,
 	return validators, nil
 }
 
+// MarshalJSON marshals AccountSet to JSON, as an array of validators addresses,
+// BLS public keys (base64 encoded), voting powers and activity flags
+func (as AccountSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]*ValidatorMetadata(as))
+}
+
+// UnmarshalJSON unmarshals AccountSet from JSON, rejecting account sets with duplicate validators
+func (as *AccountSet) UnmarshalJSON(data []byte) error {
+	var validators []*ValidatorMetadata
+	if err := json.Unmarshal(data, &validators); err != nil {
+		return err
+	}
+
+	addresses := make(map[types.Address]struct{}, len(validators))
+
+	for _, v := range validators {
+		if v == nil {
+			return errors.New("validator metadata is not provided")
+		}
+
+		if _, exists := addresses[v.Address]; exists {
+			return fmt.Errorf("%w: %s", errDuplicateValidator, v.Address)
+		}
+
+		addresses[v.Address] = struct{}{}
+	}
+
+	*as = validators
+
+	return nil
+}
+
 // Marshal marshals AccountSet to JSON
 func (as AccountSet) Marshal() ([]byte, error) {
 	return json.Marshal(as)
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"math/big"
 	"testing"
 
@@ -88,6 +89,65 @@ func TestAccountSet_GetBlsKeys(t *testing.T) {
 	assert.Equal(t, key3.PublicKey(), rs[2])
 }
 
+func TestAggregatePublicKeys(t *testing.T) {
+	t.Parallel()
+
+	keys, err := bls.CreateRandomBlsKeys(5)
+	require.NoError(t, err)
+
+	validators := make(AccountSet, len(keys))
+	for i, key := range keys {
+		validators[i] = &ValidatorMetadata{BlsKey: key.PublicKey()}
+	}
+
+	message, domain := generateRandomBytes(t), generateRandomBytes(t)
+
+	// validators 0, 2 and 3 sign the message
+	bm := bitmap.Bitmap{}
+	signatures := bls.Signatures{}
+
+	for _, i := range []int{0, 2, 3} {
+		bm.Set(uint64(i))
+
+		signature, err := keys[i].Sign(message, domain)
+		require.NoError(t, err)
+
+		signatures = append(signatures, signature)
+	}
+
+	rawAggregatedKey, err := AggregatePublicKeys(validators, bm)
+	require.NoError(t, err)
+
+	aggregatedKey, err := bls.UnmarshalPublicKey(rawAggregatedKey)
+	require.NoError(t, err)
+
+	// on-chain verifier aggregates the public keys of the validators set in the bitmap,
+	// and checks the aggregated signature against it
+	expectedKey := bls.PublicKeys{keys[0].PublicKey(), keys[2].PublicKey(), keys[3].PublicKey()}.Aggregate()
+	require.Equal(t, expectedKey.ToBigInt(), aggregatedKey.ToBigInt())
+	require.True(t, signatures.Aggregate().Verify(aggregatedKey, message, domain))
+
+	// aggregated key of a different bitmap does not verify the signature
+	bm.Set(1)
+
+	rawAggregatedKey, err = AggregatePublicKeys(validators, bm)
+	require.NoError(t, err)
+
+	aggregatedKey, err = bls.UnmarshalPublicKey(rawAggregatedKey)
+	require.NoError(t, err)
+	require.False(t, signatures.Aggregate().Verify(aggregatedKey, message, domain))
+
+	// bitmap refers to a validator outside of the validator set
+	bm.Set(uint64(len(validators)))
+
+	_, err = AggregatePublicKeys(validators, bm)
+	require.Error(t, err)
+
+	// no validator is set in the bitmap
+	_, err = AggregatePublicKeys(validators, bitmap.Bitmap{})
+	require.Error(t, err)
+}
+
 func TestAccountSet_IndexContainsAddressesAndContainsNodeId(t *testing.T) {
 	t.Parallel()
 
@@ -279,3 +339,53 @@ func TestAccountSet_Hash(t *testing.T) {
 		require.NotEqual(t, types.ZeroHash, hash)
 	})
 }
+
+func TestAccountSet_JSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Round trip", func(t *testing.T) {
+		t.Parallel()
+
+		accountSet := NewTestValidatorsWithAliases(t, []string{"A", "B", "C"}, []uint64{10, 20, 30}).GetPublicIdentities()
+
+		raw, err := json.Marshal(accountSet)
+		require.NoError(t, err)
+
+		var decoded AccountSet
+		require.NoError(t, json.Unmarshal(raw, &decoded))
+		require.True(t, accountSet.Equals(decoded))
+
+		// canonical encoding is stable across the round trip
+		expectedEncoding, err := accountSet.CanonicalEncoding()
+		require.NoError(t, err)
+
+		encoding, err := decoded.CanonicalEncoding()
+		require.NoError(t, err)
+		require.Equal(t, expectedEncoding, encoding)
+	})
+
+	t.Run("Empty account set", func(t *testing.T) {
+		t.Parallel()
+
+		raw, err := json.Marshal(AccountSet{})
+		require.NoError(t, err)
+		require.Equal(t, "[]", string(raw))
+
+		var decoded AccountSet
+		require.NoError(t, json.Unmarshal(raw, &decoded))
+		require.Empty(t, decoded)
+	})
+
+	t.Run("Duplicate validators", func(t *testing.T) {
+		t.Parallel()
+
+		accountSet := NewTestValidatorsWithAliases(t, []string{"A", "B"}).GetPublicIdentities()
+		accountSet = append(accountSet, accountSet[0].Copy())
+
+		raw, err := json.Marshal(accountSet)
+		require.NoError(t, err)
+
+		var decoded AccountSet
+		require.ErrorIs(t, json.Unmarshal(raw, &decoded), errDuplicateValidator)
+	})
+}
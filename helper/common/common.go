@@ -30,8 +30,26 @@ var (
 	MaxSafeJSInt = uint64(math.Pow(2, 53) - 2)
 
 	errInvalidDuration = errors.New("invalid duration")
+
+	// ErrCallTimeout is returned by CallWithTimeout when the call does not complete within the given timeout
+	ErrCallTimeout = errors.New("call timed out")
 )
 
+// RetryConfig defines how CallWithRetry retries a failed call
+type RetryConfig struct {
+	// Retries is the number of retries after the first failed attempt (zero means a single attempt)
+	Retries uint64
+	// Timeout bounds each attempt (zero means that attempts are not bounded in time)
+	Timeout time.Duration
+	// Backoff is the delay before the first retry, which is doubled before each subsequent retry
+	// (zero means that failed attempts are retried immediately)
+	Backoff time.Duration
+	// MaxBackoff caps the delay between retries (zero means that the delay is not capped)
+	MaxBackoff time.Duration
+	// IsRetryable reports whether a failed attempt should be retried (nil means that all errors are retried)
+	IsRetryable func(error) bool
+}
+
 // RetryForever will execute a function until it completes without error
 func RetryForever(ctx context.Context, interval time.Duration, fn func(context.Context) error) {
 	_ = retry.Do(ctx, retry.NewConstant(interval), func(context.Context) error {
@@ -43,6 +61,84 @@ func RetryForever(ctx context.Context, interval time.Duration, fn func(context.C
 	})
 }
 
+// CallWithRetry invokes given call, bounding each attempt with the configured timeout,
+// and retries it until it succeeds, fails with a non retryable error or the number of retries is exhausted
+func CallWithRetry[T any](config RetryConfig, name string, call func() (T, error)) (T, error) {
+	var (
+		result   T
+		attempts uint64
+	)
+
+	backoff := retry.WithMaxRetries(config.Retries, newBackoff(config.Backoff, config.MaxBackoff))
+
+	err := retry.Do(context.Background(), backoff, func(context.Context) error {
+		var err error
+
+		attempts++
+
+		if result, err = CallWithTimeout(config.Timeout, call); err != nil {
+			if config.IsRetryable != nil && !config.IsRetryable(err) {
+				return err
+			}
+
+			return retry.RetryableError(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("%s failed after %d attempt(s): %w", name, attempts, err)
+	}
+
+	return result, nil
+}
+
+// CallWithTimeout invokes given call and returns ErrCallTimeout if it doesn't complete in the given timeout.
+// Zero timeout means that the call is not bounded in time.
+func CallWithTimeout[T any](timeout time.Duration, call func() (T, error)) (T, error) {
+	if timeout == 0 {
+		return call()
+	}
+
+	type callResult struct {
+		result T
+		err    error
+	}
+
+	// buffered, so that the goroutine of the abandoned call is able to finish
+	resultCh := make(chan callResult, 1)
+
+	go func() {
+		result, err := call()
+		resultCh <- callResult{result: result, err: err}
+	}()
+
+	select {
+	case res := <-resultCh:
+		return res.result, res.err
+	case <-time.After(timeout):
+		var empty T
+
+		return empty, fmt.Errorf("%w (timeout %s)", ErrCallTimeout, timeout)
+	}
+}
+
+// newBackoff creates an exponential backoff starting at given delay and capped at given maximum delay
+func newBackoff(delay, maxDelay time.Duration) retry.Backoff {
+	if delay == 0 {
+		return retry.BackoffFunc(func() (time.Duration, bool) {
+			return 0, false
+		})
+	}
+
+	backoff := retry.NewExponential(delay)
+	if maxDelay > 0 {
+		backoff = retry.WithCappedDuration(maxDelay, backoff)
+	}
+
+	return backoff
+}
+
 // Min returns the strictly lower number
 func Min(a, b uint64) uint64 {
 	if a < b {
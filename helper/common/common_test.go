@@ -132,3 +132,83 @@ func TestRetryForever_ReturnNilAfterFirstRun_ShouldEnd(t *testing.T) {
 	<-ctx.Done()
 	require.True(t, errors.Is(ctx.Err(), context.Canceled))
 }
+
+func TestCallWithRetry(t *testing.T) {
+	t.Parallel()
+
+	errTransient := errors.New("transient")
+	errDeterministic := errors.New("deterministic")
+
+	config := RetryConfig{
+		Retries:     3,
+		Backoff:     10 * time.Millisecond,
+		MaxBackoff:  20 * time.Millisecond,
+		IsRetryable: func(err error) bool { return errors.Is(err, errTransient) },
+	}
+
+	t.Run("retries transient errors with a capped backoff", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+		start := time.Now()
+
+		_, err := CallWithRetry(config, "call", func() (uint64, error) {
+			calls++
+
+			return 0, errTransient
+		})
+
+		require.ErrorIs(t, err, errTransient)
+		require.ErrorContains(t, err, "call failed after 4 attempt(s)")
+		require.Equal(t, 4, calls)
+		// 10ms + 20ms + 20ms, instead of 10ms + 20ms + 40ms without the cap
+		require.Less(t, time.Since(start), 70*time.Millisecond)
+	})
+
+	t.Run("does not retry non retryable errors", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+
+		_, err := CallWithRetry(config, "call", func() (uint64, error) {
+			calls++
+
+			return 0, errDeterministic
+		})
+
+		require.ErrorIs(t, err, errDeterministic)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("succeeds after a failed attempt", func(t *testing.T) {
+		t.Parallel()
+
+		calls := 0
+
+		result, err := CallWithRetry(config, "call", func() (uint64, error) {
+			calls++
+			if calls == 1 {
+				return 0, errTransient
+			}
+
+			return 5, nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), result)
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("timed out attempt", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := CallWithRetry(RetryConfig{Timeout: 10 * time.Millisecond}, "call", func() (uint64, error) {
+			time.Sleep(time.Second)
+
+			return 0, nil
+		})
+
+		require.ErrorIs(t, err, ErrCallTimeout)
+		require.ErrorContains(t, err, "call failed after 1 attempt(s)")
+	})
+}
@@ -204,6 +204,7 @@ func VerifyProofUsing(index uint64, leaf []byte, proof []types.Hash, root types.
 
 // getProofHash uses the leaf and proof to recalculate the root hash of a tree that contains given leaf
 func getProofHash(index uint64, leaf []byte, proof []types.Hash, hasher hash.Hash) []byte {
+	hasher.Reset()
 	hasher.Write(leaf)
 	computedHash := hasher.Sum(nil)
 
@@ -44,6 +44,16 @@ type Config struct {
 	Relayer bool
 
 	NumBlockConfirmations uint64
+
+	SystemStateRetries uint64
+
+	MaxParentLag              uint64
+	SyncBeforeBlockProduction bool
+
+	MaxReorgDepth   uint64
+	BlocksCacheSize int
+	MaxForks        uint64
+	MaxForkDepth    uint64
 }
 
 // Telemetry holds the config details for metric services
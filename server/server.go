@@ -109,6 +109,8 @@ func newFileLogger(config *Config) (hclog.Logger, error) {
 		Level:      config.LogLevel,
 		Output:     logFileWriter,
 		JSONFormat: config.JSONLogFormat,
+		// sub loggers can have levels of their own (e.g. polybft subsystems)
+		IndependentLevels: true,
 	}), nil
 }
 
@@ -118,6 +120,8 @@ func newCLILogger(config *Config) hclog.Logger {
 		Name:       "polygon",
 		Level:      config.LogLevel,
 		JSONFormat: config.JSONLogFormat,
+		// sub loggers can have levels of their own (e.g. polybft subsystems)
+		IndependentLevels: true,
 	})
 }
 
@@ -341,6 +345,13 @@ func NewServer(config *Config) (*Server, error) {
 		return nil, err
 	}
 
+	m.blockchain.SetMaxReorgDepth(config.MaxReorgDepth)
+	m.blockchain.SetForkTrackingLimits(config.MaxForks, config.MaxForkDepth)
+
+	if err := m.blockchain.SetBlocksCacheSize(config.BlocksCacheSize); err != nil {
+		return nil, err
+	}
+
 	// here we can provide some other configuration
 	m.gasHelper = gasprice.NewGasHelper(gasprice.DefaultGasHelperConfig, m.blockchain)
 
@@ -591,6 +602,10 @@ func (s *Server) setupConsensus() error {
 			SecretsManager:        s.secretsManager,
 			BlockTime:             uint64(blockTime.Seconds()),
 			NumBlockConfirmations: s.config.NumBlockConfirmations,
+			SystemStateRetries:    s.config.SystemStateRetries,
+			MaxParentLag:          s.config.MaxParentLag,
+
+			SyncBeforeBlockProduction: s.config.SyncBeforeBlockProduction,
 		},
 	)
 
@@ -40,6 +40,7 @@ type Executor struct {
 	state   State
 	GetHash GetHashByNumberHelper
 
+	PreHook         func(txn *Transition, msg *types.Transaction)
 	PostHook        func(txn *Transition)
 	GenesisPostHook func(*Transition) error
 }
@@ -139,13 +140,14 @@ func (e *Executor) ProcessBlock(
 		return nil, err
 	}
 
-	for _, t := range block.Transactions {
+	for i, t := range block.Transactions {
 		if t.Gas > block.Header.GasLimit {
 			continue
 		}
 
 		if err = txn.Write(t); err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to process transaction %d (hash %s) of block %d: %w",
+				i, t.Hash, block.Number(), err)
 		}
 	}
 
@@ -215,6 +217,7 @@ func (e *Executor) BeginTxn(
 
 		evm:         evm.NewEVM(),
 		precompiles: precompiled.NewPrecompiled(),
+		PreHook:     e.PreHook,
 		PostHook:    e.PostHook,
 	}
 
@@ -265,6 +268,7 @@ type Transition struct {
 	receipts []*types.Receipt
 	totalGas uint64
 
+	PreHook  func(t *Transition, msg *types.Transaction)
 	PostHook func(t *Transition)
 
 	// runtimes
@@ -424,6 +428,10 @@ func (t *Transition) Txn() *Txn {
 func (t *Transition) Apply(msg *types.Transaction) (*runtime.ExecutionResult, error) {
 	s := t.state.Snapshot()
 
+	if t.PreHook != nil {
+		t.PreHook(t, msg)
+	}
+
 	result, err := t.apply(msg)
 	if err != nil {
 		if revertErr := t.state.RevertToSnapshot(s); revertErr != nil {
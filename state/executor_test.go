@@ -5,6 +5,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -154,3 +155,54 @@ func Test_Transition_checkDynamicFees(t *testing.T) {
 		})
 	}
 }
+
+type mockState struct {
+	snapshot Snapshot
+}
+
+func (m *mockState) NewSnapshotAt(types.Hash) (Snapshot, error) {
+	return m.snapshot, nil
+}
+
+func (m *mockState) NewSnapshot() Snapshot {
+	return m.snapshot
+}
+
+func (m *mockState) GetCode(types.Hash) ([]byte, bool) {
+	return nil, false
+}
+
+func TestExecutor_ProcessBlock_InvalidTransaction(t *testing.T) {
+	t.Parallel()
+
+	config := &chain.Params{
+		ChainID: 100,
+		Forks: &chain.Forks{
+			chain.Homestead: chain.NewFork(0),
+			chain.EIP155:    chain.NewFork(0),
+		},
+	}
+
+	executor := NewExecutor(config, &mockState{snapshot: newStateWithPreState(nil)}, hclog.NewNullLogger())
+	executor.GetHash = func(*types.Header) GetHashByNumber {
+		return func(uint64) types.Hash { return types.ZeroHash }
+	}
+
+	// sender of an unsigned transaction can not be recovered
+	unsignedTx := &types.Transaction{Nonce: 0, Gas: 21000, GasPrice: big.NewInt(1), Value: big.NewInt(1)}
+	unsignedTx.ComputeHash()
+
+	block := &types.Block{
+		Header: &types.Header{Number: 7, GasLimit: 100000},
+		Transactions: []*types.Transaction{
+			{Gas: 200000}, // skipped, since it exceeds block gas limit
+			unsignedTx,
+		},
+	}
+
+	_, err := executor.ProcessBlock(types.ZeroHash, block, types.ZeroAddress)
+	require.ErrorContains(t, err, fmt.Sprintf("failed to process transaction 1 (hash %s) of block 7", unsignedTx.Hash))
+
+	var transitionErr *TransitionApplicationError
+	require.ErrorAs(t, err, &transitionErr)
+}
@@ -3,7 +3,10 @@ package itrie
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/0xPolygon/polygon-edge/state"
+	"github.com/0xPolygon/polygon-edge/types"
 )
 
 func TestState(t *testing.T) {
@@ -16,3 +19,15 @@ func buildPreState(pre state.PreStates) state.Snapshot {
 
 	return st.NewSnapshot()
 }
+
+func TestState_NewSnapshotAt_StateNotFound(t *testing.T) {
+	t.Parallel()
+
+	st := NewState(NewMemoryStorage())
+
+	_, err := st.NewSnapshotAt(types.StringToHash("0x1"))
+	require.ErrorIs(t, err, state.ErrStateNotFound)
+
+	_, err = st.NewSnapshotAt(types.EmptyRootHash)
+	require.NoError(t, err)
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -11,6 +12,9 @@ import (
 	"github.com/0xPolygon/polygon-edge/types"
 )
 
+// ErrStateNotFound is returned when a snapshot is requested at a state root which is not stored
+var ErrStateNotFound = errors.New("state not found")
+
 type State interface {
 	NewSnapshotAt(types.Hash) (Snapshot, error)
 	NewSnapshot() Snapshot
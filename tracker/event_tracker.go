@@ -18,6 +18,13 @@ type eventSubscription interface {
 	AddLog(log *ethgo.Log)
 }
 
+// eventBatchSubscription is an optional extension of eventSubscription, implemented by subscribers
+// which can process multiple logs at once (e.g. while the tracker catches up with the tracked chain)
+type eventBatchSubscription interface {
+	eventSubscription
+	AddLogs(logs []*ethgo.Log)
+}
+
 type EventTracker struct {
 	dbPath                string
 	rpcEndpoint           string
@@ -26,6 +33,36 @@ type EventTracker struct {
 	subscriber            eventSubscription
 	logger                hcf.Logger
 	numBlockConfirmations uint64 // minimal number of child blocks required for the parent block to be considered final
+	// rpcTimeout is the maximum duration of a single JSON-RPC call (zero means no timeout)
+	rpcTimeout time.Duration
+	// rpcRetries is the number of times a failed JSON-RPC call is retried
+	rpcRetries uint64
+}
+
+// EventTrackerOption is a function which customizes the EventTracker
+type EventTrackerOption func(*EventTracker)
+
+// WithRPCTimeout sets the maximum duration of a single JSON-RPC call towards the tracked chain
+func WithRPCTimeout(timeout time.Duration) EventTrackerOption {
+	return func(e *EventTracker) {
+		e.rpcTimeout = timeout
+	}
+}
+
+// WithRPCRetries sets the number of times a failed JSON-RPC call towards the tracked chain is retried
+func WithRPCRetries(retries uint64) EventTrackerOption {
+	return func(e *EventTracker) {
+		e.rpcRetries = retries
+	}
+}
+
+// WithLogLevel sets the log level of the event tracker logger (hcf.NoLevel keeps the inherited one)
+func WithLogLevel(level hcf.Level) EventTrackerOption {
+	return func(e *EventTracker) {
+		if level != hcf.NoLevel {
+			e.logger.SetLevel(level)
+		}
+	}
 }
 
 func NewEventTracker(
@@ -36,8 +73,9 @@ func NewEventTracker(
 	numBlockConfirmations uint64,
 	startBlock uint64,
 	logger hcf.Logger,
+	opts ...EventTrackerOption,
 ) *EventTracker {
-	return &EventTracker{
+	e := &EventTracker{
 		dbPath:                dbPath,
 		rpcEndpoint:           rpcEndpoint,
 		contractAddr:          contractAddr,
@@ -46,6 +84,12 @@ func NewEventTracker(
 		startBlock:            startBlock,
 		logger:                logger.Named("event_tracker"),
 	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
 }
 
 func (e *EventTracker) Start(ctx context.Context) error {
@@ -53,13 +97,17 @@ func (e *EventTracker) Start(ctx context.Context) error {
 		"contract", e.contractAddr,
 		"JSON RPC address", e.rpcEndpoint,
 		"num block confirmations", e.numBlockConfirmations,
-		"start block", e.startBlock)
+		"start block", e.startBlock,
+		"rpc timeout", e.rpcTimeout,
+		"rpc retries", e.rpcRetries)
 
-	provider, err := jsonrpc.NewClient(e.rpcEndpoint)
+	client, err := jsonrpc.NewClient(e.rpcEndpoint)
 	if err != nil {
 		return err
 	}
 
+	provider := newRPCProvider(client.Eth(), e.rpcTimeout, e.rpcRetries)
+
 	store, err := NewEventTrackerStore(e.dbPath, e.numBlockConfirmations, e.subscriber, e.logger)
 	if err != nil {
 		return err
@@ -70,7 +118,7 @@ func (e *EventTracker) Start(ctx context.Context) error {
 		blockMaxBacklog = minBlockMaxBacklog
 	}
 
-	blockTracker := blocktracker.NewBlockTracker(provider.Eth(), blocktracker.WithBlockMaxBacklog(blockMaxBacklog))
+	blockTracker := blocktracker.NewBlockTracker(provider, blocktracker.WithBlockMaxBacklog(blockMaxBacklog))
 
 	go func() {
 		<-ctx.Done()
@@ -101,7 +149,7 @@ func (e *EventTracker) Start(ctx context.Context) error {
 		return nil
 	})
 
-	tt, err := tracker.NewTracker(provider.Eth(),
+	tt, err := tracker.NewTracker(provider,
 		tracker.WithBatchSize(10),
 		tracker.WithBlockTracker(blockTracker),
 		tracker.WithStore(store),
@@ -167,8 +167,13 @@ func (b *EventTrackerStore) onNewBlock(filterHash, blockData string) error {
 	}
 
 	// notify subscriber with logs
-	for _, log := range logs {
-		b.subscriber.AddLog(log)
+	if batchSubscriber, ok := b.subscriber.(eventBatchSubscription); ok && len(logs) > 1 {
+		// multiple logs are finalized at once while the tracker is in historical sync
+		batchSubscriber.AddLogs(logs)
+	} else {
+		for _, log := range logs {
+			b.subscriber.AddLog(log)
+		}
 	}
 
 	b.logger.Debug("Event logs have been notified to a subscriber", "len", len(logs), "next", nextToProcessIdx)
@@ -180,3 +180,39 @@ func TestEventTrackerStore_SetLastBlockSubscriberNotified(t *testing.T) {
 		require.NoError(t, entry.(*Entry).saveNextToProcessIndx(0)) //nolint
 	}
 }
+
+func TestEventTrackerStore_SetLastBlockBatchSubscriberNotified(t *testing.T) {
+	t.Parallel()
+
+	const hash = "dummy_hash"
+
+	subs := &mockEventBatchSubscriber{}
+
+	tstore, closeFn := createSetupDB(subs, 1)(t)
+	defer closeFn()
+
+	entry, err := tstore.GetEntry(hash)
+	require.NoError(t, err)
+
+	require.NoError(t, entry.StoreLogs([]*ethgo.Log{
+		{BlockNumber: 1}, {BlockNumber: 2}, {BlockNumber: 3},
+	}))
+
+	setLastBlock := func(number uint64) {
+		bytes, err := (&ethgo.Block{Number: number}).MarshalJSON()
+		require.NoError(t, err)
+
+		require.NoError(t, tstore.Set(dbLastBlockPrefix+hash, hex.EncodeToString(bytes)))
+	}
+
+	// a single finalized log is delivered on its own
+	setLastBlock(2)
+	require.Len(t, subs.logs, 1)
+	require.Len(t, subs.batches, 0)
+
+	// multiple finalized logs are delivered as a batch
+	setLastBlock(4)
+	require.Len(t, subs.logs, 1)
+	require.Len(t, subs.batches, 1)
+	require.Len(t, subs.batches[0], 2)
+}
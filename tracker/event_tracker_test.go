@@ -31,6 +31,19 @@ func (m *mockEventSubscriber) AddLog(log *ethgo.Log) {
 	m.logs = append(m.logs, log)
 }
 
+// mockEventBatchSubscriber is an event subscriber which records batches of logs it was notified with
+type mockEventBatchSubscriber struct {
+	mockEventSubscriber
+	batches [][]*ethgo.Log
+}
+
+func (m *mockEventBatchSubscriber) AddLogs(logs []*ethgo.Log) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.batches = append(m.batches, logs)
+}
+
 func (m *mockEventSubscriber) len() int {
 	m.lock.RLock()
 	defer m.lock.RUnlock()
@@ -0,0 +1,67 @@
+package tracker
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/umbracle/ethgo"
+	"github.com/umbracle/ethgo/tracker"
+)
+
+var _ tracker.Provider = (*rpcProvider)(nil)
+
+// rpcProvider wraps rootchain JSON-RPC provider, bounding each call with a timeout
+// and retrying failed calls up to the configured number of times
+type rpcProvider struct {
+	provider tracker.Provider
+	timeout  time.Duration
+	retries  uint64
+}
+
+// newRPCProvider creates a new instance of rpcProvider.
+// Zero timeout means that calls are not bounded in time, and zero retries means a single attempt.
+func newRPCProvider(provider tracker.Provider, timeout time.Duration, retries uint64) *rpcProvider {
+	return &rpcProvider{
+		provider: provider,
+		timeout:  timeout,
+		retries:  retries,
+	}
+}
+
+// BlockNumber returns the number of the latest rootchain block
+func (p *rpcProvider) BlockNumber() (uint64, error) {
+	return callWithRetry(p, "eth_blockNumber", p.provider.BlockNumber)
+}
+
+// GetBlockByHash returns rootchain block by given hash
+func (p *rpcProvider) GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, error) {
+	return callWithRetry(p, "eth_getBlockByHash", func() (*ethgo.Block, error) {
+		return p.provider.GetBlockByHash(hash, full)
+	})
+}
+
+// GetBlockByNumber returns rootchain block by given number
+func (p *rpcProvider) GetBlockByNumber(i ethgo.BlockNumber, full bool) (*ethgo.Block, error) {
+	return callWithRetry(p, "eth_getBlockByNumber", func() (*ethgo.Block, error) {
+		return p.provider.GetBlockByNumber(i, full)
+	})
+}
+
+// GetLogs returns rootchain logs matching the given filter
+func (p *rpcProvider) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	return callWithRetry(p, "eth_getLogs", func() ([]*ethgo.Log, error) {
+		return p.provider.GetLogs(filter)
+	})
+}
+
+// ChainID returns rootchain chain id
+func (p *rpcProvider) ChainID() (*big.Int, error) {
+	return callWithRetry(p, "eth_chainId", p.provider.ChainID)
+}
+
+// callWithRetry invokes given call, bounding each attempt with the provider timeout,
+// and retries it until it succeeds or the number of retries is exhausted
+func callWithRetry[T any](p *rpcProvider, method string, call func() (T, error)) (T, error) {
+	return common.CallWithRetry(common.RetryConfig{Retries: p.retries, Timeout: p.timeout}, method, call)
+}
@@ -0,0 +1,114 @@
+package tracker
+
+import (
+	"errors"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xPolygon/polygon-edge/helper/common"
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+// delayedProviderMock is a stub rootchain provider which delays its responses
+// for the first failingCalls calls, and answers immediately afterwards
+type delayedProviderMock struct {
+	delay        time.Duration
+	failingCalls uint64
+	calls        atomic.Uint64
+}
+
+func (d *delayedProviderMock) respond() {
+	if d.calls.Add(1) <= d.failingCalls {
+		time.Sleep(d.delay)
+	}
+}
+
+func (d *delayedProviderMock) BlockNumber() (uint64, error) {
+	d.respond()
+
+	return 10, nil
+}
+
+func (d *delayedProviderMock) GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, error) {
+	d.respond()
+
+	return &ethgo.Block{Hash: hash}, nil
+}
+
+func (d *delayedProviderMock) GetBlockByNumber(i ethgo.BlockNumber, full bool) (*ethgo.Block, error) {
+	d.respond()
+
+	return &ethgo.Block{Number: uint64(i)}, nil
+}
+
+func (d *delayedProviderMock) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	d.respond()
+
+	return []*ethgo.Log{}, nil
+}
+
+func (d *delayedProviderMock) ChainID() (*big.Int, error) {
+	d.respond()
+
+	return big.NewInt(100), nil
+}
+
+func TestRPCProvider_Timeout(t *testing.T) {
+	t.Parallel()
+
+	stub := &delayedProviderMock{delay: time.Second, failingCalls: 10}
+	provider := newRPCProvider(stub, 50*time.Millisecond, 2)
+
+	start := time.Now()
+	_, err := provider.BlockNumber()
+
+	require.ErrorIs(t, err, common.ErrCallTimeout)
+	require.ErrorContains(t, err, "eth_blockNumber failed after 3 attempt(s)")
+	require.Less(t, time.Since(start), stub.delay)
+	require.Equal(t, uint64(3), stub.calls.Load())
+}
+
+func TestRPCProvider_RetrySucceeds(t *testing.T) {
+	t.Parallel()
+
+	stub := &delayedProviderMock{delay: time.Second, failingCalls: 1}
+	provider := newRPCProvider(stub, 50*time.Millisecond, 1)
+
+	block, err := provider.GetBlockByNumber(5, false)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), block.Number)
+	require.Equal(t, uint64(2), stub.calls.Load())
+}
+
+func TestRPCProvider_NoTimeout(t *testing.T) {
+	t.Parallel()
+
+	stub := &delayedProviderMock{delay: 100 * time.Millisecond, failingCalls: 1}
+	provider := newRPCProvider(stub, 0, 0)
+
+	chainID, err := provider.ChainID()
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(100), chainID)
+	require.Equal(t, uint64(1), stub.calls.Load())
+}
+
+func TestRPCProvider_ErrorIsRetried(t *testing.T) {
+	t.Parallel()
+
+	errProvider := errors.New("provider error")
+	calls := 0
+
+	provider := newRPCProvider(nil, time.Second, 3)
+
+	_, err := callWithRetry(provider, "eth_getLogs", func() ([]*ethgo.Log, error) {
+		calls++
+
+		return nil, errProvider
+	})
+
+	require.ErrorIs(t, err, errProvider)
+	require.Equal(t, 4, calls)
+}
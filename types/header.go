@@ -121,6 +121,25 @@ func (b *Block) ParentHash() Hash {
 	return b.Header.ParentHash
 }
 
+// Copy returns a deep copy of the block header, transactions and uncles
+func (b *Block) Copy() *Block {
+	newBlock := &Block{
+		Header:       b.Header.Copy(),
+		Transactions: make([]*Transaction, len(b.Transactions)),
+		Uncles:       make([]*Header, len(b.Uncles)),
+	}
+
+	for i, tx := range b.Transactions {
+		newBlock.Transactions[i] = tx.Copy()
+	}
+
+	for i, uncle := range b.Uncles {
+		newBlock.Uncles[i] = uncle.Copy()
+	}
+
+	return newBlock
+}
+
 func (b *Block) Body() *Body {
 	return &Body{
 		Transactions: b.Transactions,